@@ -0,0 +1,204 @@
+package main
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/opentix/platform/apps/api/models"
+	v1 "github.com/opentix/platform/apps/api/models/v1"
+	"github.com/opentix/platform/apps/api/shared"
+	"github.com/opentix/platform/packages/gohelpers/packages/database"
+	"github.com/opentix/platform/packages/gohelpers/packages/moderation"
+	"github.com/opentix/platform/packages/gohelpers/packages/query"
+)
+
+var connStr string
+
+func init() {
+	connStr = database.BuildDatabaseConnectionString()
+}
+
+// Only wallets in app.admin can use these routes.
+func requireAdmin(ctx context.Context, queries *query.Queries) error {
+	isAdmin, err := queries.IsAdmin(ctx, shared.GetUserInfo(ctx).Wallet)
+	if err != nil {
+		return shared.FromDBError(err, "Admin not found")
+	}
+	if !isAdmin {
+		return shared.Forbidden("Only admins can review photos")
+	}
+	return nil
+}
+
+// The review queue, oldest first. Status picks the queue and defaults to
+// pending. Admin routes have no legacy format, they are always sent as v1.
+func handleGetPhotos(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	status := moderation.Pending
+	if tmp, ok := request.QueryStringParameters["Status"]; ok && tmp != "" {
+		status = moderation.Status(tmp)
+	}
+	if status != moderation.Pending && status != moderation.Approved && status != moderation.Rejected {
+		return shared.CreateErrorResponse(400, "Invalid Status", request.Headers)
+	}
+
+	limit, err := shared.GetPageSizeFromRequest(request, 25)
+	if err != nil {
+		return shared.CreateErrorResponse(400, "Invalid Limit", request.Headers)
+	}
+	cursor, hasCursor, err := shared.GetCursorFromRequest(request)
+	if err != nil {
+		return shared.CreateErrorResponse(400, "Invalid Cursor", request.Headers)
+	}
+
+	conn, err := database.ConnectToDatabase(ctx, connStr)
+	if err != nil {
+		return shared.CreateErrorResponseAndLogError(500, "Failed to connect to the database", request.Headers, err)
+	}
+	defer conn.Close(ctx)
+	queries := query.New(conn)
+
+	if err := requireAdmin(ctx, queries); err != nil {
+		return shared.CreateAPIErrorResponse(err, request.Headers)
+	}
+
+	// One extra row to know if there is another page
+	items, err := queries.AdminGetMediaByStatus(ctx, query.AdminGetMediaByStatusParams{
+		Status:  string(status),
+		Column2: hasCursor,
+		Column3: cursor.Pk,
+		Column4: limit + 1,
+	})
+	if err != nil {
+		return shared.CreateAPIErrorResponse(shared.FromDBError(err, "Photo not found"), request.Headers)
+	}
+	total, err := queries.AdminCountMediaByStatus(ctx, string(status))
+	if err != nil {
+		return shared.CreateAPIErrorResponse(shared.FromDBError(err, "Photo not found"), request.Headers)
+	}
+
+	var nextCursor string
+	if len(items) > int(limit) {
+		items = items[:limit]
+		nextCursor = shared.EncodeCursor(shared.Cursor{Pk: items[len(items)-1].Pk})
+	}
+	return shared.CreateJSONResponse(200, v1.NewPage(v1.Map(items, v1.NewMedia), nextCursor, total), request.Headers)
+}
+
+// Approving a photo makes it the cover of a gallery that has none. Rejecting
+// the cover makes the next approved photo the cover, or clears the record's
+// photo when there is none.
+func handlePutReview(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	var req models.PhotoReviewPutBodyParams
+	if err := shared.DecodeAndValidate(request.Body, &req); err != nil {
+		return shared.CreateValidationErrorResponse(err, request.Headers)
+	}
+	id, err := uuid.Parse(shared.PathParam(request, "id"))
+	if err != nil {
+		return shared.CreateErrorResponse(400, "Invalid uuid", request.Headers)
+	}
+
+	conn, err := shared.ConnectToDatabaseAsUser(ctx, connStr)
+	if err != nil {
+		return shared.CreateErrorResponseAndLogError(500, "Failed to connect to the database", request.Headers, err)
+	}
+	defer conn.Close(ctx)
+	queries := query.New(conn)
+
+	if err := requireAdmin(ctx, queries); err != nil {
+		return shared.CreateAPIErrorResponse(err, request.Headers)
+	}
+
+	media, err := queries.AdminGetMediaByUuid(ctx, id)
+	if err != nil {
+		return shared.CreateAPIErrorResponse(shared.FromDBError(err, "Photo not found"), request.Headers)
+	}
+	// The upload lambda sets the status once it is done, which would undo the
+	// review
+	if media.Status == string(moderation.Pending) && !media.Photo.Valid {
+		return shared.CreateAPIErrorResponse(shared.Conflict("Photo is still being processed"), request.Headers)
+	}
+	if media.Status == string(moderation.Rejected) && req.Status == string(moderation.Approved) {
+		return shared.CreateAPIErrorResponse(shared.Conflict("Rejected photos can't be approved, they have been removed"), request.Headers)
+	}
+
+	// Rejecting clears the cover flag along with the photo
+	wasCover := media.IsCover
+	reason := pgtype.Text{String: req.Reason, Valid: req.Reason != ""}
+	err = database.InTransaction(ctx, conn, func(queries *query.Queries) error {
+		reviewed, err := queries.AdminReviewMedia(ctx, query.AdminReviewMediaParams{
+			Pk:               media.Pk,
+			Status:           req.Status,
+			ModerationReason: reason,
+			ReviewedBy:       pgtype.Text{String: shared.GetUserInfo(ctx).Wallet, Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+		media = reviewed
+
+		if req.Block && req.Status == string(moderation.Rejected) && media.PhotoHash.Valid {
+			err := queries.AdminBlockPhotoHash(ctx, query.AdminBlockPhotoHashParams{Hash: media.PhotoHash.Int64, Reason: reason})
+			if err != nil {
+				return err
+			}
+		}
+
+		if req.Status == string(moderation.Approved) {
+			if media.IsCover {
+				return nil
+			}
+			claimed, err := queries.InsecureClaimMediaCover(ctx, media.Pk)
+			if errors.Is(err, pgx.ErrNoRows) {
+				// The gallery already has a cover
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			media = claimed
+			return syncCover(ctx, queries, media)
+		}
+
+		if !wasCover {
+			return nil
+		}
+		items, err := queries.GetMedia(ctx, query.GetMediaParams{Event: media.Event, Venue: media.Venue})
+		if err != nil {
+			return err
+		}
+		for _, item := range items {
+			if item.Photo.Valid && item.Status == string(moderation.Approved) {
+				if _, err := queries.SetMediaCover(ctx, item.Pk); err != nil {
+					return err
+				}
+				break
+			}
+		}
+		return syncCover(ctx, queries, media)
+	})
+	if err != nil {
+		return shared.CreateAPIErrorResponse(shared.FromDBError(err, "Photo not found"), request.Headers)
+	}
+	return shared.CreateJSONResponse(200, v1.NewMedia(media), request.Headers)
+}
+
+// Copies the cover of media's gallery onto its event or venue.
+func syncCover(ctx context.Context, queries *query.Queries, media query.AppMedia) error {
+	if media.Event.Valid {
+		return queries.InsecureSyncEventCover(ctx, media.Event.Int32)
+	}
+	return queries.InsecureSyncVenueCover(ctx, media.Venue.Int32)
+}
+
+func main() {
+	router := shared.NewRouter(shared.DefaultMiddleware...)
+	router.GET("/admin/photos", handleGetPhotos, shared.Auth)
+	router.PUT("/admin/photos/{id}/review", handlePutReview, shared.Auth)
+	lambda.Start(router.Serve)
+}
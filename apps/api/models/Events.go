@@ -0,0 +1,54 @@
+package models
+
+import (
+	"github.com/opentix/platform/packages/gohelpers/packages/query"
+)
+
+type EventPostBodyParams struct {
+	Vendor       int32   `json:"-"`
+	Venue        int32   `json:"Venue" validate:"required,min=1"`
+	Name         string  `json:"Name" validate:"required,max=200"`
+	Type         string  `json:"Type" validate:"required,oneof=Concert|Sporting Event|Festival|Conference/Seminar|Other"`
+	Time         string  `json:"EventDatetime" validate:"required,datetime"`
+	EndTime      string  `json:"EndDatetime" validate:"required,datetime"`
+	Description  string  `json:"Description" validate:"required,max=5000"`
+	Disclaimer   string  `json:"Disclaimer" validate:"required,max=2000"`
+	Basecost     float64 `json:"Basecost" validate:"required,min=0"`
+	NumUnique    int32   `json:"NumUnique" validate:"required,min=0"`
+	NumGa        int32   `json:"NumGa" validate:"required,min=0"`
+	AllowOverlap bool    `json:"AllowOverlap"`
+}
+
+type EventPatchBodyParams struct {
+	Pk              int32  `json:"Pk" validate:"required,min=1"`
+	Venue           int32  `json:"Venue" validate:"min=1"`
+	Name            string `json:"Name" validate:"max=200"`
+	Type            string `json:"Type" validate:"oneof=Concert|Sporting Event|Festival|Conference/Seminar|Other"`
+	Time            string `json:"EventDatetime" validate:"datetime"`
+	EndTime         string `json:"EndDatetime" validate:"datetime"`
+	Description     string `json:"Description" validate:"max=5000"`
+	Disclaimer      string `json:"Disclaimer" validate:"max=2000"`
+	TransactionHash string `json:"TransactionHash"`
+	AllowOverlap    bool   `json:"AllowOverlap"`
+}
+
+// Both counts are the new totals rather than the change, so retrying is harmless
+type EventCapacityPatchBodyParams struct {
+	NumUnique int32 `json:"NumUnique" validate:"required,min=0"`
+	NumGa     int32 `json:"NumGa" validate:"required,min=0"`
+}
+
+// Sent with a 409 when an event would overlap others at its venue. Repeating
+// the request with AllowOverlap set schedules it anyway.
+type EventOverlapResponse struct {
+	Message string                                `json:"message"`
+	Code    string                                `json:"code"`
+	Events  []query.VendorGetOverlappingEventsRow `json:"events"`
+}
+
+// An event's public page before v1, the event with the photos of its gallery
+// that are done processing
+type UserEventPage struct {
+	query.UserGetEventByUuidRow
+	Gallery []query.AppMedia
+}
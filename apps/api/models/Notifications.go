@@ -0,0 +1,14 @@
+package models
+
+// Replaces the signed in user's notification preferences. Email is where
+// email notifications go, leaving it out or null turns them off.
+type NotificationPreferencesPutBodyParams struct {
+	Email        string `json:"Email" validate:"max=254"`
+	EmailEnabled bool   `json:"EmailEnabled" validate:"required"`
+	PushEnabled  bool   `json:"PushEnabled" validate:"required"`
+}
+
+// An Expo push token of one of the mobile apps, as ExponentPushToken[...]
+type PushTokenBodyParams struct {
+	Token string `json:"Token" validate:"required,max=255"`
+}
@@ -0,0 +1,54 @@
+package models
+
+import (
+	"github.com/google/uuid"
+
+	"github.com/opentix/platform/packages/gohelpers/packages/storage"
+)
+
+type PostVendorPhotoRequest struct {
+	RecordID string `json:"ID" validate:"required,uuid"`
+	// No longer used, object keys are generated by the API. Accepted so older
+	// clients keep working.
+	Filename string `json:"Filename" validate:"max=255"`
+	// The upload must be sent with this Content-Type and exactly this many bytes
+	ContentType string `json:"ContentType" validate:"required,oneof=image/jpeg|image/png|image/gif|image/webp"`
+	Size        int64  `json:"Size" validate:"required,min=1,max=10485760"`
+	Caption     string `json:"Caption" validate:"max=500"`
+	AltText     string `json:"AltText" validate:"max=500"`
+}
+
+// MediaID is the gallery item the upload is added to once it is processed. The
+// first photo in a gallery becomes its cover.
+type PostVendorPhotoResponse struct {
+	Request   storage.PresignedUpload
+	ObjectKey string
+	MediaID   uuid.UUID
+}
+
+type DeleteVendorPhotoRequest struct {
+	RecordID string `json:"ID" validate:"required,uuid"`
+}
+
+// The new order of a gallery. IDs must list every photo in it exactly once.
+type GalleryOrderPutBodyParams struct {
+	IDs []string `json:"IDs" validate:"required"`
+}
+
+type GalleryCoverPutBodyParams struct {
+	ID string `json:"ID" validate:"required,uuid"`
+}
+
+// Caption and AltText are cleared by sending null.
+type GalleryPhotoPatchBodyParams struct {
+	Caption string `json:"Caption" validate:"max=500"`
+	AltText string `json:"AltText" validate:"max=500"`
+}
+
+// An admin's review of a gallery photo. Rejecting removes the photo's variants,
+// and Block also rejects any later upload of the same photo.
+type PhotoReviewPutBodyParams struct {
+	Status string `json:"Status" validate:"required,oneof=approved|rejected"`
+	Reason string `json:"Reason" validate:"max=500"`
+	Block  bool   `json:"Block"`
+}
@@ -0,0 +1,13 @@
+package models
+
+type TicketCheckBodyParams struct {
+	Event    string `json:"Event" validate:"required,uuid"`
+	TicketID int    `json:"TicketID" validate:"required,min=0"`
+}
+
+type TicketCreatePostBodyParams struct {
+	Event     string `json:"Event" validate:"required,uuid"`
+	Contract  string `json:"Contract" validate:"required"`
+	TicketMin int    `json:"TicketMin" validate:"required,min=0"`
+	TicketMax int    `json:"TicketMax" validate:"required,min=0"`
+}
@@ -0,0 +1,5 @@
+package models
+
+type PostPatchVendorIdRequestBody struct {
+	Name string `json:"Name" validate:"required,max=200"`
+}
@@ -0,0 +1,51 @@
+package models
+
+import (
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/opentix/platform/packages/gohelpers/packages/query"
+)
+
+// StateName and CountryName are derived from the codes, and the coordinates are
+// geocoded from the postal code.
+type VenuePostBodyParams struct {
+	Name          string        `json:"Name" validate:"required,max=200"`
+	StreetAddress string        `json:"StreetAddress" validate:"required,max=200"`
+	Zip           string        `json:"Zip" validate:"max=10"`
+	City          string        `json:"City" validate:"required,max=100"`
+	StateCode     string        `json:"StateCode" validate:"required"`
+	StateName     string        `json:"-"`
+	CountryCode   string        `json:"CountryCode" validate:"required"`
+	CountryName   string        `json:"-"`
+	NumUnique     int32         `json:"NumUnique" validate:"required,min=0"`
+	NumGa         int32         `json:"NumGa" validate:"required,min=0"`
+	Vendor        int32         `json:"-"`
+	Latitude      pgtype.Float8 `json:"-"`
+	Longitude     pgtype.Float8 `json:"-"`
+}
+
+type VenuePatchBodyParams struct {
+	Pk            int32  `json:"Pk" validate:"required,min=1"`
+	Name          string `json:"Name" validate:"max=200"`
+	StreetAddress string `json:"StreetAddress" validate:"max=200"`
+	Zip           string `json:"Zip" validate:"max=10"`
+	City          string `json:"City" validate:"max=100"`
+	StateCode     string `json:"StateCode"`
+	StateName     string `json:"-"`
+	CountryCode   string `json:"CountryCode"`
+	CountryName   string `json:"-"`
+}
+
+// Both counts are the new totals rather than the change, so retrying is harmless
+type VenueCapacityPatchBodyParams struct {
+	NumUnique int32 `json:"NumUnique" validate:"required,min=0"`
+	NumGa     int32 `json:"NumGa" validate:"required,min=0"`
+}
+
+// Sent with a 409 when upcoming events at the venue need more tickets than the
+// new capacity allows. Those events have to be reduced or moved first.
+type VenueCapacityConflictResponse struct {
+	Message string                                     `json:"message"`
+	Code    string                                     `json:"code"`
+	Events  []query.VendorGetVenueCapacityConflictsRow `json:"events"`
+}
@@ -0,0 +1,64 @@
+package v1
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/opentix/platform/packages/gohelpers/packages/query"
+)
+
+// Ticket sales and attendance of a vendor's events starting between From and
+// To, null when the range is open on that side.
+type VendorAnalytics struct {
+	From   *time.Time        `json:"from"`
+	To     *time.Time        `json:"to"`
+	Totals TicketStats       `json:"totals"`
+	Venues []VenueAnalytics  `json:"venues"`
+	Events []EventAnalytics  `json:"events"`
+	Hourly []HourlyAnalytics `json:"hourly"`
+}
+
+// Sold counts tickets whose purchase was recorded or that were checked in.
+// Revenue is the base cost of each sold ticket. NoShowRate is the share of
+// tickets sold for events that have ended which were never checked in, null
+// when no such ticket was sold.
+type TicketStats struct {
+	Minted     int64    `json:"minted"`
+	Sold       int64    `json:"sold"`
+	CheckedIn  int64    `json:"checkedIn"`
+	NoShowRate *float64 `json:"noShowRate"`
+	Revenue    float64  `json:"revenue"`
+}
+
+type EventAnalytics struct {
+	EventID       uuid.UUID   `json:"eventId"`
+	VenueID       uuid.UUID   `json:"venueId"`
+	Name          string      `json:"name"`
+	EventDatetime time.Time   `json:"eventDatetime"`
+	EndDatetime   time.Time   `json:"endDatetime"`
+	Basecost      float64     `json:"basecost"`
+	Tickets       TicketStats `json:"tickets"`
+}
+
+type VenueAnalytics struct {
+	VenueID uuid.UUID   `json:"venueId"`
+	Name    string      `json:"name"`
+	Tickets TicketStats `json:"tickets"`
+}
+
+// Tickets sold and checked in during the hour starting at Hour. Hours without
+// either are left out.
+type HourlyAnalytics struct {
+	Hour      time.Time `json:"hour"`
+	Sold      int64     `json:"sold"`
+	CheckedIn int64     `json:"checkedIn"`
+}
+
+func NewHourlyAnalytics(h query.VendorGetTicketActivityByHourRow) HourlyAnalytics {
+	return HourlyAnalytics{
+		Hour:      timestamp(h.Hour),
+		Sold:      h.Sold,
+		CheckedIn: h.CheckedIn,
+	}
+}
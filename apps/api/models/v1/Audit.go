@@ -0,0 +1,31 @@
+package v1
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/opentix/platform/packages/gohelpers/packages/query"
+)
+
+// One change to a record. Before and After hold the changed columns as stored,
+// the whole row for inserts and deletes. Actor is null for changes made by the
+// platform itself, like tickets recorded after minting.
+type AuditEntry struct {
+	Table     string          `json:"table"`
+	Action    string          `json:"action"`
+	Actor     *string         `json:"actor"`
+	Before    json.RawMessage `json:"before"`
+	After     json.RawMessage `json:"after"`
+	ChangedAt time.Time       `json:"changedAt"`
+}
+
+func NewAuditEntry(a query.AppAuditLog) AuditEntry {
+	return AuditEntry{
+		Table:     a.TableName,
+		Action:    a.Action,
+		Actor:     text(a.Actor),
+		Before:    a.Before,
+		After:     a.After,
+		ChangedAt: timestamp(a.ChangedAt),
+	}
+}
@@ -0,0 +1,34 @@
+package v1
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/opentix/platform/packages/gohelpers/packages/query"
+)
+
+// The time ranges a venue is booked between From and To. Events are listed
+// even if they only partly fall in the window, and may overlap each other when
+// they were scheduled with AllowOverlap.
+type VenueCalendar struct {
+	From   time.Time       `json:"from"`
+	To     time.Time       `json:"to"`
+	Events []CalendarEntry `json:"events"`
+}
+
+type CalendarEntry struct {
+	EventID uuid.UUID `json:"eventId"`
+	Name    string    `json:"name"`
+	Start   time.Time `json:"start"`
+	End     time.Time `json:"end"`
+}
+
+func NewCalendarEntry(e query.VendorGetVenueCalendarRow) CalendarEntry {
+	return CalendarEntry{
+		EventID: e.ID,
+		Name:    e.Name,
+		Start:   timestamp(e.EventDatetime),
+		End:     timestamp(e.EndDatetime),
+	}
+}
@@ -0,0 +1,137 @@
+package v1
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/opentix/platform/packages/gohelpers/packages/query"
+)
+
+type Event struct {
+	ID              uuid.UUID  `json:"id"`
+	VenueID         uuid.UUID  `json:"venueId"`
+	Name            string     `json:"name"`
+	Type            string     `json:"type"`
+	EventDatetime   time.Time  `json:"eventDatetime"`
+	EndDatetime     time.Time  `json:"endDatetime"`
+	Description     string     `json:"description"`
+	Disclaimer      *string    `json:"disclaimer"`
+	Basecost        float64    `json:"basecost"`
+	NumUnique       int32      `json:"numUnique"`
+	NumGa           int32      `json:"numGa"`
+	Photo           *string    `json:"photo"`
+	PhotoThumbnail  *string    `json:"photoThumbnail"`
+	PhotoCard       *string    `json:"photoCard"`
+	TransactionHash *string    `json:"transactionHash"`
+	Version         int32      `json:"version"`
+	UpdatedAt       time.Time  `json:"updatedAt"`
+	CancelledAt     *time.Time `json:"cancelledAt"`
+}
+
+// An event as listed in user searches. Distance is only set for location
+// searches.
+type EventSummary struct {
+	ID            uuid.UUID `json:"id"`
+	Name          string    `json:"name"`
+	Type          string    `json:"type"`
+	EventDatetime time.Time `json:"eventDatetime"`
+	VenueName     string    `json:"venueName"`
+	StateCode     string    `json:"stateCode"`
+	CountryCode   string    `json:"countryCode"`
+	Photo         *string   `json:"photo"`
+	Snippet       string    `json:"snippet"`
+	Distance      *float64  `json:"distance"`
+}
+
+// The public page of an event.
+type EventDetails struct {
+	ID            uuid.UUID    `json:"id"`
+	Name          string       `json:"name"`
+	Type          string       `json:"type"`
+	EventDatetime time.Time    `json:"eventDatetime"`
+	EndDatetime   time.Time    `json:"endDatetime"`
+	Description   string       `json:"description"`
+	Disclaimer    *string      `json:"disclaimer"`
+	Basecost      float64      `json:"basecost"`
+	NumUnique     int32        `json:"numUnique"`
+	NumGa         int32        `json:"numGa"`
+	Photo         *string      `json:"photo"`
+	VendorName    string       `json:"vendorName"`
+	Venue         VenueAddress `json:"venue"`
+	Gallery       []Media      `json:"gallery"`
+	// Set once the organizer cancels the event, tickets can't be bought after
+	CancelledAt *time.Time `json:"cancelledAt"`
+}
+
+// The venue's pk isn't exposed, so its uuid is passed in by the caller.
+func NewEvent(e query.AppEvent, venueID uuid.UUID) Event {
+	return Event{
+		ID:              e.ID,
+		VenueID:         venueID,
+		Name:            e.Name,
+		Type:            e.Type,
+		EventDatetime:   timestamp(e.EventDatetime),
+		EndDatetime:     timestamp(e.EndDatetime),
+		Description:     e.Description,
+		Disclaimer:      text(e.Disclaimer),
+		Basecost:        e.Basecost,
+		NumUnique:       e.NumUnique,
+		NumGa:           e.NumGa,
+		Photo:           text(e.Photo),
+		PhotoThumbnail:  text(e.PhotoThumbnail),
+		PhotoCard:       text(e.PhotoCard),
+		TransactionHash: text(e.TransactionHash),
+		Version:         e.Version,
+		UpdatedAt:       timestamp(e.UpdatedAt),
+		CancelledAt:     optionalTimestamp(e.CancelledAt),
+	}
+}
+
+func NewEventSummary(e query.UserGetEventsPaginatedRow, withDistance bool) EventSummary {
+	summary := EventSummary{
+		ID:            e.ID,
+		Name:          e.Name,
+		Type:          e.Type,
+		EventDatetime: timestamp(e.EventDatetime),
+		VenueName:     e.Venuename,
+		StateCode:     e.StateCode,
+		CountryCode:   e.CountryCode,
+		Photo:         text(e.Photo),
+		Snippet:       e.Snippet,
+	}
+	if withDistance {
+		summary.Distance = &e.Distance
+	}
+	return summary
+}
+
+// The gallery only has photos that are done processing, in order.
+func NewEventDetails(e query.UserGetEventByUuidRow, gallery []query.AppMedia) EventDetails {
+	return EventDetails{
+		ID:            e.ID,
+		Name:          e.Eventname,
+		Type:          e.Type,
+		EventDatetime: timestamp(e.EventDatetime),
+		EndDatetime:   timestamp(e.EndDatetime),
+		Description:   e.Description,
+		Disclaimer:    text(e.Disclaimer),
+		Basecost:      e.Basecost,
+		NumUnique:     e.NumUnique,
+		NumGa:         e.NumGa,
+		Photo:         text(e.Eventphoto),
+		VendorName:    e.Vendorname,
+		Venue: VenueAddress{
+			Name:          e.Venuename,
+			StreetAddress: e.StreetAddress,
+			Zip:           e.Zip,
+			City:          e.City,
+			StateCode:     e.StateCode,
+			CountryCode:   e.CountryCode,
+			CountryName:   e.CountryName,
+			Photo:         text(e.Venuephoto),
+		},
+		Gallery:     NewGallery(gallery).Photos,
+		CancelledAt: optionalTimestamp(e.CancelledAt),
+	}
+}
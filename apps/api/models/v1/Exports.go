@@ -0,0 +1,37 @@
+package v1
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/opentix/platform/packages/gohelpers/packages/query"
+)
+
+// An export left for the ticket export job. DownloadURL is set once it is
+// done, and stops working shortly after it is sent, so fetch the export again
+// for a new one.
+type TicketExport struct {
+	ID          uuid.UUID  `json:"id"`
+	Format      string     `json:"format"`
+	Status      string     `json:"status"`
+	Error       *string    `json:"error"`
+	DownloadURL *string    `json:"downloadUrl"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	CompletedAt *time.Time `json:"completedAt"`
+}
+
+func NewTicketExport(e query.AppTicketExport, downloadURL string) TicketExport {
+	export := TicketExport{
+		ID:          e.ID,
+		Format:      e.Format,
+		Status:      e.Status,
+		Error:       text(e.Error),
+		CreatedAt:   timestamp(e.CreatedAt),
+		CompletedAt: optionalTimestamp(e.CompletedAt),
+	}
+	if downloadURL != "" {
+		export.DownloadURL = &downloadURL
+	}
+	return export
+}
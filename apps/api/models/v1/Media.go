@@ -0,0 +1,55 @@
+package v1
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/opentix/platform/packages/gohelpers/packages/query"
+)
+
+// A photo in an event's or venue's gallery. Photo and its variants are null
+// while the upload is still being processed, and once it has been rejected.
+// Status is "pending" until the photo is approved or rejected, and only
+// approved photos are shown to users.
+type Media struct {
+	ID               uuid.UUID `json:"id"`
+	Position         int32     `json:"position"`
+	Caption          *string   `json:"caption"`
+	AltText          *string   `json:"altText"`
+	Photo            *string   `json:"photo"`
+	PhotoThumbnail   *string   `json:"photoThumbnail"`
+	PhotoCard        *string   `json:"photoCard"`
+	Cover            bool      `json:"cover"`
+	Status           string    `json:"status"`
+	ModerationReason *string   `json:"moderationReason"`
+	CreatedAt        time.Time `json:"createdAt"`
+}
+
+type Gallery struct {
+	Photos []Media `json:"photos"`
+}
+
+func NewMedia(m query.AppMedia) Media {
+	return Media{
+		ID:               m.ID,
+		Position:         m.Position,
+		Caption:          text(m.Caption),
+		AltText:          text(m.AltText),
+		Photo:            text(m.Photo),
+		PhotoThumbnail:   text(m.PhotoThumbnail),
+		PhotoCard:        text(m.PhotoCard),
+		Cover:            m.IsCover,
+		Status:           m.Status,
+		ModerationReason: text(m.ModerationReason),
+		CreatedAt:        timestamp(m.CreatedAt),
+	}
+}
+
+func NewGallery(items []query.AppMedia) Gallery {
+	gallery := Gallery{Photos: make([]Media, len(items))}
+	for i, item := range items {
+		gallery.Photos[i] = NewMedia(item)
+	}
+	return gallery
+}
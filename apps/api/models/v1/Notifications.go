@@ -0,0 +1,21 @@
+package v1
+
+import (
+	"github.com/opentix/platform/packages/gohelpers/packages/query"
+)
+
+// Which notifications a user gets. Users without preferences get every
+// notification they have an address for.
+type NotificationPreferences struct {
+	Email        *string `json:"email"`
+	EmailEnabled bool    `json:"emailEnabled"`
+	PushEnabled  bool    `json:"pushEnabled"`
+}
+
+func NewNotificationPreferences(p query.AppNotificationPreference) NotificationPreferences {
+	return NotificationPreferences{
+		Email:        text(p.Email),
+		EmailEnabled: p.EmailEnabled,
+		PushEnabled:  p.PushEnabled,
+	}
+}
@@ -0,0 +1,28 @@
+package v1
+
+// A page of a listing. NextCursor is null on the last page.
+type Page[T any] struct {
+	Items      []T     `json:"items"`
+	NextCursor *string `json:"nextCursor"`
+	TotalCount int64   `json:"totalCount"`
+}
+
+func NewPage[T any](items []T, nextCursor string, totalCount int64) Page[T] {
+	page := Page[T]{
+		Items:      items,
+		TotalCount: totalCount,
+	}
+	if nextCursor != "" {
+		page.NextCursor = &nextCursor
+	}
+	return page
+}
+
+// Applies mapper to every row, returning an empty rather than nil slice.
+func Map[Row any, T any](rows []Row, mapper func(Row) T) []T {
+	items := make([]T, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, mapper(row))
+	}
+	return items
+}
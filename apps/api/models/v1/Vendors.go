@@ -0,0 +1,21 @@
+package v1
+
+import (
+	"github.com/google/uuid"
+
+	"github.com/opentix/platform/packages/gohelpers/packages/query"
+)
+
+type Vendor struct {
+	ID     uuid.UUID `json:"id"`
+	Name   string    `json:"name"`
+	Wallet string    `json:"wallet"`
+}
+
+func NewVendor(v query.AppVendor) Vendor {
+	return Vendor{
+		ID:     v.ID,
+		Name:   v.Name,
+		Wallet: v.Wallet,
+	}
+}
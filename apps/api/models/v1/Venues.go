@@ -0,0 +1,77 @@
+package v1
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/opentix/platform/packages/gohelpers/packages/query"
+)
+
+type Venue struct {
+	ID             uuid.UUID `json:"id"`
+	Name           string    `json:"name"`
+	StreetAddress  string    `json:"streetAddress"`
+	Zip            string    `json:"zip"`
+	City           string    `json:"city"`
+	StateCode      string    `json:"stateCode"`
+	StateName      string    `json:"stateName"`
+	CountryCode    string    `json:"countryCode"`
+	CountryName    string    `json:"countryName"`
+	NumUnique      int32     `json:"numUnique"`
+	NumGa          int32     `json:"numGa"`
+	Photo          *string   `json:"photo"`
+	PhotoThumbnail *string   `json:"photoThumbnail"`
+	PhotoCard      *string   `json:"photoCard"`
+	Latitude       *float64  `json:"latitude"`
+	Longitude      *float64  `json:"longitude"`
+	Version        int32     `json:"version"`
+	UpdatedAt      time.Time `json:"updatedAt"`
+}
+
+type VenueSummary struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+}
+
+// Where an event takes place, as shown on its public page.
+type VenueAddress struct {
+	Name          string  `json:"name"`
+	StreetAddress string  `json:"streetAddress"`
+	Zip           string  `json:"zip"`
+	City          string  `json:"city"`
+	StateCode     string  `json:"stateCode"`
+	CountryCode   string  `json:"countryCode"`
+	CountryName   string  `json:"countryName"`
+	Photo         *string `json:"photo"`
+}
+
+func NewVenue(v query.AppVenue) Venue {
+	return Venue{
+		ID:             v.ID,
+		Name:           v.Name,
+		StreetAddress:  v.StreetAddress,
+		Zip:            v.Zip,
+		City:           v.City,
+		StateCode:      v.StateCode,
+		StateName:      v.StateName,
+		CountryCode:    v.CountryCode,
+		CountryName:    v.CountryName,
+		NumUnique:      v.NumUnique,
+		NumGa:          v.NumGa,
+		Photo:          text(v.Photo),
+		PhotoThumbnail: text(v.PhotoThumbnail),
+		PhotoCard:      text(v.PhotoCard),
+		Latitude:       float8(v.Latitude),
+		Longitude:      float8(v.Longitude),
+		Version:        v.Version,
+		UpdatedAt:      timestamp(v.UpdatedAt),
+	}
+}
+
+func NewVenueSummary(v query.VendorGetAllVenuesRow) VenueSummary {
+	return VenueSummary{
+		ID:   v.ID,
+		Name: v.Name,
+	}
+}
@@ -83,19 +83,19 @@ func handleGet(ctx context.Context, request events.APIGatewayProxyRequest) (even
 
 	req, err := http.NewRequest(http.MethodGet, requestURL, nil)
 	if err != nil {
-		return shared.CreateErrorResponse(500, "Could not create request to "+requestURL, request.Headers)
+		return shared.CreateAPIErrorResponse(shared.Internal("Could not create request to "+requestURL, err), request.Headers)
 	}
 
 	req.Header.Set("OK-ACCESS-KEY", OKLINK_API_KEY)
 
 	res, err := http.DefaultClient.Do(req)
 	if err != nil {
-		return shared.CreateErrorResponse(500, "Unable to perform get request to "+requestURL, request.Headers)
+		return shared.CreateAPIErrorResponse(shared.Upstream("Unable to perform get request to "+requestURL, err), request.Headers)
 	}
 	defer res.Body.Close()
 	resBody, err := io.ReadAll(res.Body)
 	if err != nil {
-		return shared.CreateErrorResponse(500, "Unable to read response body from "+requestURL, request.Headers)
+		return shared.CreateAPIErrorResponse(shared.Upstream("Unable to read response body from "+requestURL, err), request.Headers)
 	}
 	return events.APIGatewayProxyResponse{
 		StatusCode: 200,
@@ -104,19 +104,8 @@ func handleGet(ctx context.Context, request events.APIGatewayProxyRequest) (even
 	}, nil
 }
 
-func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
-	if request.HTTPMethod == "GET" {
-		return handleGet(ctx, request)
-	} else {
-		body, _ := json.Marshal(map[string]string{"message": "Method Not Allowed."})
-		return events.APIGatewayProxyResponse{
-			StatusCode: 405,
-			Body:       string(body),
-			Headers:    shared.GetResponseHeaders(request.Headers),
-		}, nil
-	}
-}
-
 func main() {
-	lambda.Start(Handler)
+	router := shared.NewRouter(shared.DefaultMiddleware...)
+	router.GET("/oklink", handleGet)
+	lambda.Start(router.Serve)
 }
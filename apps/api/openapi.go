@@ -0,0 +1,36 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+
+	"github.com/opentix/platform/apps/api/shared"
+	"github.com/opentix/platform/apps/api/spec"
+)
+
+var document []byte
+
+func init() {
+	var err error
+	document, err = json.Marshal(spec.Document())
+	if err != nil {
+		panic(err)
+	}
+}
+
+func handleGet(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	return events.APIGatewayProxyResponse{
+		StatusCode: 200,
+		Body:       string(document),
+		Headers:    shared.GetResponseHeaders(request.Headers),
+	}, nil
+}
+
+func main() {
+	router := shared.NewRouter(shared.DefaultMiddleware...)
+	router.GET("/openapi", handleGet)
+	lambda.Start(router.Serve)
+}
@@ -7,8 +7,12 @@ import (
 	"github.com/aws/aws-lambda-go/events"
 )
 
+type ErrorResponse struct {
+	Message string `json:"message"`
+}
+
 func CreateErrorResponse(statusCode int, message string, requestHeaders map[string]string) (events.APIGatewayProxyResponse, error) {
-	body, _ := json.Marshal(map[string]string{"message": message})
+	body, _ := json.Marshal(ErrorResponse{Message: message})
 	return events.APIGatewayProxyResponse{
 		StatusCode: statusCode,
 		Body:       string(body),
@@ -20,3 +24,16 @@ func CreateErrorResponseAndLogError(statusCode int, message string, requestHeade
 	log.Printf("Error: %v:  %v\n", message, err)
 	return CreateErrorResponse(statusCode, message, requestHeaders)
 }
+
+// Marshals body into a JSON response.
+func CreateJSONResponse(statusCode int, body interface{}, requestHeaders map[string]string) (events.APIGatewayProxyResponse, error) {
+	responseBody, err := json.Marshal(body)
+	if err != nil {
+		return CreateErrorResponseAndLogError(500, "Failed to marshal response", requestHeaders, err)
+	}
+	return events.APIGatewayProxyResponse{
+		StatusCode: statusCode,
+		Body:       string(responseBody),
+		Headers:    GetResponseHeaders(requestHeaders),
+	}, nil
+}
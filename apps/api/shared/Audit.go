@@ -0,0 +1,74 @@
+package shared
+
+import (
+	"context"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/jackc/pgx/v5"
+
+	v1 "github.com/opentix/platform/apps/api/models/v1"
+	"github.com/opentix/platform/packages/gohelpers/packages/database"
+	"github.com/opentix/platform/packages/gohelpers/packages/query"
+)
+
+// Connects to the database with the signed in wallet recorded as the actor of
+// every change made over the connection, see app.audit_log. Handlers that write
+// should connect with this, behind the Auth middleware.
+func ConnectToDatabaseAsUser(ctx context.Context, connStr string) (*pgx.Conn, error) {
+	conn, err := database.ConnectToDatabase(ctx, connStr)
+	if err != nil {
+		return nil, err
+	}
+	if err := query.New(conn).SetAuditActor(ctx, GetUserInfo(ctx).Wallet); err != nil {
+		conn.Close(ctx)
+		return nil, err
+	}
+	return conn, nil
+}
+
+// Responds with a page of the changes to a record of the signed in vendor,
+// newest first. table is the record's table name in app.audit_log, and the
+// history of an event includes the changes to its tickets.
+//
+// History has no legacy format, it is always sent as v1 whatever the version.
+func CreateHistoryResponse(ctx context.Context, queries *query.Queries, table string, recordPk int32, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	wallet := GetUserInfo(ctx).Wallet
+
+	limit, err := GetPageSizeFromRequest(request, 25)
+	if err != nil {
+		return CreateErrorResponse(400, "Invalid Limit", request.Headers)
+	}
+	cursor, hasCursor, err := GetCursorFromRequest(request)
+	if err != nil {
+		return CreateErrorResponse(400, "Invalid Cursor", request.Headers)
+	}
+
+	// Fetch one extra row to know if there is another page
+	entries, err := queries.VendorGetRecordHistory(ctx, query.VendorGetRecordHistoryParams{
+		Column1: limit + 1,
+		Wallet:  wallet,
+		Column3: table,
+		Column4: recordPk,
+		Column5: hasCursor,
+		Column6: cursor.Pk,
+	})
+	if err != nil {
+		return CreateAPIErrorResponse(FromDBError(err, "History not found"), request.Headers)
+	}
+
+	total, err := queries.VendorCountRecordHistory(ctx, query.VendorCountRecordHistoryParams{
+		Wallet:  wallet,
+		Column2: table,
+		Column3: recordPk,
+	})
+	if err != nil {
+		return CreateAPIErrorResponse(FromDBError(err, "History not found"), request.Headers)
+	}
+
+	var nextCursor string
+	if len(entries) > int(limit) {
+		entries = entries[:limit]
+		nextCursor = EncodeCursor(Cursor{Pk: entries[len(entries)-1].Pk})
+	}
+	return CreateJSONResponse(200, v1.NewPage(v1.Map(entries, v1.NewAuditEntry), nextCursor, total), request.Headers)
+}
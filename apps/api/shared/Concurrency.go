@@ -0,0 +1,47 @@
+package shared
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// The ETag of a record at the given row version. Events and venues bump their
+// version on every update, so a PATCH sent with the ETag as If-Match only
+// applies if nobody changed the record since it was read.
+func ETag(version int32) string {
+	return `"` + strconv.Itoa(int(version)) + `"`
+}
+
+// Returns the row version a PATCH expects from its If-Match header, or 0 when it
+// doesn't send one or sends * and so applies whatever the current version.
+func GetIfMatchVersion(request events.APIGatewayProxyRequest) (int32, error) {
+	raw := strings.TrimSpace(getHeader(request.Headers, "If-Match"))
+	if raw == "" || raw == "*" {
+		return 0, nil
+	}
+	tag := strings.Trim(strings.TrimPrefix(raw, "W/"), `"`)
+	version, err := strconv.ParseInt(tag, 10, 32)
+	if err != nil || version < 1 {
+		return 0, BadRequest("If-Match must be an ETag returned by this API")
+	}
+	return int32(version), nil
+}
+
+// Responds with body and its ETag.
+func CreateTaggedJSONResponse(statusCode int, body interface{}, version int32, requestHeaders map[string]string) (events.APIGatewayProxyResponse, error) {
+	response, err := CreateJSONResponse(statusCode, body, requestHeaders)
+	if response.StatusCode == statusCode {
+		response.Headers["ETag"] = ETag(version)
+	}
+	return response, err
+}
+
+// Responds to a PATCH whose If-Match is out of date. The 412 carries the
+// current ETag so the client can refetch and retry.
+func CreatePreconditionFailedResponse(currentVersion int32, requestHeaders map[string]string) (events.APIGatewayProxyResponse, error) {
+	response, err := CreateAPIErrorResponse(PreconditionFailed("The record was changed since it was read, fetch it again before updating"), requestHeaders)
+	response.Headers["ETag"] = ETag(currentVersion)
+	return response, err
+}
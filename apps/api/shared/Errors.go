@@ -0,0 +1,122 @@
+package shared
+
+import (
+	"errors"
+	"log"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type ErrorKind int
+
+const (
+	ErrInternal ErrorKind = iota
+	ErrNotFound
+	ErrForbidden
+	ErrConflict
+	ErrValidation
+	ErrUpstream
+	ErrPrecondition
+)
+
+var errorKindStatus = map[ErrorKind]int{
+	ErrInternal:     500,
+	ErrNotFound:     404,
+	ErrForbidden:    403,
+	ErrConflict:     409,
+	ErrValidation:   400,
+	ErrUpstream:     502,
+	ErrPrecondition: 412,
+}
+
+// A domain error that knows which HTTP status it should be reported with.
+// Message is sent to the client, Err is only logged.
+type APIError struct {
+	Kind    ErrorKind
+	Message string
+	Err     error
+}
+
+func (e *APIError) Error() string {
+	if e.Err == nil {
+		return e.Message
+	}
+	return e.Message + ": " + e.Err.Error()
+}
+
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+func (e *APIError) StatusCode() int {
+	return errorKindStatus[e.Kind]
+}
+
+func NotFound(message string) *APIError {
+	return &APIError{Kind: ErrNotFound, Message: message}
+}
+
+func Forbidden(message string) *APIError {
+	return &APIError{Kind: ErrForbidden, Message: message}
+}
+
+func Conflict(message string) *APIError {
+	return &APIError{Kind: ErrConflict, Message: message}
+}
+
+func PreconditionFailed(message string) *APIError {
+	return &APIError{Kind: ErrPrecondition, Message: message}
+}
+
+func BadRequest(message string) *APIError {
+	return &APIError{Kind: ErrValidation, Message: message}
+}
+
+func Upstream(message string, err error) *APIError {
+	return &APIError{Kind: ErrUpstream, Message: message, Err: err}
+}
+
+func Internal(message string, err error) *APIError {
+	return &APIError{Kind: ErrInternal, Message: message, Err: err}
+}
+
+// Classifies an error returned by a query. No rows becomes a not found error
+// carrying notFoundMessage, constraint violations become conflict or validation
+// errors, and anything else is an internal error.
+func FromDBError(err error, notFoundMessage string) *APIError {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return &APIError{Kind: ErrNotFound, Message: notFoundMessage, Err: err}
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case "23505": // unique_violation
+			return &APIError{Kind: ErrConflict, Message: "Record already exists", Err: err}
+		case "23503", "23514", "22001": // foreign_key_violation, check_violation, string_data_right_truncation
+			return &APIError{Kind: ErrValidation, Message: "Request violates a database constraint", Err: err}
+		}
+	}
+	return &APIError{Kind: ErrInternal, Message: "Unable to get response from database or malformed query", Err: err}
+}
+
+// Writes the response for any error a handler ends up with. APIErrors use their
+// kind's status, ValidationErrors keep their field errors and anything else is
+// reported as an internal error. Server side failures are logged.
+func CreateAPIErrorResponse(err error, requestHeaders map[string]string) (events.APIGatewayProxyResponse, error) {
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		return CreateValidationErrorResponse(validationErr, requestHeaders)
+	}
+
+	apiErr := &APIError{Kind: ErrInternal, Message: "Internal server error", Err: err}
+	errors.As(err, &apiErr)
+
+	status := apiErr.StatusCode()
+	if status >= 500 {
+		log.Printf("Error: %v:  %v\n", apiErr.Message, apiErr.Err)
+	}
+	return CreateErrorResponse(status, apiErr.Message, requestHeaders)
+}
@@ -0,0 +1,137 @@
+package shared
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/opentix/platform/apps/api/models"
+	"github.com/opentix/platform/packages/gohelpers/packages/query"
+)
+
+var EndBeforeStartError = []FieldError{{
+	Field:   "EndDatetime",
+	Code:    "min",
+	Message: "EndDatetime must be after EventDatetime",
+}}
+
+// GetOwnedEvent looks up an event and checks it belongs to the vendor with the
+// given wallet, so a missing event and someone else's event can be told apart.
+func GetOwnedEvent(ctx context.Context, queries *query.Queries, wallet string, id uuid.UUID) (query.AppEvent, error) {
+	event, err := queries.GetEventByUuid(ctx, id)
+	if err != nil {
+		return event, FromDBError(err, "Event not found")
+	}
+	vendor, err := queries.GetVendorByWallet(ctx, wallet)
+	if err != nil {
+		return event, FromDBError(err, "Vendor does not exist")
+	}
+	if event.Vendor != vendor.Pk {
+		return event, Forbidden("Vendor does not own event")
+	}
+	return event, nil
+}
+
+// Returned by PrepareEvent when the event would overlap others at its venue and
+// AllowOverlap isn't set. Overlaps can be allowed, so they aren't a constraint
+// on the table; events are checked with their venue locked instead.
+type OverlapError struct {
+	Events []query.VendorGetOverlappingEventsRow
+}
+
+func (e *OverlapError) Error() string {
+	return fmt.Sprintf("event overlaps %d other events at the venue", len(e.Events))
+}
+
+// PrepareEvent checks a decoded EventPostBodyParams against the rules of
+// creating an event: it must end after it starts, be at one of the vendor's
+// venues, fit the venue's capacity and not overlap other events there unless
+// AllowOverlap is set. params.Vendor must already be set. Returns the arguments
+// for CreateEvent along with the venue.
+//
+// The venue row is locked for the rest of the transaction, so call it in the
+// transaction that creates the event; its capacity can't change in between.
+func PrepareEvent(ctx context.Context, queries *query.Queries, wallet string, params models.EventPostBodyParams) (query.CreateEventParams, query.AppVenue, error) {
+	var venue query.AppVenue
+	var tstamp, endTstamp pgtype.Timestamptz
+
+	t, err := time.Parse(DatetimeLayout, strings.Trim(params.Time, "\x0d\x0a"))
+	tstamp.Scan(t)
+	if err != nil || !tstamp.Valid {
+		return query.CreateEventParams{}, venue, BadRequest("Unable to parse timestamp for event_datetime")
+	}
+	t, err = time.Parse(DatetimeLayout, strings.Trim(params.EndTime, "\x0d\x0a"))
+	endTstamp.Scan(t)
+	if err != nil || !endTstamp.Valid {
+		return query.CreateEventParams{}, venue, BadRequest("Unable to parse timestamp for end_datetime")
+	}
+	if !endTstamp.Time.After(tstamp.Time) {
+		return query.CreateEventParams{}, venue, &ValidationError{StatusCode: 422, Code: "validation_failed", Message: "One or more fields are invalid", Errors: EndBeforeStartError}
+	}
+
+	dbVendor, err := queries.CheckVenueVendorStatus(ctx, params.Venue)
+	if err != nil {
+		return query.CreateEventParams{}, venue, FromDBError(err, "Venue not found")
+	}
+	if dbVendor != params.Vendor {
+		return query.CreateEventParams{}, venue, Forbidden("You are not authorized to create an event for that venue")
+	}
+
+	venue, err = queries.VendorGetVenueByPkForUpdate(ctx, query.VendorGetVenueByPkForUpdateParams{
+		Pk:     params.Venue,
+		Wallet: wallet,
+	})
+	if err != nil {
+		return query.CreateEventParams{}, venue, FromDBError(err, "Venue not found")
+	}
+	var capacityErrors []FieldError
+	if venue.NumUnique < params.NumUnique {
+		capacityErrors = append(capacityErrors, FieldError{
+			Field:   "NumUnique",
+			Code:    "max",
+			Message: fmt.Sprintf("NumUnique must be at most the venue's %d unique seats", venue.NumUnique),
+		})
+	}
+	if venue.NumGa < params.NumGa {
+		capacityErrors = append(capacityErrors, FieldError{
+			Field:   "NumGa",
+			Code:    "max",
+			Message: fmt.Sprintf("NumGa must be at most the venue's %d general admission tickets", venue.NumGa),
+		})
+	}
+	if len(capacityErrors) > 0 {
+		return query.CreateEventParams{}, venue, &ValidationError{StatusCode: 422, Code: "validation_failed", Message: "Number of tickets exceeds venue capacity.", Errors: capacityErrors}
+	}
+
+	if !params.AllowOverlap {
+		overlaps, err := queries.VendorGetOverlappingEvents(ctx, query.VendorGetOverlappingEventsParams{
+			Venue:   params.Venue,
+			Column2: tstamp,
+			Column3: endTstamp,
+		})
+		if err != nil {
+			return query.CreateEventParams{}, venue, FromDBError(err, "Venue not found")
+		}
+		if len(overlaps) > 0 {
+			return query.CreateEventParams{}, venue, &OverlapError{Events: overlaps}
+		}
+	}
+
+	return query.CreateEventParams{
+		Vendor:        params.Vendor,
+		Venue:         params.Venue,
+		Name:          params.Name,
+		Type:          params.Type,
+		EventDatetime: tstamp,
+		EndDatetime:   endTstamp,
+		Description:   params.Description,
+		Disclaimer:    pgtype.Text{String: params.Disclaimer, Valid: true},
+		Basecost:      params.Basecost,
+		NumUnique:     params.NumUnique,
+		NumGa:         params.NumGa,
+	}, venue, nil
+}
@@ -0,0 +1,34 @@
+package shared
+
+import (
+	"encoding/json"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// A problem with a single request field. Field is the JSON name of the field in
+// the request body so forms can highlight the offending input.
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+type FieldErrorResponse struct {
+	Message string       `json:"message"`
+	Code    string       `json:"code"`
+	Errors  []FieldError `json:"errors"`
+}
+
+func CreateFieldErrorResponse(statusCode int, code string, message string, fieldErrors []FieldError, requestHeaders map[string]string) (events.APIGatewayProxyResponse, error) {
+	body, _ := json.Marshal(FieldErrorResponse{
+		Message: message,
+		Code:    code,
+		Errors:  fieldErrors,
+	})
+	return events.APIGatewayProxyResponse{
+		StatusCode: statusCode,
+		Body:       string(body),
+		Headers:    GetResponseHeaders(requestHeaders),
+	}, nil
+}
@@ -0,0 +1,122 @@
+package shared
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log"
+	"strconv"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/opentix/platform/packages/gohelpers/packages/database"
+	"github.com/opentix/platform/packages/gohelpers/packages/query"
+)
+
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// Set on responses replayed from an earlier request with the same key
+const IdempotentReplayedHeader = "Idempotent-Replayed"
+
+const maxIdempotencyKeyLength = 255
+
+// Makes a route safe to retry. A request sent with an Idempotency-Key header is
+// handled once per vendor and key, and repeating it within 24 hours returns the
+// stored response instead of running the handler again. Reusing a key for a
+// different request, or while the first is still running, gets a 409.
+//
+// Server errors aren't stored, so a request that failed with a 5xx can be
+// retried with the same key. Must come after Auth since keys are per wallet.
+func Idempotency(connStr string) Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+			key := getHeader(request.Headers, IdempotencyKeyHeader)
+			if key == "" {
+				return next(ctx, request)
+			}
+			if len(key) > maxIdempotencyKeyLength {
+				return CreateErrorResponse(400, "Idempotency-Key must be at most 255 characters", request.Headers)
+			}
+			wallet := GetUserInfo(ctx).Wallet
+
+			conn, err := database.ConnectToDatabase(ctx, connStr)
+			if err != nil {
+				return CreateErrorResponseAndLogError(500, "Failed to connect to the database", request.Headers, err)
+			}
+			defer conn.Close(ctx)
+			queries := query.New(conn)
+
+			fingerprint := requestFingerprint(ctx, request)
+			_, err = queries.ClaimIdempotencyKey(ctx, query.ClaimIdempotencyKeyParams{
+				Wallet:      wallet,
+				Key:         key,
+				Fingerprint: fingerprint,
+			})
+			if errors.Is(err, pgx.ErrNoRows) {
+				return replayIdempotentResponse(ctx, queries, wallet, key, fingerprint, request)
+			}
+			if err != nil {
+				return CreateAPIErrorResponse(FromDBError(err, "Idempotency key not found"), request.Headers)
+			}
+
+			response, err := next(ctx, request)
+			if err != nil || response.StatusCode >= 500 {
+				releaseErr := queries.ReleaseIdempotencyKey(ctx, query.ReleaseIdempotencyKeyParams{Wallet: wallet, Key: key})
+				if releaseErr != nil {
+					log.Printf("Failed to release idempotency key %q: %v", key, releaseErr)
+				}
+				return response, err
+			}
+
+			err = queries.CompleteIdempotencyKey(ctx, query.CompleteIdempotencyKeyParams{
+				Wallet:       wallet,
+				Key:          key,
+				StatusCode:   pgtype.Int4{Int32: int32(response.StatusCode), Valid: true},
+				ResponseBody: pgtype.Text{String: response.Body, Valid: true},
+			})
+			if err != nil {
+				// The request went through, so report its result even though a
+				// retry won't be recognized
+				log.Printf("Failed to store response for idempotency key %q: %v", key, err)
+			}
+			return response, nil
+		}
+	}
+}
+
+func replayIdempotentResponse(ctx context.Context, queries *query.Queries, wallet string, key string, fingerprint string, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	stored, err := queries.GetIdempotencyKey(ctx, query.GetIdempotencyKeyParams{Wallet: wallet, Key: key})
+	if err != nil {
+		// Released by a failed request since the claim, it is safe to retry
+		return CreateAPIErrorResponse(FromDBError(err, "Idempotency key not found, retry the request"), request.Headers)
+	}
+	if stored.Fingerprint != fingerprint {
+		return CreateAPIErrorResponse(Conflict("Idempotency-Key was already used for a different request"), request.Headers)
+	}
+	if !stored.StatusCode.Valid {
+		return CreateAPIErrorResponse(Conflict("A request with this Idempotency-Key is still being processed"), request.Headers)
+	}
+
+	headers := GetResponseHeaders(request.Headers)
+	headers[IdempotentReplayedHeader] = "true"
+	return events.APIGatewayProxyResponse{
+		StatusCode: int(stored.StatusCode.Int32),
+		Body:       stored.ResponseBody.String,
+		Headers:    headers,
+	}, nil
+}
+
+// Identifies what a request asks for, so a key reused for something else can be
+// told apart from a retry. The version is included since it changes the
+// response a retry would be given.
+func requestFingerprint(ctx context.Context, request events.APIGatewayProxyRequest) string {
+	h := sha256.New()
+	for _, part := range []string{request.HTTPMethod, request.Path, strconv.Itoa(GetVersion(ctx)), request.Body} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,188 @@
+package shared
+
+import (
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/google/uuid"
+)
+
+// The outcome of a bulk import. Valid counts the rows that were created, or on a
+// dry run the ones that would have been, since nothing is saved then. Rows are
+// reported in the order they were sent, and Row counts from 1 without a CSV
+// header.
+type ImportResponse struct {
+	DryRun  bool        `json:"dryRun"`
+	Valid   int         `json:"valid"`
+	Invalid int         `json:"invalid"`
+	Rows    []ImportRow `json:"rows"`
+}
+
+// Status is created, valid on a dry run or invalid. ID is the new record's, and
+// invalid rows carry the message, code and field errors the single POST would
+// have responded with.
+type ImportRow struct {
+	Row     int          `json:"row"`
+	Status  string       `json:"status"`
+	ID      *uuid.UUID   `json:"id"`
+	Message string       `json:"message,omitempty"`
+	Code    string       `json:"code,omitempty"`
+	Errors  []FieldError `json:"errors,omitempty"`
+}
+
+// Turns the error a row failed with into its invalid ImportRow. Server side
+// failures aren't the row's fault, those report false and should fail the
+// whole import.
+func InvalidImportRow(row int, err error) (ImportRow, bool) {
+	result := ImportRow{Row: row, Status: "invalid"}
+
+	var validationErr *ValidationError
+	var overlapErr *OverlapError
+	var apiErr *APIError
+	switch {
+	case errors.As(err, &validationErr):
+		result.Message = validationErr.Message
+		result.Code = validationErr.Code
+		result.Errors = validationErr.Errors
+	case errors.As(err, &overlapErr):
+		result.Message = "Event overlaps other events at the venue, set AllowOverlap to schedule it anyway"
+		result.Code = "event_overlap"
+		for _, event := range overlapErr.Events {
+			result.Errors = append(result.Errors, FieldError{
+				Field:   "EventDatetime",
+				Code:    "overlap",
+				Message: fmt.Sprintf("Overlaps %v (%v)", event.Name, event.ID),
+			})
+		}
+	case errors.As(err, &apiErr) && apiErr.StatusCode() < 500:
+		result.Message = apiErr.Message
+	default:
+		return result, false
+	}
+	return result, true
+}
+
+// DecodeRows splits the body of a bulk request into one JSON object per row, so
+// each can go through DecodeAndValidate on its own and fail without failing the
+// others. The body is a JSON array of objects, or CSV with a header row when the
+// Content-Type is text/csv. dst is a pointer to the struct rows decode into:
+// CSV columns are named after its JSON fields, and cells are converted to the
+// field's type. Empty cells are left out, the same as a missing JSON field.
+//
+// A body that can't be split into rows, or has more than maxRows of them, is
+// returned as a 400 ValidationError.
+func DecodeRows(request events.APIGatewayProxyRequest, dst interface{}, maxRows int) ([]string, error) {
+	body := request.Body
+	if request.IsBase64Encoded {
+		decoded, err := base64.StdEncoding.DecodeString(body)
+		if err != nil {
+			return nil, malformedRows("Request body is not valid base64")
+		}
+		body = string(decoded)
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(getHeader(request.Headers, "Content-Type"))
+	var rows []string
+	if mediaType == "text/csv" {
+		var err error
+		if rows, err = csvRows(body, reflect.TypeOf(dst).Elem()); err != nil {
+			return nil, err
+		}
+	} else {
+		var raw []json.RawMessage
+		if err := json.Unmarshal([]byte(body), &raw); err != nil {
+			return nil, malformedRows("Request body must be a JSON array of objects or text/csv")
+		}
+		for _, row := range raw {
+			rows = append(rows, string(row))
+		}
+	}
+
+	if len(rows) == 0 {
+		return nil, malformedRows("Request body has no rows")
+	}
+	if len(rows) > maxRows {
+		return nil, malformedRows(fmt.Sprintf("Request body has %d rows, at most %d can be sent at once", len(rows), maxRows))
+	}
+	return rows, nil
+}
+
+func malformedRows(message string) *ValidationError {
+	return &ValidationError{StatusCode: 400, Code: "malformed_body", Message: message}
+}
+
+// Converts CSV with a header row into JSON objects with the fields of t.
+func csvRows(body string, t reflect.Type) ([]string, error) {
+	kinds := map[string]reflect.Kind{}
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		if name != "" && name != "-" {
+			kinds[name] = t.Field(i).Type.Kind()
+		}
+	}
+
+	reader := csv.NewReader(strings.NewReader(body))
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, malformedRows("Request body has no rows")
+	}
+	if err != nil {
+		return nil, malformedRows("Request body is not valid CSV: " + err.Error())
+	}
+	for i, name := range header {
+		// Spreadsheets like to start files with a byte order mark
+		header[i] = strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))
+		if _, ok := kinds[header[i]]; !ok {
+			return nil, malformedRows("Unknown CSV column " + strconv.Quote(header[i]))
+		}
+	}
+
+	var rows []string
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, malformedRows("Request body is not valid CSV: " + err.Error())
+		}
+		row := map[string]interface{}{}
+		for i, cell := range record {
+			cell = strings.TrimSpace(cell)
+			if cell == "" {
+				continue
+			}
+			row[header[i]] = csvValue(cell, kinds[header[i]])
+		}
+		encoded, _ := json.Marshal(row)
+		rows = append(rows, string(encoded))
+	}
+}
+
+// A cell that doesn't parse as the field's type is kept as a string, so
+// DecodeAndValidate reports it like the same mistake in JSON.
+func csvValue(cell string, kind reflect.Kind) interface{} {
+	switch kind {
+	case reflect.Bool:
+		if b, err := strconv.ParseBool(cell); err == nil {
+			return b
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		if _, err := strconv.ParseFloat(cell, 64); err == nil {
+			return json.Number(cell)
+		}
+	}
+	return cell
+}
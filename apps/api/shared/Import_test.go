@@ -0,0 +1,143 @@
+package shared
+
+import (
+	"encoding/base64"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+type importedRow struct {
+	Name         string  `json:"Name"`
+	NumGa        int32   `json:"NumGa"`
+	Price        float64 `json:"Price"`
+	AllowOverlap bool    `json:"AllowOverlap"`
+	Internal     string  `json:"-"`
+}
+
+func TestDecodeRows(t *testing.T) {
+	csvHeaders := map[string]string{"Content-Type": "text/csv; charset=utf-8"}
+	tests := []struct {
+		name    string
+		request events.APIGatewayProxyRequest
+		want    []string
+		// Non-empty when the body should be refused with a 400
+		wantErr string
+	}{
+		{
+			name:    "json array",
+			request: events.APIGatewayProxyRequest{Body: `[{"Name":"Jazz"}, {"Name":"Blues","NumGa":"ten"}]`},
+			want:    []string{`{"Name":"Jazz"}`, `{"Name":"Blues","NumGa":"ten"}`},
+		},
+		{
+			name:    "json rows are passed through as sent",
+			request: events.APIGatewayProxyRequest{Body: `[1, null, "row"]`},
+			want:    []string{`1`, `null`, `"row"`},
+		},
+		{
+			name:    "json object",
+			request: events.APIGatewayProxyRequest{Body: `{"Name":"Jazz"}`},
+			wantErr: "JSON array",
+		},
+		{
+			name:    "empty json array",
+			request: events.APIGatewayProxyRequest{Body: `[]`},
+			wantErr: "no rows",
+		},
+		{
+			name:    "csv",
+			request: events.APIGatewayProxyRequest{Headers: csvHeaders, Body: "Name,NumGa,Price,AllowOverlap\nJazz,100,12.5,true\n"},
+			want:    []string{`{"AllowOverlap":true,"Name":"Jazz","NumGa":100,"Price":12.5}`},
+		},
+		{
+			name:    "csv header is case insensitive",
+			request: events.APIGatewayProxyRequest{Headers: map[string]string{"content-type": "text/csv"}, Body: "Name\nJazz\n"},
+			want:    []string{`{"Name":"Jazz"}`},
+		},
+		{
+			name:    "csv empty cells are left out",
+			request: events.APIGatewayProxyRequest{Headers: csvHeaders, Body: "Name,NumGa\nJazz,\n,5\n"},
+			want:    []string{`{"Name":"Jazz"}`, `{"NumGa":5}`},
+		},
+		{
+			name:    "csv cells that don't parse stay strings",
+			request: events.APIGatewayProxyRequest{Headers: csvHeaders, Body: "NumGa,AllowOverlap\nten,maybe\n"},
+			want:    []string{`{"AllowOverlap":"maybe","NumGa":"ten"}`},
+		},
+		{
+			name:    "csv whitespace and byte order mark",
+			request: events.APIGatewayProxyRequest{Headers: csvHeaders, Body: "\ufeffName , NumGa\n  Jazz Night  ,  7\n"},
+			want:    []string{`{"Name":"Jazz Night","NumGa":7}`},
+		},
+		{
+			name:    "csv quoted cells",
+			request: events.APIGatewayProxyRequest{Headers: csvHeaders, Body: "Name\n\"Jazz, Blues and \"\"Soul\"\"\"\n"},
+			want:    []string{`{"Name":"Jazz, Blues and \"Soul\""}`},
+		},
+		{
+			name:    "csv unknown column",
+			request: events.APIGatewayProxyRequest{Headers: csvHeaders, Body: "Name,Colour\nJazz,red\n"},
+			wantErr: `Unknown CSV column "Colour"`,
+		},
+		{
+			name:    "csv ignored field isn't a column",
+			request: events.APIGatewayProxyRequest{Headers: csvHeaders, Body: "Internal\nsecret\n"},
+			wantErr: "Unknown CSV column",
+		},
+		{
+			name:    "csv ragged row",
+			request: events.APIGatewayProxyRequest{Headers: csvHeaders, Body: "Name,NumGa\nJazz,1,2\n"},
+			wantErr: "not valid CSV",
+		},
+		{
+			name:    "csv empty body",
+			request: events.APIGatewayProxyRequest{Headers: csvHeaders, Body: ""},
+			wantErr: "no rows",
+		},
+		{
+			name:    "csv header only",
+			request: events.APIGatewayProxyRequest{Headers: csvHeaders, Body: "Name\n"},
+			wantErr: "no rows",
+		},
+		{
+			name: "base64 body",
+			request: events.APIGatewayProxyRequest{
+				Headers:         csvHeaders,
+				Body:            base64.StdEncoding.EncodeToString([]byte("Name\nJazz\n")),
+				IsBase64Encoded: true,
+			},
+			want: []string{`{"Name":"Jazz"}`},
+		},
+		{
+			name:    "invalid base64 body",
+			request: events.APIGatewayProxyRequest{Body: "not base64!", IsBase64Encoded: true},
+			wantErr: "base64",
+		},
+		{
+			name:    "too many rows",
+			request: events.APIGatewayProxyRequest{Body: `[{}, {}, {}, {}]`},
+			wantErr: "at most 3",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodeRows(tt.request, &importedRow{}, 3)
+			if tt.wantErr != "" {
+				var validationErr *ValidationError
+				if !errors.As(err, &validationErr) || validationErr.StatusCode != 400 || !strings.Contains(validationErr.Message, tt.wantErr) {
+					t.Fatalf("DecodeRows() = %v, %v, want a 400 mentioning %q", got, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("DecodeRows() = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DecodeRows() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
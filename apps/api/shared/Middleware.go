@@ -0,0 +1,81 @@
+package shared
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"runtime/debug"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+type userInfoKey struct{}
+
+// The middleware every lambda should start its router with.
+var DefaultMiddleware = []Middleware{Recovery, Logging, CORS, Versioning}
+
+// Turns a panic in a handler into a 500 instead of crashing the lambda.
+func Recovery(next HandlerFunc) HandlerFunc {
+	return func(ctx context.Context, request events.APIGatewayProxyRequest) (response events.APIGatewayProxyResponse, err error) {
+		defer func() {
+			if p := recover(); p != nil {
+				log.Printf("Panic handling %v %v: %v\n%s", request.HTTPMethod, request.Path, p, debug.Stack())
+				response, err = CreateAPIErrorResponse(Internal("Internal server error", fmt.Errorf("panic: %v", p)), request.Headers)
+			}
+		}()
+		return next(ctx, request)
+	}
+}
+
+// Logs the method, path, status and duration of every request.
+func Logging(next HandlerFunc) HandlerFunc {
+	return func(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		start := time.Now()
+		response, err := next(ctx, request)
+		log.Printf("%v %v %d %v", request.HTTPMethod, request.Path, response.StatusCode, time.Since(start))
+		return response, err
+	}
+}
+
+// Makes sure every response carries the CORS headers for the request's origin,
+// without overwriting headers the handler set itself.
+func CORS(next HandlerFunc) HandlerFunc {
+	return func(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		response, err := next(ctx, request)
+		if response.Headers == nil {
+			response.Headers = map[string]string{}
+		}
+		for name, value := range GetResponseHeaders(request.Headers) {
+			if _, ok := response.Headers[name]; !ok {
+				response.Headers[name] = value
+			}
+		}
+		return response, err
+	}
+}
+
+// Rejects requests without a usable token with a 401. The wallet and uuid from
+// the token are available to the handler through GetUserInfo.
+func Auth(next HandlerFunc) HandlerFunc {
+	return func(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		tk, err := GetTokenFromRequest(request)
+		if err != nil {
+			return CreateErrorResponseAndLogError(401, "Error creating token object from DIDToken", request.Headers, err)
+		}
+
+		userinfo, err := GetWalletAndUUIDFromToken(tk)
+		if err != nil {
+			return CreateErrorResponseAndLogError(401, "Error retrieving wallet from token", request.Headers, err)
+		}
+
+		return next(context.WithValue(ctx, userInfoKey{}, userinfo), request)
+	}
+}
+
+// Returns the token information stored by Auth. Only valid in handlers behind
+// the Auth middleware.
+func GetUserInfo(ctx context.Context) GetWalletAndUUIDFromTokenResponse {
+	userinfo, _ := ctx.Value(userInfoKey{}).(GetWalletAndUUIDFromTokenResponse)
+	return userinfo
+}
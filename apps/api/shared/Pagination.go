@@ -0,0 +1,71 @@
+package shared
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+const MaxPageSize int32 = 100
+
+// Position of the last row of a page. Clients only ever see the encoded form,
+// so fields can be added without breaking them. Score is the primary sort key
+// for listings that rank their results (e.g. search relevance) and is zero
+// otherwise.
+type Cursor struct {
+	Score         float64   `json:"s,omitempty"`
+	EventDatetime time.Time `json:"d"`
+	Name          string    `json:"n"`
+	Pk            int32     `json:"p"`
+}
+
+type PaginatedResponse struct {
+	Items      interface{} `json:"items"`
+	NextCursor string      `json:"next_cursor"`
+	TotalCount int64       `json:"total_count"`
+}
+
+func EncodeCursor(cursor Cursor) string {
+	raw, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// Returns false when no cursor was supplied, i.e. the first page is requested.
+func GetCursorFromRequest(request events.APIGatewayProxyRequest) (Cursor, bool, error) {
+	var cursor Cursor
+	tmp, ok := request.QueryStringParameters["Cursor"]
+	if !ok || tmp == "" {
+		return cursor, false, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(tmp)
+	if err != nil {
+		return cursor, false, errors.New("malformed cursor")
+	}
+	if err := json.Unmarshal(raw, &cursor); err != nil {
+		return cursor, false, errors.New("malformed cursor")
+	}
+	return cursor, true, nil
+}
+
+// Reads the Limit query parameter, falling back to defaultSize and capping the
+// result at MaxPageSize.
+func GetPageSizeFromRequest(request events.APIGatewayProxyRequest, defaultSize int32) (int32, error) {
+	tmp, ok := request.QueryStringParameters["Limit"]
+	if !ok || tmp == "" {
+		return defaultSize, nil
+	}
+
+	size, err := strconv.ParseInt(tmp, 10, 32)
+	if err != nil || size < 1 {
+		return 0, errors.New("limit must be a positive integer")
+	}
+	if int32(size) > MaxPageSize {
+		return MaxPageSize, nil
+	}
+	return int32(size), nil
+}
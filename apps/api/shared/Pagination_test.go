@@ -0,0 +1,102 @@
+package shared
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+func requestWithQuery(params map[string]string) events.APIGatewayProxyRequest {
+	return events.APIGatewayProxyRequest{QueryStringParameters: params}
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	tests := []struct {
+		name   string
+		cursor Cursor
+	}{
+		{"zero", Cursor{}},
+		{"pk only", Cursor{Pk: 42}},
+		{"event listing", Cursor{
+			EventDatetime: time.Date(2026, 10, 19, 20, 30, 0, 0, time.UTC),
+			Name:          "Jazz & Blues Night",
+			Pk:            7,
+		}},
+		{"ranked listing", Cursor{
+			Score:         0.0759,
+			EventDatetime: time.Date(2026, 1, 2, 3, 4, 5, 6000000, time.UTC),
+			Name:          "Café über alles",
+			Pk:            1 << 30,
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoded := EncodeCursor(tt.cursor)
+			got, ok, err := GetCursorFromRequest(requestWithQuery(map[string]string{"Cursor": encoded}))
+			if err != nil || !ok {
+				t.Fatalf("GetCursorFromRequest(%q) = _, %v, %v, want a cursor", encoded, ok, err)
+			}
+			if !got.EventDatetime.Equal(tt.cursor.EventDatetime) || got.Score != tt.cursor.Score || got.Name != tt.cursor.Name || got.Pk != tt.cursor.Pk {
+				t.Errorf("cursor = %+v, want %+v", got, tt.cursor)
+			}
+		})
+	}
+}
+
+func TestGetCursorFromRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  map[string]string
+		wantOK  bool
+		wantErr bool
+	}{
+		{"no parameters", nil, false, false},
+		{"absent", map[string]string{"Limit": "10"}, false, false},
+		{"empty", map[string]string{"Cursor": ""}, false, false},
+		{"not base64", map[string]string{"Cursor": "not a cursor!"}, false, true},
+		{"padded base64", map[string]string{"Cursor": base64.URLEncoding.EncodeToString([]byte(`{"p":1}`))}, false, true},
+		{"not json", map[string]string{"Cursor": base64.RawURLEncoding.EncodeToString([]byte("p=1"))}, false, true},
+		{"wrong field type", map[string]string{"Cursor": base64.RawURLEncoding.EncodeToString([]byte(`{"p":"1"}`))}, false, true},
+		{"valid", map[string]string{"Cursor": base64.RawURLEncoding.EncodeToString([]byte(`{"p":1}`))}, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, ok, err := GetCursorFromRequest(requestWithQuery(tt.params))
+			if ok != tt.wantOK || (err != nil) != tt.wantErr {
+				t.Errorf("GetCursorFromRequest() = _, %v, %v, want ok %v and error %v", ok, err, tt.wantOK, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGetPageSizeFromRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		limit   string
+		want    int32
+		wantErr bool
+	}{
+		{"absent", "", 20, false},
+		{"within bounds", "5", 5, false},
+		{"at the maximum", "100", MaxPageSize, false},
+		{"capped", "1000", MaxPageSize, false},
+		{"zero", "0", 0, true},
+		{"negative", "-1", 0, true},
+		{"not a number", "ten", 0, true},
+		{"overflows int32", "4294967296", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := map[string]string{}
+			if tt.limit != "" {
+				params["Limit"] = tt.limit
+			}
+			got, err := GetPageSizeFromRequest(requestWithQuery(params), 20)
+			if got != tt.want || (err != nil) != tt.wantErr {
+				t.Errorf("GetPageSizeFromRequest(%q) = %d, %v, want %d and error %v", tt.limit, got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
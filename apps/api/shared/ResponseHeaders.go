@@ -16,7 +16,8 @@ var defaultOrigin = "https://opentix.co"
 func GetResponseHeaders(headers map[string]string) map[string]string {
 	responseHeaders := map[string]string{
 		"Content-Type":                     "application/json",
-		"Access-Control-Allow-Headers":     "Content-Type,X-Amz-Date,Authorization,X-Api-Key,X-Amz-Security-Token,X-Amz-User-Agent",
+		"Access-Control-Allow-Headers":     "Content-Type,X-Amz-Date,Authorization,X-Api-Key,X-Amz-Security-Token,X-Amz-User-Agent,API-Version,Idempotency-Key,If-Match",
+		"Access-Control-Expose-Headers":    "API-Version,Idempotent-Replayed,ETag,Content-Disposition",
 		"Access-Control-Allow-Credentials": "true",
 		"Access-Control-Allow-Methods":     "OPTIONS,GET,PUT,POST,PATCH,DELETE",
 	}
@@ -0,0 +1,161 @@
+package shared
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+type HandlerFunc func(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error)
+
+// Wraps a handler with behaviour that runs before and/or after it.
+type Middleware func(next HandlerFunc) HandlerFunc
+
+type route struct {
+	method   string
+	segments []string
+	handler  HandlerFunc
+}
+
+// Dispatches API Gateway requests to handlers by method and path. Patterns are
+// slash separated and a segment written as {name} matches any single segment,
+// which is then available through PathParam. Routes are tried in the order they
+// were declared, so literal routes should come before parameterized siblings
+// (e.g. /vendor/venues/all before /vendor/venues/{id}).
+//
+// A path that matches no route gets a 404. A path that matches but not for the
+// request method gets a 405 with an Allow header, or the CORS preflight response
+// if the method is OPTIONS.
+type Router struct {
+	routes     []route
+	middleware []Middleware
+}
+
+// Middleware given here wraps every request, including 404/405 responses, in
+// the order listed (the first one is outermost).
+func NewRouter(middleware ...Middleware) *Router {
+	return &Router{middleware: middleware}
+}
+
+// Declares a route. Middleware given here only wraps this route and runs inside
+// the router wide middleware.
+func (r *Router) Handle(method string, pattern string, handler HandlerFunc, middleware ...Middleware) {
+	r.routes = append(r.routes, route{
+		method:   method,
+		segments: splitPath(pattern),
+		handler:  chain(handler, middleware),
+	})
+}
+
+func (r *Router) GET(pattern string, handler HandlerFunc, middleware ...Middleware) {
+	r.Handle("GET", pattern, handler, middleware...)
+}
+
+func (r *Router) POST(pattern string, handler HandlerFunc, middleware ...Middleware) {
+	r.Handle("POST", pattern, handler, middleware...)
+}
+
+func (r *Router) PUT(pattern string, handler HandlerFunc, middleware ...Middleware) {
+	r.Handle("PUT", pattern, handler, middleware...)
+}
+
+func (r *Router) PATCH(pattern string, handler HandlerFunc, middleware ...Middleware) {
+	r.Handle("PATCH", pattern, handler, middleware...)
+}
+
+func (r *Router) DELETE(pattern string, handler HandlerFunc, middleware ...Middleware) {
+	r.Handle("DELETE", pattern, handler, middleware...)
+}
+
+// The lambda entry point, pass it to lambda.Start.
+func (r *Router) Serve(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	return chain(r.dispatch, r.middleware)(ctx, request)
+}
+
+func (r *Router) dispatch(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	path := splitPath(request.Path)
+
+	var allowed []string
+	for _, rt := range r.routes {
+		params, ok := matchPath(rt.segments, path)
+		if !ok {
+			continue
+		}
+		if rt.method != request.HTTPMethod {
+			if !slices.Contains(allowed, rt.method) {
+				allowed = append(allowed, rt.method)
+			}
+			continue
+		}
+
+		if request.PathParameters == nil {
+			request.PathParameters = map[string]string{}
+		}
+		for name, value := range params {
+			request.PathParameters[name] = value
+		}
+		return rt.handler(ctx, request)
+	}
+
+	if len(allowed) == 0 {
+		return CreateErrorResponse(404, "Not Found", request.Headers)
+	}
+
+	allow := strings.Join(append(allowed, "OPTIONS"), ",")
+	if request.HTTPMethod == "OPTIONS" {
+		headers := GetResponseHeaders(request.Headers)
+		headers["Access-Control-Allow-Methods"] = allow
+		headers["Allow"] = allow
+		return events.APIGatewayProxyResponse{
+			StatusCode: 204,
+			Headers:    headers,
+		}, nil
+	}
+
+	response, err := CreateErrorResponse(405, "Method Not Allowed", request.Headers)
+	response.Headers["Allow"] = allow
+	return response, err
+}
+
+// Returns the value of a {name} segment of the matched route.
+func PathParam(request events.APIGatewayProxyRequest, name string) string {
+	return request.PathParameters[name]
+}
+
+func chain(handler HandlerFunc, middleware []Middleware) HandlerFunc {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		handler = middleware[i](handler)
+	}
+	return handler
+}
+
+func splitPath(path string) []string {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return nil
+	}
+	return strings.Split(path, "/")
+}
+
+func matchPath(pattern []string, path []string) (map[string]string, bool) {
+	if len(pattern) != len(path) {
+		return nil, false
+	}
+
+	var params map[string]string
+	for i, segment := range pattern {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			if params == nil {
+				params = map[string]string{}
+			}
+			params[segment[1:len(segment)-1]] = path[i]
+			continue
+		}
+		if segment != path[i] {
+			return nil, false
+		}
+	}
+	return params, true
+}
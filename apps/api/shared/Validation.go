@@ -0,0 +1,222 @@
+package shared
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/google/uuid"
+)
+
+// ISO 8601, the format every datetime in a request body is expected in
+const DatetimeLayout string = "2006-01-02T15:04:05.999Z"
+
+// Parses an optional ISO 8601 query parameter, returning nil when it's absent.
+func GetTimeParam(request events.APIGatewayProxyRequest, name string) (*time.Time, error) {
+	tmp, ok := request.QueryStringParameters[name]
+	if !ok || tmp == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(DatetimeLayout, strings.TrimSpace(tmp))
+	if err != nil {
+		return nil, BadRequest(name + " must be an ISO 8601 datetime")
+	}
+	t = t.UTC()
+	return &t, nil
+}
+
+// Returned by DecodeAndValidate. StatusCode is 400 when the body isn't a JSON
+// object at all and 422 when individual fields break their rules.
+type ValidationError struct {
+	StatusCode int
+	Code       string
+	Message    string
+	Errors     []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%v: %v", e.Message, e.Errors)
+}
+
+// DecodeAndValidate unmarshals a JSON request body into dst, which must be a
+// pointer to a struct, and checks its fields against their `validate` tags.
+// Rules are comma separated:
+//
+//	required   the field must be present, not null and not an empty string
+//	min=N      numbers must be >= N, strings at least N characters long
+//	max=N      numbers must be <= N, strings at most N characters long
+//	oneof=a|b  the value must be one of the listed options
+//	uuid       the string must be a valid UUID
+//	datetime   the string must be in DatetimeLayout
+//
+// Apart from required, rules are only checked for fields present in the body, so
+// PATCH bodies can leave out anything they don't change.
+func DecodeAndValidate(body string, dst interface{}) error {
+	malformed := &ValidationError{
+		StatusCode: 400,
+		Code:       "malformed_body",
+		Message:    "Request body must be a JSON object",
+	}
+
+	var present map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(body), &present); err != nil || present == nil {
+		return malformed
+	}
+
+	var fieldErrors []FieldError
+	if err := json.Unmarshal([]byte(body), dst); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if !errors.As(err, &typeErr) {
+			return malformed
+		}
+		fieldErrors = append(fieldErrors, FieldError{
+			Field:   typeErr.Field,
+			Code:    "invalid_type",
+			Message: fmt.Sprintf("%v must be %v", typeErr.Field, jsonTypeName(typeErr.Type)),
+		})
+	}
+
+	value := reflect.ValueOf(dst).Elem()
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		rules := field.Tag.Get("validate")
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if rules == "" || name == "" || name == "-" {
+			continue
+		}
+		// A field that failed to decode already has its error
+		if slices.ContainsFunc(fieldErrors, func(fe FieldError) bool { return fe.Field == name }) {
+			continue
+		}
+
+		raw, ok := present[name]
+		ok = ok && !bytes.Equal(raw, []byte("null"))
+		if fe, failed := checkField(name, value.Field(i), ok, rules); failed {
+			fieldErrors = append(fieldErrors, fe)
+		}
+	}
+
+	if len(fieldErrors) > 0 {
+		return &ValidationError{
+			StatusCode: 422,
+			Code:       "validation_failed",
+			Message:    "One or more fields are invalid",
+			Errors:     fieldErrors,
+		}
+	}
+	return nil
+}
+
+// Returns the fields a JSON body explicitly sets to null. DecodeAndValidate
+// treats null the same as a missing field, so PATCH handlers use this to tell
+// clearing a field apart from leaving it unchanged, as in JSON Merge Patch.
+func NullFields(body string) map[string]bool {
+	var present map[string]json.RawMessage
+	json.Unmarshal([]byte(body), &present)
+
+	nulls := map[string]bool{}
+	for name, raw := range present {
+		if bytes.Equal(raw, []byte("null")) {
+			nulls[name] = true
+		}
+	}
+	return nulls
+}
+
+// Checks a single field, stopping at the first rule it breaks.
+func checkField(name string, field reflect.Value, present bool, rules string) (FieldError, bool) {
+	for _, rule := range strings.Split(rules, ",") {
+		key, arg, _ := strings.Cut(rule, "=")
+
+		if key == "required" {
+			if !present || (field.Kind() == reflect.String && strings.TrimSpace(field.String()) == "") {
+				return FieldError{name, "required", name + " is required"}, true
+			}
+			continue
+		}
+		if !present {
+			return FieldError{}, false
+		}
+
+		switch key {
+		case "min", "max":
+			limit, _ := strconv.ParseFloat(arg, 64)
+			n, isString := fieldSize(field)
+			if (key == "min" && n < limit) || (key == "max" && n > limit) {
+				return FieldError{name, key, sizeMessage(name, key, arg, isString)}, true
+			}
+		case "oneof":
+			options := strings.Split(arg, "|")
+			if !slices.Contains(options, fmt.Sprint(field.Interface())) {
+				return FieldError{name, "oneof", name + " must be one of: " + strings.Join(options, ", ")}, true
+			}
+		case "uuid":
+			if _, err := uuid.Parse(field.String()); err != nil {
+				return FieldError{name, "uuid", name + " must be a valid UUID"}, true
+			}
+		case "datetime":
+			if _, err := time.Parse(DatetimeLayout, strings.TrimSpace(field.String())); err != nil {
+				return FieldError{name, "datetime", name + " must be an ISO 8601 datetime"}, true
+			}
+		}
+	}
+	return FieldError{}, false
+}
+
+// Numeric value of a field, or its length for strings.
+func fieldSize(field reflect.Value) (float64, bool) {
+	switch field.Kind() {
+	case reflect.String:
+		return float64(len([]rune(field.String()))), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(field.Int()), false
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(field.Uint()), false
+	case reflect.Float32, reflect.Float64:
+		return field.Float(), false
+	}
+	return 0, false
+}
+
+func sizeMessage(name string, key string, arg string, isString bool) string {
+	bound := "at least"
+	if key == "max" {
+		bound = "at most"
+	}
+	if isString {
+		return fmt.Sprintf("%v must be %v %v characters long", name, bound, arg)
+	}
+	return fmt.Sprintf("%v must be %v %v", name, bound, arg)
+}
+
+func jsonTypeName(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "an integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	}
+	return "a valid " + t.String()
+}
+
+// Turns an error from DecodeAndValidate into a response. Anything that isn't a
+// ValidationError is treated as a malformed body.
+func CreateValidationErrorResponse(err error, requestHeaders map[string]string) (events.APIGatewayProxyResponse, error) {
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		return CreateErrorResponseAndLogError(400, "Invalid request body", requestHeaders, err)
+	}
+	return CreateFieldErrorResponse(validationErr.StatusCode, validationErr.Code, validationErr.Message, validationErr.Errors, requestHeaders)
+}
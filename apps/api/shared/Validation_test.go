@@ -0,0 +1,117 @@
+package shared
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type validatedBody struct {
+	Name    string  `json:"name" validate:"required,min=2,max=5"`
+	Count   int32   `json:"count" validate:"min=1,max=10"`
+	Price   float64 `json:"price" validate:"max=9.5"`
+	Tier    string  `json:"tier" validate:"oneof=unique|ga"`
+	Venue   string  `json:"venue" validate:"uuid"`
+	Time    string  `json:"time" validate:"datetime"`
+	Skipped string  `json:"-" validate:"required"`
+	Free    bool    `json:"free"`
+}
+
+func TestDecodeAndValidate(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+		// Field and code of each error, in order
+		wantErrors [][2]string
+	}{
+		{"minimal", `{"name":"Jazz"}`, 0, nil},
+		{"every field", `{"name":"Jazz","count":10,"price":9.5,"tier":"ga","venue":"0f8fad5b-d9cb-469f-a165-70867728950e","time":"2026-10-19T20:30:00.000Z","free":true}`, 0, nil},
+		{"unknown fields are ignored", `{"name":"Jazz","extra":1}`, 0, nil},
+		{"not json", `name=Jazz`, 400, nil},
+		{"empty body", ``, 400, nil},
+		{"array", `[{"name":"Jazz"}]`, 400, nil},
+		{"null", `null`, 400, nil},
+		{"string", `"Jazz"`, 400, nil},
+		{"missing required", `{}`, 422, [][2]string{{"name", "required"}}},
+		{"null required", `{"name":null}`, 422, [][2]string{{"name", "required"}}},
+		{"blank required", `{"name":"  "}`, 422, [][2]string{{"name", "required"}}},
+		{"string too short", `{"name":"J"}`, 422, [][2]string{{"name", "min"}}},
+		{"string too long", `{"name":"Jazzfest"}`, 422, [][2]string{{"name", "max"}}},
+		{"length counts characters", `{"name":"Über"}`, 0, nil},
+		{"number too small", `{"name":"Jazz","count":0}`, 422, [][2]string{{"count", "min"}}},
+		{"number too large", `{"name":"Jazz","count":11}`, 422, [][2]string{{"count", "max"}}},
+		{"fractional bound", `{"name":"Jazz","price":9.75}`, 422, [][2]string{{"price", "max"}}},
+		{"absent fields skip their rules", `{"name":"Jazz","count":null}`, 0, nil},
+		{"not one of", `{"name":"Jazz","tier":"vip"}`, 422, [][2]string{{"tier", "oneof"}}},
+		{"invalid uuid", `{"name":"Jazz","venue":"venue-1"}`, 422, [][2]string{{"venue", "uuid"}}},
+		{"invalid datetime", `{"name":"Jazz","time":"19/10/2026"}`, 422, [][2]string{{"time", "datetime"}}},
+		{"wrong type", `{"name":"Jazz","count":"ten"}`, 422, [][2]string{{"count", "invalid_type"}}},
+		{"wrong type isn't checked again", `{"name":"Jazz","count":-1.5}`, 422, [][2]string{{"count", "invalid_type"}}},
+		{"errors are collected", `{"name":"J","count":11,"tier":"vip"}`, 422, [][2]string{{"name", "min"}, {"count", "max"}, {"tier", "oneof"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var dst validatedBody
+			err := DecodeAndValidate(tt.body, &dst)
+			if tt.wantStatus == 0 {
+				if err != nil {
+					t.Fatalf("DecodeAndValidate(%s) = %v, want no error", tt.body, err)
+				}
+				return
+			}
+
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("DecodeAndValidate(%s) = %v, want a ValidationError", tt.body, err)
+			}
+			if validationErr.StatusCode != tt.wantStatus {
+				t.Errorf("StatusCode = %d, want %d", validationErr.StatusCode, tt.wantStatus)
+			}
+			var got [][2]string
+			for _, fe := range validationErr.Errors {
+				got = append(got, [2]string{fe.Field, fe.Code})
+			}
+			if !reflect.DeepEqual(got, tt.wantErrors) {
+				t.Errorf("Errors = %v, want %v", got, tt.wantErrors)
+			}
+		})
+	}
+}
+
+func TestDecodeAndValidateDecodes(t *testing.T) {
+	var dst validatedBody
+	body := `{"name":"Jazz","count":3,"tier":"unique","free":true}`
+	if err := DecodeAndValidate(body, &dst); err != nil {
+		t.Fatalf("DecodeAndValidate() = %v", err)
+	}
+	want := validatedBody{Name: "Jazz", Count: 3, Tier: "unique", Free: true}
+	if dst != want {
+		t.Errorf("decoded %+v, want %+v", dst, want)
+	}
+}
+
+func TestNullFields(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want map[string]bool
+	}{
+		{"empty object", `{}`, map[string]bool{}},
+		{"no nulls", `{"Name":"Jazz","Photo":""}`, map[string]bool{}},
+		{"cleared fields", `{"Name":"Jazz","Photo":null,"TransactionHash":null}`, map[string]bool{"Photo": true, "TransactionHash": true}},
+		{"whitespace around null", `{"Photo" :  null }`, map[string]bool{"Photo": true}},
+		{"null string isn't null", `{"Photo":"null"}`, map[string]bool{}},
+		{"nested nulls are ignored", `{"Address":{"Zip":null}}`, map[string]bool{}},
+		{"not an object", `[null]`, map[string]bool{}},
+		{"malformed", `{"Photo":null`, map[string]bool{}},
+		{"null body", `null`, map[string]bool{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NullFields(tt.body); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NullFields(%s) = %v, want %v", tt.body, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package shared
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/opentix/platform/apps/api/models"
+	"github.com/opentix/platform/packages/gohelpers/packages/address"
+	"github.com/opentix/platform/packages/gohelpers/packages/geo"
+	"github.com/opentix/platform/packages/gohelpers/packages/query"
+)
+
+// Geocodes a venue from its postal code. Venues outside the bundled dataset are
+// stored without coordinates and won't show up in location searches.
+func GetVenueLocation(countryCode string, zip string) (pgtype.Float8, pgtype.Float8) {
+	point, ok := geo.LookupZip(countryCode, zip)
+	if !ok {
+		return pgtype.Float8{}, pgtype.Float8{}
+	}
+	return pgtype.Float8{Float64: point.Latitude, Valid: true}, pgtype.Float8{Float64: point.Longitude, Valid: true}
+}
+
+// Normalizes a venue's address, deriving the state and country names from their
+// codes. Returns the field errors to send back if the address is invalid.
+func NormalizeVenueAddress(addr address.Address) (address.Address, []FieldError) {
+	normalized, errs := address.Normalize(addr)
+	var fieldErrors []FieldError
+	for _, e := range errs {
+		fieldErrors = append(fieldErrors, FieldError(e))
+	}
+	return normalized, fieldErrors
+}
+
+// Reports whether the vendor already has a venue other than excludePk at the
+// given address. excludePk is 0 when creating a venue.
+func IsDuplicateVenue(ctx context.Context, queries *query.Queries, wallet string, addr address.Address, excludePk int32) (bool, error) {
+	_, err := queries.VendorFindDuplicateVenue(ctx, query.VendorFindDuplicateVenueParams{
+		Wallet:      wallet,
+		Column2:     addr.StreetAddress,
+		Zip:         addr.Zip,
+		CountryCode: addr.CountryCode,
+		Column5:     excludePk,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+var DuplicateVenueError = []FieldError{{
+	Field:   "StreetAddress",
+	Code:    "duplicate",
+	Message: "You already have a venue at this address",
+}}
+
+// PrepareVenue checks a decoded VenuePostBodyParams against the rules of
+// creating a venue and fills in the fields derived from its address, so it can
+// be passed on to CreateVenue. params.Vendor must already be set. Problems with
+// the venue are returned as a ValidationError.
+func PrepareVenue(ctx context.Context, queries *query.Queries, wallet string, params *models.VenuePostBodyParams) error {
+	params.Name = address.CollapseWhitespace(params.Name)
+	addr, fieldErrors := NormalizeVenueAddress(address.Address{
+		StreetAddress: params.StreetAddress,
+		Zip:           params.Zip,
+		City:          params.City,
+		StateCode:     params.StateCode,
+		CountryCode:   params.CountryCode,
+	})
+	if len(fieldErrors) > 0 {
+		return &ValidationError{StatusCode: 422, Code: "validation_failed", Message: "Venue is invalid", Errors: fieldErrors}
+	}
+	params.StreetAddress = addr.StreetAddress
+	params.Zip = addr.Zip
+	params.City = addr.City
+	params.StateCode = addr.StateCode
+	params.StateName = addr.StateName
+	params.CountryCode = addr.CountryCode
+	params.CountryName = addr.CountryName
+
+	duplicate, err := IsDuplicateVenue(ctx, queries, wallet, addr, 0)
+	if err != nil {
+		return FromDBError(err, "Venue not found")
+	}
+	if duplicate {
+		return &ValidationError{StatusCode: 409, Code: "duplicate_venue", Message: "Venue already exists", Errors: DuplicateVenueError}
+	}
+
+	params.Latitude, params.Longitude = GetVenueLocation(params.CountryCode, params.Zip)
+	return nil
+}
@@ -0,0 +1,75 @@
+package shared
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// Versions of the response format. Legacy is the sqlc rows marshalled as is,
+// which unversioned requests keep getting so existing clients don't break.
+const (
+	VersionLegacy = 0
+	Version1      = 1
+
+	LatestVersion = Version1
+)
+
+const VersionHeader = "API-Version"
+
+type versionKey struct{}
+
+var versionPrefix = regexp.MustCompile(`^/v([0-9]+)(/|$)`)
+
+// Picks the response version from a /v{n} path prefix or the API-Version header,
+// the prefix winning if both are given. The prefix is stripped so routes are
+// declared once for every version. Unsupported versions get a 400, and
+// responses say which version they are in.
+func Versioning(next HandlerFunc) HandlerFunc {
+	return func(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		version := VersionLegacy
+		raw := getHeader(request.Headers, VersionHeader)
+
+		if m := versionPrefix.FindStringSubmatch(request.Path); m != nil {
+			raw = m[1]
+			request.Path = "/" + strings.TrimPrefix(request.Path[len(m[0]):], "/")
+		}
+		if raw != "" {
+			v, err := strconv.Atoi(strings.TrimPrefix(strings.ToLower(raw), "v"))
+			if err != nil || v < VersionLegacy || v > LatestVersion {
+				return CreateErrorResponse(400, "Unsupported API version "+raw, request.Headers)
+			}
+			version = v
+		}
+
+		response, err := next(context.WithValue(ctx, versionKey{}, version), request)
+		if response.Headers == nil {
+			response.Headers = map[string]string{}
+		}
+		response.Headers[VersionHeader] = strconv.Itoa(version)
+		response.Headers["Vary"] = VersionHeader
+		return response, err
+	}
+}
+
+// Returns the response version negotiated by Versioning.
+func GetVersion(ctx context.Context) int {
+	version, _ := ctx.Value(versionKey{}).(int)
+	return version
+}
+
+// Header lookup that doesn't depend on the client's capitalization.
+func getHeader(headers map[string]string, name string) string {
+	if value, ok := headers[name]; ok {
+		return value
+	}
+	for key, value := range headers {
+		if strings.EqualFold(key, name) {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,368 @@
+package spec
+
+//go:generate go run ./gen -json openapi.json -ts ../../../packages/types/src/lib/api.gen.ts
+
+import (
+	"encoding/json"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/opentix/platform/apps/api/models"
+	v1 "github.com/opentix/platform/apps/api/models/v1"
+	"github.com/opentix/platform/apps/api/shared"
+	"github.com/opentix/platform/packages/gohelpers/packages/openapi"
+	"github.com/opentix/platform/packages/gohelpers/packages/query"
+)
+
+// One operation of the API. Body and Response are zero values of the types the
+// handler decodes and marshals, nil when there is none. V1 is the response
+// under /v1 when it differs from the legacy one.
+type route struct {
+	Method  string
+	Path    string
+	ID      string
+	Summary string
+	Tag     string
+	Auth    bool
+	// Accepts an Idempotency-Key header, see shared.Idempotency
+	Idempotent bool
+	// Accepts an If-Match header with the ETag of the record, see shared.ETag
+	Conditional bool
+	// Body of a 409 the route sends itself. It replaces the Idempotent 409 in
+	// the document, both carry a message.
+	Conflict interface{}
+	Query    []openapi.Parameter
+	Body     interface{}
+	Status   int
+	Response interface{}
+	V1       interface{}
+}
+
+// Listings are wrapped in a shared.PaginatedResponse of these item types, or a
+// v1.Page from v1 on
+type page struct {
+	Name string
+	Item interface{}
+	V1   bool
+}
+
+var (
+	pagedEvents     = page{"PaginatedAppEvent", query.AppEvent{}, false}
+	pagedVenues     = page{"PaginatedAppVenue", query.AppVenue{}, false}
+	pagedUserEvents = page{"PaginatedUserGetEventsPaginatedRow", query.UserGetEventsPaginatedRow{}, false}
+
+	v1PagedEvents     = page{"V1PageEvent", v1.Event{}, true}
+	v1PagedVenues     = page{"V1PageVenue", v1.Venue{}, true}
+	v1PagedUserEvents = page{"V1PageEventSummary", v1.EventSummary{}, true}
+	// History is only sent in the v1 format
+	pagedHistory = page{"V1PageAuditEntry", v1.AuditEntry{}, true}
+	// As is the photo review queue
+	pagedMedia = page{"V1PageMedia", v1.Media{}, true}
+)
+
+var maxIdempotencyKeyLength = 255
+
+var paginationParams = []openapi.Parameter{
+	queryParam("Limit", "integer", "Page size, capped at 100"),
+	queryParam("Cursor", "string", "next_cursor of the previous page"),
+}
+
+var importDryRunParam = queryParam("DryRun", "boolean", "Check every row without saving any")
+
+// Every route the lambdas declare. Keep in sync with the router declarations in
+// apps/api/*.go, CI fails when openapi.json is out of date with this list.
+var routes = []route{
+	{Method: "GET", Path: "/vendor/id", ID: "getVendor", Summary: "Get the signed in vendor", Tag: "vendor", Auth: true,
+		Status: 200, Response: query.AppVendor{}, V1: v1.Vendor{}},
+	{Method: "POST", Path: "/vendor/id", ID: "createVendor", Summary: "Register the signed in wallet as a vendor", Tag: "vendor", Auth: true,
+		Body: models.PostPatchVendorIdRequestBody{}, Status: 201, Response: query.AppVendor{}, V1: v1.Vendor{}},
+	{Method: "PATCH", Path: "/vendor/id", ID: "updateVendor", Summary: "Rename the signed in vendor", Tag: "vendor", Auth: true,
+		Body: models.PostPatchVendorIdRequestBody{}, Status: 200, Response: query.AppVendor{}, V1: v1.Vendor{}},
+	{Method: "GET", Path: "/vendor/id/history", ID: "getVendorHistory", Summary: "List the changes made to the signed in vendor", Tag: "vendor", Auth: true,
+		Query: paginationParams, Status: 200, Response: pagedHistory},
+
+	{Method: "GET", Path: "/vendor/venues", ID: "listVenues", Summary: "List the vendor's venues", Tag: "venues", Auth: true,
+		Query: append([]openapi.Parameter{
+			queryParam("Filter", "string", "Full text search over the venue name and address"),
+		}, paginationParams...),
+		Status: 200, Response: pagedVenues, V1: v1PagedVenues},
+	{Method: "GET", Path: "/vendor/venues/all", ID: "listAllVenues", Summary: "List every venue of the vendor, unpaginated", Tag: "venues", Auth: true,
+		Status: 200, Response: []query.VendorGetAllVenuesRow{}, V1: []v1.VenueSummary{}},
+	{Method: "GET", Path: "/vendor/venues/{id}", ID: "getVenue", Summary: "Get a venue by uuid or pk", Tag: "venues", Auth: true,
+		Status: 200, Response: query.AppVenue{}, V1: v1.Venue{}},
+	{Method: "GET", Path: "/vendor/venues/{id}/history", ID: "getVenueHistory", Summary: "List the changes made to a venue", Tag: "venues", Auth: true,
+		Query: paginationParams, Status: 200, Response: pagedHistory},
+	{Method: "GET", Path: "/vendor/venues/{id}/calendar", ID: "getVenueCalendar", Summary: "List the times a venue is booked by events", Tag: "venues", Auth: true,
+		Query: []openapi.Parameter{
+			queryParam("From", "string", "ISO 8601 start of the window, defaults to now"),
+			queryParam("To", "string", "ISO 8601 end of the window, defaults to 90 days after From"),
+		},
+		Status: 200, Response: v1.VenueCalendar{}},
+	{Method: "POST", Path: "/vendor/venues", ID: "createVenue", Summary: "Create a venue", Tag: "venues", Auth: true, Idempotent: true,
+		Body: models.VenuePostBodyParams{}, Status: 201, Response: query.AppVenue{}, V1: v1.Venue{}},
+	{Method: "POST", Path: "/vendor/venues/import", ID: "importVenues", Summary: "Create venues in bulk from a JSON array or text/csv, reporting the errors of each row", Tag: "venues", Auth: true,
+		Query: []openapi.Parameter{importDryRunParam}, Body: []models.VenuePostBodyParams{}, Status: 200, Response: shared.ImportResponse{}},
+	{Method: "PATCH", Path: "/vendor/venues", ID: "updateVenue", Summary: "Update a venue, sending a null Photo removes its cover", Tag: "venues", Auth: true, Conditional: true,
+		Body: models.VenuePatchBodyParams{}, Status: 200, Response: query.AppVenue{}, V1: v1.Venue{}},
+	{Method: "PATCH", Path: "/vendor/venues/{id}/capacity", ID: "updateVenueCapacity", Summary: "Change a venue's capacity if its upcoming events still fit", Tag: "venues", Auth: true, Conditional: true,
+		Body: models.VenueCapacityPatchBodyParams{}, Conflict: models.VenueCapacityConflictResponse{}, Status: 200, Response: query.AppVenue{}, V1: v1.Venue{}},
+	{Method: "POST", Path: "/vendor/venues/photos", ID: "uploadVenuePhoto", Summary: "Get a presigned upload of a photo for a venue's gallery", Tag: "photos", Auth: true,
+		Body: models.PostVendorPhotoRequest{}, Status: 200, Response: models.PostVendorPhotoResponse{}},
+	{Method: "DELETE", Path: "/vendor/venues/photos", ID: "deleteVenuePhoto", Summary: "Remove a venue's photo, leaving its gallery without a cover", Tag: "photos", Auth: true,
+		Body: models.DeleteVendorPhotoRequest{}, Status: 202, Response: query.AppVenue{}, V1: v1.Venue{}},
+	{Method: "GET", Path: "/vendor/venues/{id}/photos", ID: "getVenueGallery", Summary: "List the photos in a venue's gallery", Tag: "photos", Auth: true,
+		Status: 200, Response: v1.Gallery{}},
+	{Method: "PUT", Path: "/vendor/venues/{id}/photos/order", ID: "reorderVenueGallery", Summary: "Reorder the photos in a venue's gallery", Tag: "photos", Auth: true,
+		Body: models.GalleryOrderPutBodyParams{}, Status: 200, Response: v1.Gallery{}},
+	{Method: "PUT", Path: "/vendor/venues/{id}/photos/cover", ID: "setVenueGalleryCover", Summary: "Choose the photo shown as a venue's photo", Tag: "photos", Auth: true,
+		Body: models.GalleryCoverPutBodyParams{}, Status: 200, Response: v1.Gallery{}},
+	{Method: "PATCH", Path: "/vendor/venues/{id}/photos/{photoId}", ID: "updateVenueGalleryPhoto", Summary: "Change a photo's caption or alt text", Tag: "photos", Auth: true,
+		Body: models.GalleryPhotoPatchBodyParams{}, Status: 200, Response: v1.Media{}},
+	{Method: "DELETE", Path: "/vendor/venues/{id}/photos/{photoId}", ID: "deleteVenueGalleryPhoto", Summary: "Remove a photo from a venue's gallery", Tag: "photos", Auth: true,
+		Status: 200, Response: v1.Gallery{}},
+
+	{Method: "GET", Path: "/vendor/events", ID: "listEvents", Summary: "List the vendor's events", Tag: "events", Auth: true,
+		Query: append([]openapi.Parameter{
+			queryParam("Filter", "string", "Full text search over the event, its venue and city"),
+			queryParam("Venue", "integer", "Only events at this venue pk"),
+			queryParam("EventDatetime", "string", "Only events at or after this ISO 8601 datetime"),
+		}, paginationParams...),
+		Status: 200, Response: pagedEvents, V1: v1PagedEvents},
+	{Method: "GET", Path: "/vendor/events/{id}", ID: "getEvent", Summary: "Get an event by uuid or pk", Tag: "events", Auth: true,
+		Status: 200, Response: query.AppEvent{}, V1: v1.Event{}},
+	{Method: "GET", Path: "/vendor/events/{id}/history", ID: "getEventHistory", Summary: "List the changes made to an event and its tickets", Tag: "events", Auth: true,
+		Query: paginationParams, Status: 200, Response: pagedHistory},
+	{Method: "POST", Path: "/vendor/events", ID: "createEvent", Summary: "Create an event", Tag: "events", Auth: true, Idempotent: true,
+		Body: models.EventPostBodyParams{}, Conflict: models.EventOverlapResponse{}, Status: 200, Response: query.AppEvent{}, V1: v1.Event{}},
+	{Method: "POST", Path: "/vendor/events/import", ID: "importEvents", Summary: "Create events in bulk from a JSON array or text/csv, reporting the errors of each row", Tag: "events", Auth: true,
+		Query: []openapi.Parameter{importDryRunParam}, Body: []models.EventPostBodyParams{}, Status: 200, Response: shared.ImportResponse{}},
+	{Method: "PATCH", Path: "/vendor/events", ID: "updateEvent", Summary: "Update an event, sending a null Photo removes its cover", Tag: "events", Auth: true, Conditional: true,
+		Body: models.EventPatchBodyParams{}, Conflict: models.EventOverlapResponse{}, Status: 200, Response: query.AppEvent{}, V1: v1.Event{}},
+	{Method: "POST", Path: "/vendor/events/{id}/cancel", ID: "cancelEvent", Summary: "Cancel an event, notifying its ticket holders", Tag: "events", Auth: true,
+		Status: 200, Response: query.AppEvent{}, V1: v1.Event{}},
+	{Method: "POST", Path: "/vendor/events/photos", ID: "uploadEventPhoto", Summary: "Get a presigned upload of a photo for an event's gallery", Tag: "photos", Auth: true,
+		Body: models.PostVendorPhotoRequest{}, Status: 200, Response: models.PostVendorPhotoResponse{}},
+	{Method: "DELETE", Path: "/vendor/events/photos", ID: "deleteEventPhoto", Summary: "Remove an event's photo, leaving its gallery without a cover", Tag: "photos", Auth: true,
+		Body: models.DeleteVendorPhotoRequest{}, Status: 202, Response: query.AppEvent{}, V1: v1.Event{}},
+	{Method: "GET", Path: "/vendor/events/{id}/photos", ID: "getEventGallery", Summary: "List the photos in an event's gallery", Tag: "photos", Auth: true,
+		Status: 200, Response: v1.Gallery{}},
+	{Method: "PUT", Path: "/vendor/events/{id}/photos/order", ID: "reorderEventGallery", Summary: "Reorder the photos in an event's gallery", Tag: "photos", Auth: true,
+		Body: models.GalleryOrderPutBodyParams{}, Status: 200, Response: v1.Gallery{}},
+	{Method: "PUT", Path: "/vendor/events/{id}/photos/cover", ID: "setEventGalleryCover", Summary: "Choose the photo shown as an event's photo", Tag: "photos", Auth: true,
+		Body: models.GalleryCoverPutBodyParams{}, Status: 200, Response: v1.Gallery{}},
+	{Method: "PATCH", Path: "/vendor/events/{id}/photos/{photoId}", ID: "updateEventGalleryPhoto", Summary: "Change a photo's caption or alt text", Tag: "photos", Auth: true,
+		Body: models.GalleryPhotoPatchBodyParams{}, Status: 200, Response: v1.Media{}},
+	{Method: "DELETE", Path: "/vendor/events/{id}/photos/{photoId}", ID: "deleteEventGalleryPhoto", Summary: "Remove a photo from an event's gallery", Tag: "photos", Auth: true,
+		Status: 200, Response: v1.Gallery{}},
+	{Method: "PATCH", Path: "/vendor/events/tickets", ID: "checkInTicket", Summary: "Check in a ticket at the door", Tag: "tickets", Auth: true,
+		Body: models.TicketCheckBodyParams{}, Status: 200},
+	{Method: "POST", Path: "/vendor/events/tickets/create", ID: "createTickets", Summary: "Queue minted tickets to be recorded", Tag: "tickets", Auth: true, Idempotent: true,
+		Body: models.TicketCreatePostBodyParams{}, Status: 202},
+	{Method: "PATCH", Path: "/vendor/events/{id}/capacity", ID: "updateEventCapacity", Summary: "Change how many tickets an event has, down to the tickets already sold, minting any new ones", Tag: "tickets", Auth: true, Conditional: true,
+		Body: models.EventCapacityPatchBodyParams{}, Status: 200, Response: query.AppEvent{}, V1: v1.Event{}},
+	{Method: "GET", Path: "/vendor/events/{id}/tickets/export", ID: "exportTickets", Summary: "Download an event's tickets, or a 202 with a TicketExport when it is exported in the background", Tag: "tickets", Auth: true,
+		Query: []openapi.Parameter{
+			queryParam("Format", "string", "csv (default) or ndjson"),
+			queryParam("Async", "boolean", "Always export in the background, as events with over 5000 tickets are"),
+		},
+		Status: 200, Response: json.RawMessage{}},
+	{Method: "GET", Path: "/vendor/exports/{id}", ID: "getTicketExport", Summary: "Get a background ticket export, with a download link once it is done", Tag: "tickets", Auth: true,
+		Status: 200, Response: v1.TicketExport{}},
+	{Method: "GET", Path: "/vendor/analytics", ID: "getVendorAnalytics", Summary: "Ticket sales and attendance of the signed in vendor's events", Tag: "tickets", Auth: true,
+		Query: []openapi.Parameter{
+			queryParam("From", "string", "Only events starting at or after this ISO 8601 datetime"),
+			queryParam("To", "string", "Only events starting before this ISO 8601 datetime"),
+		},
+		Status: 200, Response: v1.VendorAnalytics{}},
+
+	{Method: "GET", Path: "/admin/photos", ID: "listPhotosForReview", Summary: "List gallery photos by moderation status, oldest first", Tag: "admin", Auth: true,
+		Query: append([]openapi.Parameter{
+			queryParam("Status", "string", "pending (default), approved or rejected"),
+		}, paginationParams...),
+		Status: 200, Response: pagedMedia},
+	{Method: "PUT", Path: "/admin/photos/{id}/review", ID: "reviewPhoto", Summary: "Approve or reject a gallery photo", Tag: "admin", Auth: true,
+		Body: models.PhotoReviewPutBodyParams{}, Status: 200, Response: v1.Media{}},
+
+	{Method: "GET", Path: "/user/events", ID: "searchEvents", Summary: "Search upcoming events", Tag: "user",
+		Query: append([]openapi.Parameter{
+			queryParam("Search", "string", "Full text search, ranked by relevance"),
+			queryParam("Name", "string", ""),
+			queryParam("Type", "string", ""),
+			queryParam("Basecost", "number", "Maximum base cost"),
+			queryParam("EventDatetime", "string", "Only events at or after this ISO 8601 datetime"),
+			queryParam("Zip", "string", ""),
+			queryParam("Latitude", "number", "Centre of a location search, sorts results by distance"),
+			queryParam("Longitude", "number", "Centre of a location search, sorts results by distance"),
+			queryParam("Radius", "number", "Location search radius in km, default 50 and at most 500"),
+		}, paginationParams...),
+		Status: 200, Response: pagedUserEvents, V1: v1PagedUserEvents},
+	{Method: "GET", Path: "/user/events/{id}", ID: "getUserEvent", Summary: "Get an event's public details", Tag: "user",
+		Status: 200, Response: models.UserEventPage{}, V1: v1.EventDetails{}},
+	{Method: "GET", Path: "/user/notifications/preferences", ID: "getNotificationPreferences", Summary: "Get which notifications the signed in user gets", Tag: "user", Auth: true,
+		Status: 200, Response: v1.NotificationPreferences{}},
+	{Method: "PUT", Path: "/user/notifications/preferences", ID: "setNotificationPreferences", Summary: "Set which notifications the signed in user gets, and the email they go to", Tag: "user", Auth: true,
+		Body: models.NotificationPreferencesPutBodyParams{}, Status: 200, Response: v1.NotificationPreferences{}},
+	{Method: "POST", Path: "/user/notifications/push-tokens", ID: "registerPushToken", Summary: "Send push notifications to a device of the signed in user", Tag: "user", Auth: true,
+		Body: models.PushTokenBodyParams{}, Status: 200},
+	{Method: "DELETE", Path: "/user/notifications/push-tokens", ID: "deletePushToken", Summary: "Stop push notifications to a device", Tag: "user", Auth: true,
+		Body: models.PushTokenBodyParams{}, Status: 200},
+
+	{Method: "GET", Path: "/oklink", ID: "getTokenBalances", Summary: "Proxy to OKLink's address balance API", Tag: "user", Auth: true,
+		Query: []openapi.Parameter{
+			requiredQueryParam("wallet", "string"),
+			requiredQueryParam("chainShortName", "string"),
+			requiredQueryParam("tokenContractAddress", "string"),
+		},
+		Status: 200, Response: json.RawMessage{}},
+	{Method: "GET", Path: "/openapi", ID: "getOpenAPI", Summary: "This document", Tag: "meta",
+		Status: 200, Response: json.RawMessage{}},
+}
+
+func queryParam(name string, typ string, description string) openapi.Parameter {
+	return openapi.Parameter{Name: name, In: "query", Description: description, Schema: &openapi.Schema{Type: typ}}
+}
+
+func requiredQueryParam(name string, typ string) openapi.Parameter {
+	p := queryParam(name, typ, "")
+	p.Required = true
+	return p
+}
+
+// Builds the OpenAPI document of the API from the routes and the Go types of
+// their bodies.
+func Document() *openapi.Document {
+	g := openapi.NewGenerator()
+	g.NotNull(query.AppEvent{}, "EventDatetime", "EndDatetime")
+	g.NotNull(query.UserGetEventByUuidRow{}, "EventDatetime", "EndDatetime")
+	g.NotNull(query.UserGetEventsPaginatedRow{}, "EventDatetime")
+	g.NotNull(query.VendorGetVenueCapacityConflictsRow{}, "EventDatetime")
+	g.NotNull(query.VendorGetOverlappingEventsRow{}, "EventDatetime", "EndDatetime")
+	g.NotNull(query.AppEvent{}, "UpdatedAt")
+	g.NotNull(query.AppVenue{}, "UpdatedAt")
+	g.NotNull(query.AppMedia{}, "CreatedAt")
+	g.Nullable(models.EventPatchBodyParams{}, "Disclaimer", "TransactionHash")
+	g.Nullable(models.GalleryPhotoPatchBodyParams{}, "Caption", "AltText")
+
+	doc := &openapi.Document{
+		OpenAPI: "3.0.3",
+		Info: openapi.Info{
+			Title:       "OpenTix API",
+			Description: "Generated from the request and response types in apps/api. Do not edit by hand, run go generate ./apps/api/spec. Paths under /v1 return the v1 response format, which unprefixed paths also return when sent the API-Version: 1 header.",
+			Version:     "0.1.0",
+		},
+		Components: openapi.Components{
+			SecuritySchemes: map[string]*openapi.SecurityScheme{
+				"bearer": {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+			},
+		},
+	}
+
+	for _, r := range routes {
+		doc.AddOperation(r.Method, r.Path, operation(g, r, r.ID, r.Response))
+		// Every route is also served under the /v1 prefix, see shared.Versioning
+		if r.Path == "/openapi" {
+			continue
+		}
+		response := r.Response
+		if r.V1 != nil {
+			response = r.V1
+		}
+		doc.AddOperation(r.Method, "/v1"+r.Path, operation(g, r, r.ID+"V1", response))
+	}
+
+	doc.Components.Schemas = g.Schemas
+	return doc
+}
+
+func operation(g *openapi.Generator, r route, id string, response interface{}) *openapi.Operation {
+	errorResponse := g.SchemaOf(shared.ErrorResponse{})
+	op := &openapi.Operation{
+		OperationID: id,
+		Summary:     r.Summary,
+		Tags:        []string{r.Tag},
+		Parameters:  slices.Clone(r.Query),
+		Responses: map[string]*openapi.Response{
+			"default": {Description: "Error", Content: openapi.JSONContent(errorResponse)},
+		},
+	}
+
+	for _, segment := range strings.Split(r.Path, "/") {
+		if strings.HasPrefix(segment, "{") {
+			op.Parameters = append(op.Parameters, openapi.Parameter{
+				Name:     strings.Trim(segment, "{}"),
+				In:       "path",
+				Required: true,
+				Schema:   &openapi.Schema{Type: "string"},
+			})
+		}
+	}
+
+	if r.Auth {
+		op.Security = []map[string][]string{{"bearer": {}}}
+		op.Responses["401"] = &openapi.Response{Description: "Missing or invalid token", Content: openapi.JSONContent(errorResponse)}
+	}
+
+	if r.Idempotent {
+		op.Parameters = append(op.Parameters, openapi.Parameter{
+			Name:        shared.IdempotencyKeyHeader,
+			In:          "header",
+			Description: "Unique key making retries of this request safe for 24 hours",
+			Schema:      &openapi.Schema{Type: "string", MaxLength: &maxIdempotencyKeyLength},
+		})
+		op.Responses["409"] = &openapi.Response{Description: "Idempotency-Key reused for a different request or while in progress", Content: openapi.JSONContent(errorResponse)}
+	}
+
+	if r.Conditional {
+		op.Parameters = append(op.Parameters, openapi.Parameter{
+			Name:        "If-Match",
+			In:          "header",
+			Description: "ETag the record was read with, the update is refused if it changed since",
+			Schema:      &openapi.Schema{Type: "string"},
+		})
+		op.Responses["412"] = &openapi.Response{Description: "The record changed since the If-Match ETag was read", Content: openapi.JSONContent(errorResponse)}
+	}
+
+	if r.Conflict != nil {
+		op.Responses["409"] = &openapi.Response{Description: "The change conflicts with related records", Content: openapi.JSONContent(g.SchemaOf(r.Conflict))}
+	}
+
+	if r.Body != nil {
+		op.RequestBody = &openapi.RequestBody{Required: true, Content: openapi.JSONContent(g.SchemaOf(r.Body))}
+		op.Responses["422"] = &openapi.Response{Description: "Invalid fields", Content: openapi.JSONContent(g.SchemaOf(shared.FieldErrorResponse{}))}
+	}
+
+	success := &openapi.Response{Description: "OK"}
+	switch response := response.(type) {
+	case nil:
+	case page:
+		success.Content = openapi.JSONContent(g.Define(response.Name, pageSchema(g, response)))
+	default:
+		success.Content = openapi.JSONContent(g.SchemaOf(response))
+	}
+	op.Responses[strconv.Itoa(r.Status)] = success
+	return op
+}
+
+func pageSchema(g *openapi.Generator, p page) *openapi.Schema {
+	if p.V1 {
+		return &openapi.Schema{
+			Type: "object",
+			Properties: map[string]*openapi.Schema{
+				"items":      {Type: "array", Items: g.SchemaOf(p.Item)},
+				"nextCursor": {Type: "string", Nullable: true, Description: "Null on the last page"},
+				"totalCount": {Type: "integer", Format: "int64"},
+			},
+			Required: []string{"items", "nextCursor", "totalCount"},
+		}
+	}
+	return &openapi.Schema{
+		Type: "object",
+		Properties: map[string]*openapi.Schema{
+			"items":       {Type: "array", Items: g.SchemaOf(p.Item)},
+			"next_cursor": {Type: "string", Description: "Empty on the last page"},
+			"total_count": {Type: "integer", Format: "int64"},
+		},
+		Required: []string{"items", "next_cursor", "total_count"},
+	}
+}
@@ -0,0 +1,62 @@
+// Writes the OpenAPI document of the API and the TypeScript types generated
+// from it. With -check nothing is written and it exits non-zero if either file
+// is out of date, which CI uses to catch handler types changing without the
+// document being regenerated.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/opentix/platform/apps/api/spec"
+	"github.com/opentix/platform/packages/gohelpers/packages/openapi"
+)
+
+const tsHeader = `// Code generated by apps/api/spec/gen from the OpenAPI document. DO NOT EDIT.
+// Run go generate ./apps/api/spec after changing request or response types.
+`
+
+func main() {
+	jsonPath := flag.String("json", "apps/api/spec/openapi.json", "where to write the OpenAPI document")
+	tsPath := flag.String("ts", "packages/types/src/lib/api.gen.ts", "where to write the TypeScript types")
+	check := flag.Bool("check", false, "only check that the files are up to date")
+	flag.Parse()
+
+	doc := spec.Document()
+
+	docJSON, err := json.MarshalIndent(doc, "", "\t")
+	if err != nil {
+		log.Fatalf("Failed to marshal document: %v", err)
+	}
+	docJSON = append(docJSON, '\n')
+
+	files := []struct {
+		path    string
+		content []byte
+	}{
+		{*jsonPath, docJSON},
+		{*tsPath, []byte(openapi.TypeScript(doc, tsHeader))},
+	}
+
+	stale := false
+	for _, f := range files {
+		if *check {
+			current, err := os.ReadFile(f.path)
+			if err != nil || !bytes.Equal(current, f.content) {
+				fmt.Fprintf(os.Stderr, "%v is out of date, run go generate ./apps/api/spec\n", f.path)
+				stale = true
+			}
+			continue
+		}
+		if err := os.WriteFile(f.path, f.content, 0644); err != nil {
+			log.Fatalf("Failed to write %v: %v", f.path, err)
+		}
+	}
+	if stale {
+		os.Exit(1)
+	}
+}
@@ -12,9 +12,12 @@ import (
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
 
+	"github.com/opentix/platform/apps/api/models"
+	v1 "github.com/opentix/platform/apps/api/models/v1"
 	"github.com/opentix/platform/apps/api/shared"
 	"github.com/opentix/platform/packages/gohelpers/packages/database"
 	"github.com/opentix/platform/packages/gohelpers/packages/query"
+	"github.com/opentix/platform/packages/gohelpers/packages/search"
 )
 
 var connStr string
@@ -22,21 +25,34 @@ var connStr string
 // ISO 8601
 const time_layout string = "2006-01-02T15:04:05.999Z"
 
+// Radius in miles used for location searches that don't specify one, and the
+// largest radius a client may ask for
+const defaultRadius float64 = 50
+const maxRadius float64 = 500
+
 // Type for unmarshalling query params
 type eventGetQueryParams struct {
-	PageNum string `json:"Page"`
 	ZipCode string `json:"Zip"`
+	Search  string `json:"Search"`
 	Name    string `json:"Name"`
 	Type    string `json:"Type"`
 	Cost    string `json:"Basecost"`
 	Time    string `json:"EventDatetime"`
+	Lat     string `json:"Latitude"`
+	Lng     string `json:"Longitude"`
+	Radius  string `json:"Radius"`
 }
 
 func init() {
 	connStr = database.BuildDatabaseConnectionString()
 }
 
-func handleGetByUuid(ctx context.Context, request events.APIGatewayProxyRequest, id string) (events.APIGatewayProxyResponse, error) {
+func handleGetOne(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	u, err := uuid.Parse(shared.PathParam(request, "id"))
+	if err != nil {
+		return shared.CreateErrorResponseAndLogError(400, "Invalid UUID", request.Headers, err)
+	}
+
 	// Connect to the database
 	conn, err := database.ConnectToDatabase(ctx, connStr)
 	if err != nil {
@@ -46,18 +62,30 @@ func handleGetByUuid(ctx context.Context, request events.APIGatewayProxyRequest,
 
 	queries := query.New(conn)
 
-	u, err := uuid.Parse(id)
+	// Get events for current page
+	dbResponse, err := queries.UserGetEventByUuid(ctx, u)
 	if err != nil {
-		return shared.CreateErrorResponseAndLogError(400, "Invalid UUID", request.Headers, err)
+		return shared.CreateAPIErrorResponse(shared.FromDBError(err, "Event not found"), request.Headers)
 	}
 
-	// Get events for current page
-	dbResponse, err := queries.UserGetEventByUuid(ctx, u)
+	gallery, err := queries.UserGetEventMedia(ctx, u)
 	if err != nil {
-		return shared.CreateErrorResponseAndLogError(500, "Unable to get response from database or malformed query", request.Headers, err)
+		return shared.CreateAPIErrorResponse(shared.FromDBError(err, "Event not found"), request.Headers)
+	}
+	if gallery == nil {
+		gallery = []query.AppMedia{}
+	}
+	// Who reviewed a photo and its hash are only for admins
+	for i := range gallery {
+		gallery[i].PhotoHash = pgtype.Int8{}
+		gallery[i].ReviewedBy = pgtype.Text{}
+	}
+
+	if shared.GetVersion(ctx) >= shared.Version1 {
+		return shared.CreateJSONResponse(200, v1.NewEventDetails(dbResponse, gallery), request.Headers)
 	}
 
-	responseBody, err := json.Marshal(dbResponse)
+	responseBody, err := json.Marshal(models.UserEventPage{UserGetEventByUuidRow: dbResponse, Gallery: gallery})
 	if err != nil {
 		return shared.CreateErrorResponseAndLogError(500, "Failed to marshal response", request.Headers, err)
 	}
@@ -70,30 +98,28 @@ func handleGetByUuid(ctx context.Context, request events.APIGatewayProxyRequest,
 }
 
 func handleGet(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
-	tmpid, ok := request.QueryStringParameters["ID"]
-	if ok {
-		return handleGetByUuid(ctx, request, tmpid)
-	}
 	// Set default parameters
 	var params eventGetQueryParams = eventGetQueryParams{
-		PageNum: "",
 		ZipCode: "",
+		Search:  "",
 		Name:    "",
 		Type:    "",
 		Cost:    "",
 		Time:    "",
+		Lat:     "",
+		Lng:     "",
+		Radius:  "",
 	}
 
 	// Easiest way to get query parameters out
 	tmp, _ := json.Marshal(request.QueryStringParameters)
 	err := json.Unmarshal(tmp, &params)
 	if err != nil {
-		return shared.CreateErrorResponseAndLogError(404, "Could not get Query Params", request.Headers, err)
+		return shared.CreateErrorResponseAndLogError(400, "Could not get Query Params", request.Headers, err)
 	}
 
 	// Parse the parameters that are not strings
 	var tstamp pgtype.Timestamptz
-	var page int32
 	var cost float64
 
 	// Set time to a really low value to show all events if not provided
@@ -108,16 +134,17 @@ func handleGet(ctx context.Context, request events.APIGatewayProxyRequest) (even
 		}
 	}
 
-	// Default to page 1
-	if params.PageNum == "" {
-		page = 1
-	} else {
-		p, err := strconv.ParseInt(params.PageNum, 10, 32)
-		page = int32(p)
-		if err != nil {
-			page = 1
-		}
+	limit, err := shared.GetPageSizeFromRequest(request, 5)
+	if err != nil {
+		return shared.CreateErrorResponse(400, "Invalid Limit", request.Headers)
+	}
+
+	cursor, hasCursor, err := shared.GetCursorFromRequest(request)
+	if err != nil {
+		return shared.CreateErrorResponse(400, "Invalid Cursor", request.Headers)
 	}
+	var cursorTime pgtype.Timestamptz
+	cursorTime.Scan(cursor.EventDatetime)
 
 	// Set cost to a large number so that all events will be displayed if not provided
 	if params.Cost == "" {
@@ -129,6 +156,34 @@ func handleGet(ctx context.Context, request events.APIGatewayProxyRequest) (even
 		}
 	}
 
+	// Location search needs both coordinates, sending only one is an error
+	var latitude, longitude float64
+	var radius float64 = defaultRadius
+	useLocation := params.Lat != "" || params.Lng != ""
+	if useLocation {
+		latitude, err = strconv.ParseFloat(params.Lat, 64)
+		if err != nil || latitude < -90 || latitude > 90 {
+			return shared.CreateErrorResponse(400, "Invalid latitude parameter", request.Headers)
+		}
+		longitude, err = strconv.ParseFloat(params.Lng, 64)
+		if err != nil || longitude < -180 || longitude > 180 {
+			return shared.CreateErrorResponse(400, "Invalid longitude parameter", request.Headers)
+		}
+		if params.Radius != "" {
+			radius, err = strconv.ParseFloat(params.Radius, 64)
+			if err != nil || radius <= 0 {
+				return shared.CreateErrorResponse(400, "Invalid radius parameter", request.Headers)
+			}
+			radius = min(radius, maxRadius)
+		}
+	}
+
+	// Name predates full-text search and is still sent by older clients
+	if params.Search == "" {
+		params.Search = params.Name
+	}
+	params.Search = strings.TrimSpace(params.Search)
+
 	params.ZipCode = strings.ReplaceAll(strings.ReplaceAll(params.ZipCode, " ", ""), "%20", "")
 	var zip_codes []string = []string{}
 	if params.ZipCode != "" {
@@ -142,22 +197,79 @@ func handleGet(ctx context.Context, request events.APIGatewayProxyRequest) (even
 	}
 	defer conn.Close(ctx)
 
-	// Get events for specified page
+	// Get events for specified page, fetching one extra row to know if there is another page
 	queries := query.New(conn)
+	tsquery := search.PrefixQuery(params.Search)
 	dbResponse, err := queries.UserGetEventsPaginated(ctx, query.UserGetEventsPaginatedParams{
-		Column1: page,
-		Column2: zip_codes,
-		Column3: params.Name,
-		Column4: params.Type,
-		Column5: cost,
-		Column6: tstamp,
+		Column1:  limit + 1,
+		Column2:  zip_codes,
+		Column3:  params.Search,
+		Column4:  tsquery,
+		Column5:  params.Type,
+		Column6:  cost,
+		Column7:  tstamp,
+		Column8:  hasCursor,
+		Column9:  cursor.Score,
+		Column10: cursorTime,
+		Column11: cursor.Name,
+		Column12: cursor.Pk,
+		Column13: useLocation,
+		Column14: latitude,
+		Column15: longitude,
+		Column16: radius,
 	})
 
 	if err != nil {
-		return shared.CreateErrorResponseAndLogError(404, "Unable to get response from database or malformed query", request.Headers, err)
+		return shared.CreateAPIErrorResponse(shared.FromDBError(err, "Event not found"), request.Headers)
+	}
+
+	total, err := queries.UserCountEvents(ctx, query.UserCountEventsParams{
+		Column1:  zip_codes,
+		Column2:  params.Search,
+		Column3:  tsquery,
+		Column4:  params.Type,
+		Column5:  cost,
+		Column6:  tstamp,
+		Column7:  useLocation,
+		Column8:  latitude,
+		Column9:  longitude,
+		Column10: radius,
+	})
+	if err != nil {
+		return shared.CreateAPIErrorResponse(shared.FromDBError(err, "Event not found"), request.Headers)
 	}
 
-	responseBody, err := json.Marshal(dbResponse)
+	response := shared.PaginatedResponse{
+		Items:      []query.UserGetEventsPaginatedRow{},
+		TotalCount: total,
+	}
+	if len(dbResponse) > int(limit) {
+		dbResponse = dbResponse[:limit]
+		last := dbResponse[len(dbResponse)-1]
+		// Must match the leading sort key of UserGetEventsPaginated
+		score := -last.Rank
+		if useLocation {
+			score = last.Distance
+		}
+		response.NextCursor = shared.EncodeCursor(shared.Cursor{
+			Score:         score,
+			EventDatetime: last.EventDatetime.Time,
+			Name:          last.Name,
+			Pk:            last.Pk,
+		})
+	}
+	if dbResponse != nil {
+		response.Items = dbResponse
+	}
+
+	if shared.GetVersion(ctx) >= shared.Version1 {
+		items := v1.Map(response.Items.([]query.UserGetEventsPaginatedRow), func(event query.UserGetEventsPaginatedRow) v1.EventSummary {
+			return v1.NewEventSummary(event, useLocation)
+		})
+		return shared.CreateJSONResponse(200, v1.NewPage(items, response.NextCursor, response.TotalCount), request.Headers)
+	}
+
+	responseBody, err := json.Marshal(response)
 	if err != nil {
 		return shared.CreateErrorResponseAndLogError(500, "Failed to marshal response", request.Headers, err)
 	}
@@ -168,19 +280,9 @@ func handleGet(ctx context.Context, request events.APIGatewayProxyRequest) (even
 	}, nil
 }
 
-func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
-	if request.HTTPMethod == "GET" {
-		return handleGet(ctx, request)
-	} else {
-		body, _ := json.Marshal(map[string]string{"message": "Method Not Allowed."})
-		return events.APIGatewayProxyResponse{
-			StatusCode: 405,
-			Body:       string(body),
-			Headers:    shared.GetResponseHeaders(request.Headers),
-		}, nil
-	}
-}
-
 func main() {
-	lambda.Start(Handler)
+	router := shared.NewRouter(shared.DefaultMiddleware...)
+	router.GET("/user/events", handleGet)
+	router.GET("/user/events/{id}", handleGetOne)
+	lambda.Start(router.Serve)
 }
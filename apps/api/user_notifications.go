@@ -0,0 +1,184 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/mail"
+	"regexp"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/opentix/platform/apps/api/models"
+	v1 "github.com/opentix/platform/apps/api/models/v1"
+	"github.com/opentix/platform/apps/api/shared"
+	"github.com/opentix/platform/packages/gohelpers/packages/database"
+	"github.com/opentix/platform/packages/gohelpers/packages/query"
+)
+
+var connStr string
+
+// The tokens Expo hands the apps for push notifications
+var pushTokenPattern = regexp.MustCompile(`^Expo(nent)?PushToken\[[^\]]+\]$`)
+
+func init() {
+	connStr = database.BuildDatabaseConnectionString()
+}
+
+// Everyone gets every notification they have an address for until they say
+// otherwise
+var defaultPreferences = v1.NotificationPreferences{
+	EmailEnabled: true,
+	PushEnabled:  true,
+}
+
+func handleGetPreferences(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	userinfo := shared.GetUserInfo(ctx)
+
+	// Connect to the database
+	conn, err := database.ConnectToDatabase(ctx, connStr)
+	if err != nil {
+		return shared.CreateErrorResponseAndLogError(500, "Failed to connect to the database", request.Headers, err)
+	}
+	defer conn.Close(ctx)
+	queries := query.New(conn)
+
+	preference, err := queries.UserGetNotificationPreference(ctx, userinfo.Wallet)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return shared.CreateJSONResponse(200, defaultPreferences, request.Headers)
+	}
+	if err != nil {
+		return shared.CreateAPIErrorResponse(shared.FromDBError(err, "Preferences not found"), request.Headers)
+	}
+	return shared.CreateJSONResponse(200, v1.NewNotificationPreferences(preference), request.Headers)
+}
+
+func handlePutPreferences(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	userinfo := shared.GetUserInfo(ctx)
+
+	var params models.NotificationPreferencesPutBodyParams
+	if err := shared.DecodeAndValidate(request.Body, &params); err != nil {
+		return shared.CreateValidationErrorResponse(err, request.Headers)
+	}
+	var email pgtype.Text
+	if address := strings.TrimSpace(params.Email); address != "" {
+		parsed, err := mail.ParseAddress(address)
+		if err != nil || parsed.Address != address {
+			return shared.CreateFieldErrorResponse(422, "validation_failed", "One or more fields are invalid", []shared.FieldError{
+				{Field: "Email", Code: "email", Message: "Email must be a valid email address"},
+			}, request.Headers)
+		}
+		email = pgtype.Text{String: address, Valid: true}
+	}
+
+	// Connect to the database
+	conn, err := shared.ConnectToDatabaseAsUser(ctx, connStr)
+	if err != nil {
+		return shared.CreateErrorResponseAndLogError(500, "Failed to connect to the database", request.Headers, err)
+	}
+	defer conn.Close(ctx)
+	queries := query.New(conn)
+
+	preference, err := queries.UserUpsertNotificationPreference(ctx, query.UserUpsertNotificationPreferenceParams{
+		Wallet:       userinfo.Wallet,
+		Email:        email,
+		EmailEnabled: params.EmailEnabled,
+		PushEnabled:  params.PushEnabled,
+	})
+	if err != nil {
+		return shared.CreateAPIErrorResponse(shared.FromDBError(err, "Preferences not found"), request.Headers)
+	}
+	return shared.CreateJSONResponse(200, v1.NewNotificationPreferences(preference), request.Headers)
+}
+
+func decodePushToken(request events.APIGatewayProxyRequest) (string, error) {
+	var params models.PushTokenBodyParams
+	if err := shared.DecodeAndValidate(request.Body, &params); err != nil {
+		return "", err
+	}
+	if !pushTokenPattern.MatchString(params.Token) {
+		return "", &shared.ValidationError{
+			StatusCode: 422,
+			Code:       "validation_failed",
+			Message:    "One or more fields are invalid",
+			Errors: []shared.FieldError{
+				{Field: "Token", Code: "push_token", Message: "Token must be an Expo push token"},
+			},
+		}
+	}
+	return params.Token, nil
+}
+
+// Registers a device of the signed in user for push notifications. A token
+// registered by someone else moves to this user, as the app was signed into
+// another account.
+func handlePostPushToken(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	userinfo := shared.GetUserInfo(ctx)
+
+	token, err := decodePushToken(request)
+	if err != nil {
+		return shared.CreateValidationErrorResponse(err, request.Headers)
+	}
+
+	// Connect to the database
+	conn, err := shared.ConnectToDatabaseAsUser(ctx, connStr)
+	if err != nil {
+		return shared.CreateErrorResponseAndLogError(500, "Failed to connect to the database", request.Headers, err)
+	}
+	defer conn.Close(ctx)
+	queries := query.New(conn)
+
+	err = queries.UserRegisterPushToken(ctx, query.UserRegisterPushTokenParams{
+		Token:  token,
+		Wallet: userinfo.Wallet,
+	})
+	if err != nil {
+		return shared.CreateAPIErrorResponse(shared.FromDBError(err, "Push token not found"), request.Headers)
+	}
+	return events.APIGatewayProxyResponse{
+		StatusCode: 200,
+		Headers:    shared.GetResponseHeaders(request.Headers),
+	}, nil
+}
+
+// Stops push notifications to a device, as when the user signs out of the app
+func handleDeletePushToken(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	userinfo := shared.GetUserInfo(ctx)
+
+	token, err := decodePushToken(request)
+	if err != nil {
+		return shared.CreateValidationErrorResponse(err, request.Headers)
+	}
+
+	// Connect to the database
+	conn, err := shared.ConnectToDatabaseAsUser(ctx, connStr)
+	if err != nil {
+		return shared.CreateErrorResponseAndLogError(500, "Failed to connect to the database", request.Headers, err)
+	}
+	defer conn.Close(ctx)
+	queries := query.New(conn)
+
+	err = queries.UserDeletePushToken(ctx, query.UserDeletePushTokenParams{
+		Token:  token,
+		Wallet: userinfo.Wallet,
+	})
+	if err != nil {
+		return shared.CreateAPIErrorResponse(shared.FromDBError(err, "Push token not found"), request.Headers)
+	}
+	return events.APIGatewayProxyResponse{
+		StatusCode: 200,
+		Headers:    shared.GetResponseHeaders(request.Headers),
+	}, nil
+}
+
+func main() {
+	router := shared.NewRouter(shared.DefaultMiddleware...)
+	router.GET("/user/notifications/preferences", handleGetPreferences, shared.Auth)
+	router.PUT("/user/notifications/preferences", handlePutPreferences, shared.Auth)
+	router.POST("/user/notifications/push-tokens", handlePostPushToken, shared.Auth)
+	router.DELETE("/user/notifications/push-tokens", handleDeletePushToken, shared.Auth)
+	lambda.Start(router.Serve)
+}
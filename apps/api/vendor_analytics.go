@@ -0,0 +1,161 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	v1 "github.com/opentix/platform/apps/api/models/v1"
+	"github.com/opentix/platform/apps/api/shared"
+	"github.com/opentix/platform/packages/gohelpers/packages/database"
+	"github.com/opentix/platform/packages/gohelpers/packages/query"
+)
+
+var connStr string
+
+func init() {
+	connStr = database.BuildDatabaseConnectionString()
+}
+
+func timestamptz(t *time.Time) pgtype.Timestamptz {
+	if t == nil {
+		return pgtype.Timestamptz{}
+	}
+	return pgtype.Timestamptz{Time: *t, Valid: true}
+}
+
+// Running totals of TicketStats over any number of events. Only tickets of
+// events that have ended count towards the no-show rate, the others can still
+// be checked in.
+type tally struct {
+	minted, sold, checkedIn int64
+	endedSold, endedIn      int64
+	revenue                 float64
+}
+
+func (t *tally) add(event query.VendorGetEventAnalyticsRow, now time.Time) {
+	t.minted += event.Minted
+	t.sold += event.Sold
+	t.checkedIn += event.CheckedIn
+	t.revenue += event.Basecost * float64(event.Sold)
+	if event.EndDatetime.Time.Before(now) {
+		t.endedSold += event.Sold
+		t.endedIn += event.CheckedIn
+	}
+}
+
+func (t tally) stats() v1.TicketStats {
+	stats := v1.TicketStats{
+		Minted:    t.minted,
+		Sold:      t.sold,
+		CheckedIn: t.checkedIn,
+		Revenue:   t.revenue,
+	}
+	if t.endedSold > 0 {
+		rate := float64(t.endedSold-t.endedIn) / float64(t.endedSold)
+		stats.NoShowRate = &rate
+	}
+	return stats
+}
+
+// Sales and attendance of the signed in vendor's events, per event, per venue
+// and in total, along with sales and check-ins by hour. From and To pick the
+// events by their start and are both optional. Analytics are only sent in the
+// v1 format.
+func handleGet(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	vendorinfo := shared.GetUserInfo(ctx)
+
+	from, err := shared.GetTimeParam(request, "From")
+	if err != nil {
+		return shared.CreateAPIErrorResponse(err, request.Headers)
+	}
+	to, err := shared.GetTimeParam(request, "To")
+	if err != nil {
+		return shared.CreateAPIErrorResponse(err, request.Headers)
+	}
+	if from != nil && to != nil && !to.After(*from) {
+		return shared.CreateAPIErrorResponse(shared.BadRequest("To must be after From"), request.Headers)
+	}
+
+	conn, err := database.ConnectToDatabase(ctx, connStr)
+	if err != nil {
+		return shared.CreateErrorResponseAndLogError(500, "Failed to connect to the database", request.Headers, err)
+	}
+	defer conn.Close(ctx)
+	queries := query.New(conn)
+
+	if _, err := queries.GetVendorByWallet(ctx, vendorinfo.Wallet); err != nil {
+		return shared.CreateAPIErrorResponse(shared.FromDBError(err, "Vendor does not exist"), request.Headers)
+	}
+
+	rows, err := queries.VendorGetEventAnalytics(ctx, query.VendorGetEventAnalyticsParams{
+		Wallet:  vendorinfo.Wallet,
+		Column2: timestamptz(from),
+		Column3: timestamptz(to),
+	})
+	if err != nil {
+		return shared.CreateAPIErrorResponse(shared.FromDBError(err, "Event not found"), request.Headers)
+	}
+	hours, err := queries.VendorGetTicketActivityByHour(ctx, query.VendorGetTicketActivityByHourParams{
+		Wallet:  vendorinfo.Wallet,
+		Column2: timestamptz(from),
+		Column3: timestamptz(to),
+	})
+	if err != nil {
+		return shared.CreateAPIErrorResponse(shared.FromDBError(err, "Event not found"), request.Headers)
+	}
+
+	now := time.Now()
+	var totals tally
+	// Venues in the order their first event appears
+	venues := map[uuid.UUID]*tally{}
+	var venueOrder []v1.VenueAnalytics
+	eventStats := make([]v1.EventAnalytics, 0, len(rows))
+	for _, row := range rows {
+		var event tally
+		event.add(row, now)
+		totals.add(row, now)
+
+		venue, ok := venues[row.VenueID]
+		if !ok {
+			venue = &tally{}
+			venues[row.VenueID] = venue
+			venueOrder = append(venueOrder, v1.VenueAnalytics{VenueID: row.VenueID, Name: row.VenueName})
+		}
+		venue.add(row, now)
+
+		eventStats = append(eventStats, v1.EventAnalytics{
+			EventID:       row.ID,
+			VenueID:       row.VenueID,
+			Name:          row.Name,
+			EventDatetime: row.EventDatetime.Time.UTC(),
+			EndDatetime:   row.EndDatetime.Time.UTC(),
+			Basecost:      row.Basecost,
+			Tickets:       event.stats(),
+		})
+	}
+	venueStats := make([]v1.VenueAnalytics, 0, len(venueOrder))
+	for _, venue := range venueOrder {
+		venue.Tickets = venues[venue.VenueID].stats()
+		venueStats = append(venueStats, venue)
+	}
+
+	return shared.CreateJSONResponse(200, v1.VendorAnalytics{
+		From:   from,
+		To:     to,
+		Totals: totals.stats(),
+		Venues: venueStats,
+		Events: eventStats,
+		Hourly: v1.Map(hours, v1.NewHourlyAnalytics),
+	}, request.Headers)
+}
+
+func main() {
+	router := shared.NewRouter(shared.DefaultMiddleware...)
+	router.GET("/vendor/analytics", handleGet, shared.Auth)
+	lambda.Start(router.Serve)
+}
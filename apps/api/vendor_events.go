@@ -3,7 +3,7 @@ package main
 import (
 	"context"
 	"encoding/json"
-	"fmt"
+	"errors"
 	"strconv"
 	"strings"
 	"time"
@@ -11,11 +11,16 @@ import (
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 
+	"github.com/opentix/platform/apps/api/models"
+	v1 "github.com/opentix/platform/apps/api/models/v1"
 	"github.com/opentix/platform/apps/api/shared"
 	"github.com/opentix/platform/packages/gohelpers/packages/database"
+	"github.com/opentix/platform/packages/gohelpers/packages/notify"
 	"github.com/opentix/platform/packages/gohelpers/packages/query"
+	"github.com/opentix/platform/packages/gohelpers/packages/search"
 )
 
 var connStr string
@@ -23,66 +28,64 @@ var connStr string
 // ISO 8601
 const time_layout string = "2006-01-02T15:04:05.999Z"
 
-type EventPostBodyParams struct {
-	Vendor      int32
-	Venue       int32   `json:"Venue"`
-	Name        string  `json:"Name"`
-	Type        string  `json:"Type"`
-	Time        string  `json:"EventDatetime"`
-	Description string  `json:"Description"`
-	Disclaimer  string  `json:"Disclaimer"`
-	Basecost    float64 `json:"Basecost"`
-	NumUnique   int32   `json:"NumUnique"`
-	NumGa       int32   `json:"NumGa"`
-}
-
-type EventPatchBodyParams struct {
-	Pk              int32  `json:"Pk"`
-	Venue           int32  `json:"Venue"`
-	Name            string `json:"Name"`
-	Type            string `json:"Type"`
-	Time            string `json:"EventDatetime"`
-	Description     string `json:"Description"`
-	Disclaimer      string `json:"Disclaimer"`
-	Photo           string `json:"Photo"`
-	TransactionHash string `json:"TransactionHash"`
-}
-
 func init() {
 	connStr = database.BuildDatabaseConnectionString()
 }
 
-func handleGetByPk(ctx context.Context, request events.APIGatewayProxyRequest, pk int32, vendorinfo shared.GetWalletAndUUIDFromTokenResponse) (events.APIGatewayProxyResponse, error) {
-	// Connect to the database
-	conn, err := database.ConnectToDatabase(ctx, connStr)
+// v1 refers to venues by uuid rather than pk
+func newV1Event(ctx context.Context, queries *query.Queries, wallet string, event query.AppEvent) (v1.Event, error) {
+	venue, err := queries.VendorGetVenueByPk(ctx, query.VendorGetVenueByPkParams{
+		Pk:     event.Venue,
+		Wallet: wallet,
+	})
 	if err != nil {
-		return shared.CreateErrorResponseAndLogError(500, "Failed to connect to the database", request.Headers, err)
+		return v1.Event{}, err
 	}
-	defer conn.Close(ctx)
+	return v1.NewEvent(event, venue.ID), nil
+}
 
-	queries := query.New(conn)
+// Looks up one of the vendor's events by uuid, or by pk when the id is numeric.
+func getEventByID(ctx context.Context, queries *query.Queries, wallet string, id string) (query.AppEvent, error) {
+	var event query.AppEvent
+	if pk, err := strconv.ParseInt(id, 10, 32); err == nil {
+		event, err = queries.VendorGetEventByPk(ctx, query.VendorGetEventByPkParams{
+			Pk:     int32(pk),
+			Wallet: wallet,
+		})
+		if err != nil {
+			return event, shared.FromDBError(err, "Event not found")
+		}
+		return event, nil
+	}
 
-	// Get events for current page
-	dbResponse, err := queries.VendorGetEventByPk(ctx, query.VendorGetEventByPkParams{
-		Pk:     pk,
-		Wallet: vendorinfo.Wallet,
-	})
+	u, err := uuid.Parse(id)
 	if err != nil {
-		return shared.CreateErrorResponseAndLogError(500, "Unable to get response from database or malformed query", request.Headers, err)
+		return event, shared.BadRequest("Invalid UUID")
 	}
-
-	responseBody, err := json.Marshal(dbResponse)
+	event, err = queries.VendorGetEventByUuid(ctx, query.VendorGetEventByUuidParams{
+		ID:     u,
+		Wallet: wallet,
+	})
 	if err != nil {
-		return shared.CreateErrorResponseAndLogError(500, "Failed to marshal response", request.Headers, err)
+		return event, shared.FromDBError(err, "Event not found")
 	}
-	return events.APIGatewayProxyResponse{
-		StatusCode: 200,
-		Body:       string(responseBody),
-		Headers:    shared.GetResponseHeaders(request.Headers),
-	}, nil
+	return event, nil
 }
 
-func handleGetByUuid(ctx context.Context, request events.APIGatewayProxyRequest, id string, vendorinfo shared.GetWalletAndUUIDFromTokenResponse) (events.APIGatewayProxyResponse, error) {
+// Events at a venue may only overlap when the vendor says so with AllowOverlap,
+// this lists the ones in the way otherwise.
+func createOverlapResponse(overlaps []query.VendorGetOverlappingEventsRow, requestHeaders map[string]string) (events.APIGatewayProxyResponse, error) {
+	return shared.CreateJSONResponse(409, models.EventOverlapResponse{
+		Message: "Event overlaps other events at the venue, set AllowOverlap to schedule it anyway",
+		Code:    "event_overlap",
+		Events:  overlaps,
+	}, requestHeaders)
+}
+
+// The changes made to an event and its tickets, newest first.
+func handleGetHistory(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	vendorinfo := shared.GetUserInfo(ctx)
+
 	// Connect to the database
 	conn, err := database.ConnectToDatabase(ctx, connStr)
 	if err != nil {
@@ -92,64 +95,45 @@ func handleGetByUuid(ctx context.Context, request events.APIGatewayProxyRequest,
 
 	queries := query.New(conn)
 
-	u, err := uuid.Parse(id)
+	event, err := getEventByID(ctx, queries, vendorinfo.Wallet, shared.PathParam(request, "id"))
 	if err != nil {
-		return shared.CreateErrorResponseAndLogError(400, "Invalid UUID", request.Headers, err)
+		return shared.CreateAPIErrorResponse(err, request.Headers)
 	}
+	return shared.CreateHistoryResponse(ctx, queries, "event", event.Pk, request)
+}
 
-	// Get events for current page
-	dbResponse, err := queries.VendorGetEventByUuid(ctx, query.VendorGetEventByUuidParams{
-		ID:     u,
-		Wallet: vendorinfo.Wallet,
-	})
-	if err != nil {
-		return shared.CreateErrorResponseAndLogError(500, "Unable to get response from database or malformed query", request.Headers, err)
-	}
+func handleGetOne(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	vendorinfo := shared.GetUserInfo(ctx)
 
-	responseBody, err := json.Marshal(dbResponse)
+	// Connect to the database
+	conn, err := database.ConnectToDatabase(ctx, connStr)
 	if err != nil {
-		return shared.CreateErrorResponseAndLogError(500, "Failed to marshal response", request.Headers, err)
+		return shared.CreateErrorResponseAndLogError(500, "Failed to connect to the database", request.Headers, err)
 	}
-	return events.APIGatewayProxyResponse{
-		StatusCode: 200,
-		Body:       string(responseBody),
-		Headers:    shared.GetResponseHeaders(request.Headers),
-	}, nil
-}
+	defer conn.Close(ctx)
 
-func handleGet(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
-	// Grab auth token
-	tk, err := shared.GetTokenFromRequest(request)
-	if err != nil {
-		return shared.CreateErrorResponseAndLogError(401, "Error creating token object from DIDToken", request.Headers, err)
-	}
+	queries := query.New(conn)
 
-	// Grab vendor information from token
-	vendorinfo, err := shared.GetWalletAndUUIDFromToken(tk)
+	dbResponse, err := getEventByID(ctx, queries, vendorinfo.Wallet, shared.PathParam(request, "id"))
 	if err != nil {
-		return shared.CreateErrorResponseAndLogError(401, "Error retrieving wallet from token", request.Headers, err)
+		return shared.CreateAPIErrorResponse(err, request.Headers)
 	}
 
-	/* ---- Breakaway for different queries based on input params ---- */
-	tmp, ok := request.QueryStringParameters["Pk"]
-	if ok {
-		pk, err := strconv.ParseInt(tmp, 10, 32)
+	if shared.GetVersion(ctx) >= shared.Version1 {
+		event, err := newV1Event(ctx, queries, vendorinfo.Wallet, dbResponse)
 		if err != nil {
-			return shared.CreateErrorResponse(400, "Invalid pk", request.Headers)
+			return shared.CreateAPIErrorResponse(shared.FromDBError(err, "Venue not found"), request.Headers)
 		}
-		return handleGetByPk(ctx, request, int32(pk), vendorinfo)
-	}
-
-	tmp, ok = request.QueryStringParameters["ID"]
-	if ok {
-		return handleGetByUuid(ctx, request, tmp, vendorinfo)
+		return shared.CreateTaggedJSONResponse(200, event, dbResponse.Version, request.Headers)
 	}
 
-	/* ---- End ---- */
+	return shared.CreateTaggedJSONResponse(200, dbResponse, dbResponse.Version, request.Headers)
+}
 
-	// Return all events as paginated response
+func handleGet(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	vendorinfo := shared.GetUserInfo(ctx)
 
-	tmp, ok = request.QueryStringParameters["EventDatetime"]
+	tmp, ok := request.QueryStringParameters["EventDatetime"]
 	var tstamp pgtype.Timestamptz
 	// Set time to a really low value to show all events if not provided
 	if !ok || tmp == "" {
@@ -166,21 +150,20 @@ func handleGet(ctx context.Context, request events.APIGatewayProxyRequest) (even
 	var filter string = ""
 	tmp, ok = request.QueryStringParameters["Filter"]
 	if ok && tmp != "" {
-		filter = tmp
+		filter = strings.TrimSpace(tmp)
 	}
 
-	// Get query parameters and set defaults if not ok
-	tmp, ok = request.QueryStringParameters["Page"]
-	var page int32
-	if !ok {
-		page = 1
-	} else {
-		p, err := strconv.ParseInt(tmp, 10, 32)
-		page = int32(p)
-		if err != nil {
-			page = 1
-		}
+	limit, err := shared.GetPageSizeFromRequest(request, 25)
+	if err != nil {
+		return shared.CreateErrorResponse(400, "Invalid Limit", request.Headers)
+	}
+
+	cursor, hasCursor, err := shared.GetCursorFromRequest(request)
+	if err != nil {
+		return shared.CreateErrorResponse(400, "Invalid Cursor", request.Headers)
 	}
+	var cursorTime pgtype.Timestamptz
+	cursorTime.Scan(cursor.EventDatetime)
 
 	tmp, ok = request.QueryStringParameters["Venue"]
 	var venue int32
@@ -201,21 +184,69 @@ func handleGet(ctx context.Context, request events.APIGatewayProxyRequest) (even
 	}
 	defer conn.Close(ctx)
 
-	// Get events for current page
+	// Get events for current page, fetching one extra row to know if there is another page
 	queries := query.New(conn)
 	dbResponse, err := queries.VendorGetEventsPaginated(ctx, query.VendorGetEventsPaginatedParams{
-		Column1: page,
-		Wallet:  vendorinfo.Wallet,
-		Column3: venue,
-		Column4: tstamp,
-		Column5: strings.ToLower(filter),
+		Column1:  limit + 1,
+		Wallet:   vendorinfo.Wallet,
+		Column3:  venue,
+		Column4:  tstamp,
+		Column5:  filter,
+		Column6:  search.PrefixQuery(filter),
+		Column7:  hasCursor,
+		Column8:  cursorTime,
+		Column9:  cursor.Name,
+		Column10: cursor.Pk,
 	})
 
 	if err != nil {
-		return shared.CreateErrorResponseAndLogError(500, "Unable to get response from database or malformed query", request.Headers, err)
+		return shared.CreateAPIErrorResponse(shared.FromDBError(err, "Event not found"), request.Headers)
+	}
+
+	total, err := queries.VendorCountEvents(ctx, query.VendorCountEventsParams{
+		Wallet:  vendorinfo.Wallet,
+		Column2: venue,
+		Column3: tstamp,
+		Column4: filter,
+		Column5: search.PrefixQuery(filter),
+	})
+	if err != nil {
+		return shared.CreateAPIErrorResponse(shared.FromDBError(err, "Event not found"), request.Headers)
+	}
+
+	response := shared.PaginatedResponse{
+		Items:      []query.AppEvent{},
+		TotalCount: total,
+	}
+	if len(dbResponse) > int(limit) {
+		dbResponse = dbResponse[:limit]
+		last := dbResponse[len(dbResponse)-1]
+		response.NextCursor = shared.EncodeCursor(shared.Cursor{
+			EventDatetime: last.EventDatetime.Time,
+			Name:          last.Name,
+			Pk:            last.Pk,
+		})
+	}
+	if dbResponse != nil {
+		response.Items = dbResponse
+	}
+
+	if shared.GetVersion(ctx) >= shared.Version1 {
+		venues, err := queries.VendorGetAllVenues(ctx, vendorinfo.Wallet)
+		if err != nil {
+			return shared.CreateAPIErrorResponse(shared.FromDBError(err, "Venue not found"), request.Headers)
+		}
+		venueIDs := make(map[int32]uuid.UUID, len(venues))
+		for _, venue := range venues {
+			venueIDs[venue.Pk] = venue.ID
+		}
+		items := v1.Map(response.Items.([]query.AppEvent), func(event query.AppEvent) v1.Event {
+			return v1.NewEvent(event, venueIDs[event.Venue])
+		})
+		return shared.CreateJSONResponse(200, v1.NewPage(items, response.NextCursor, response.TotalCount), request.Headers)
 	}
 
-	responseBody, err := json.Marshal(dbResponse)
+	responseBody, err := json.Marshal(response)
 	if err != nil {
 		return shared.CreateErrorResponseAndLogError(500, "Failed to marshal response", request.Headers, err)
 	}
@@ -227,58 +258,16 @@ func handleGet(ctx context.Context, request events.APIGatewayProxyRequest) (even
 }
 
 func handlePost(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
-	// Grab auth token
-	tk, err := shared.GetTokenFromRequest(request)
-	if err != nil {
-		return shared.CreateErrorResponseAndLogError(401, "Error creating token object from DIDToken", request.Headers, err)
-	}
-
-	// Grab vendor information from token
-	vendorinfo, err := shared.GetWalletAndUUIDFromToken(tk)
-	if err != nil {
-		return shared.CreateErrorResponseAndLogError(401, "Error retrieving wallet from token", request.Headers, err)
-	}
+	vendorinfo := shared.GetUserInfo(ctx)
 
-	var params EventPostBodyParams = EventPostBodyParams{
-		Vendor:      -1,
-		Venue:       -1,
-		Name:        "",
-		Type:        "",
-		Time:        "",
-		Description: "",
-		Disclaimer:  "",
-		Basecost:    -1,
-		NumUnique:   -1,
-		NumGa:       -1,
-	}
-
-	err = json.Unmarshal([]byte(request.Body), &params)
+	var params models.EventPostBodyParams
+	err := shared.DecodeAndValidate(request.Body, &params)
 	if err != nil {
-		return shared.CreateErrorResponseAndLogError(404, "Could not get Body Params", request.Headers, err)
-	}
-
-	if params.Venue == -1 || params.Name == "" || params.Type == "" || params.Time == "" || params.Description == "" || params.Disclaimer == "" || params.Basecost == -1 || params.NumUnique == -1 || params.NumGa == -1 {
-		return shared.CreateErrorResponseAndLogError(404, "One of the required fields is empty in body request", request.Headers, fmt.Errorf("params = %v\n", params))
-	}
-
-	// Parse the parameters that are not strings
-	var tstamp pgtype.Timestamptz
-	var disclaimer pgtype.Text
-
-	// Set time to a really low value to show all events if not provided
-	tmps := strings.Trim(params.Time, "\x0d\x0a")
-	t, err := time.Parse(time_layout, tmps)
-	tstamp.Scan(t)
-	if err != nil || !tstamp.Valid {
-		return shared.CreateErrorResponseAndLogError(404, "Unable to parse timestamp for event_datetime", request.Headers, err)
-	}
-
-	if disclaimer.Scan(params.Disclaimer) != nil {
-		return shared.CreateErrorResponseAndLogError(404, "Unable to parse photo or disclaimer", request.Headers, err)
+		return shared.CreateValidationErrorResponse(err, request.Headers)
 	}
 
 	// Connect to the database
-	conn, err := database.ConnectToDatabase(ctx, connStr)
+	conn, err := shared.ConnectToDatabaseAsUser(ctx, connStr)
 	if err != nil {
 		return shared.CreateErrorResponseAndLogError(500, "Failed to connect to the database", request.Headers, err)
 	}
@@ -288,141 +277,276 @@ func handlePost(ctx context.Context, request events.APIGatewayProxyRequest) (eve
 
 	resp, err := queries.GetVendorByWallet(ctx, vendorinfo.Wallet)
 	if err != nil {
-		return shared.CreateErrorResponse(404, "Vendor does not exist", request.Headers)
+		return shared.CreateAPIErrorResponse(shared.FromDBError(err, "Vendor does not exist"), request.Headers)
 	}
-	vendor := resp.Pk
+	params.Vendor = resp.Pk
 
-	dbVendor, err := queries.CheckVenueVendorStatus(ctx, params.Venue)
-	if err != nil {
-		return shared.CreateErrorResponse(404, "Vendor does not exist", request.Headers)
+	// The same checks the bulk import makes of every row. They lock the venue,
+	// so the event is created before its capacity can change.
+	var dbVenue query.AppVenue
+	var dbResponse query.AppEvent
+	err = database.InTransaction(ctx, conn, func(queries *query.Queries) error {
+		arg, venue, err := shared.PrepareEvent(ctx, queries, vendorinfo.Wallet, params)
+		if err != nil {
+			return err
+		}
+		dbVenue = venue
+		dbResponse, err = queries.CreateEvent(ctx, arg)
+		return err
+	})
+	var overlapErr *shared.OverlapError
+	if errors.As(err, &overlapErr) {
+		return createOverlapResponse(overlapErr.Events, request.Headers)
 	}
-
-	if dbVendor != vendor {
-		return shared.CreateErrorResponse(401, "You are not authorized to create an event for that venue", request.Headers)
+	var apiErr *shared.APIError
+	var validationErr *shared.ValidationError
+	if errors.As(err, &apiErr) || errors.As(err, &validationErr) {
+		return shared.CreateAPIErrorResponse(err, request.Headers)
 	}
-
-	dbVenue, err := queries.VendorGetVenueByPk(ctx, query.VendorGetVenueByPkParams{
-		Pk:     params.Venue,
-		Wallet: vendorinfo.Wallet,
-	})
 	if err != nil {
-		return shared.CreateErrorResponse(500, "Error retrieving venue from database.", request.Headers)
-	}
-	if (dbVenue.NumGa < params.NumGa) || (dbVenue.NumUnique < params.NumUnique) {
-		return shared.CreateErrorResponse(400, "Number of tickets exceeds venue capacity.", request.Headers)
-	}
-
-	// Get events for current page
-	dbResponse, err := queries.CreateEvent(ctx, query.CreateEventParams{
-		Vendor:        vendor,
-		Venue:         params.Venue,
-		Name:          params.Name,
-		Type:          params.Type,
-		EventDatetime: tstamp,
-		Description:   params.Description,
-		Disclaimer:    disclaimer,
-		Basecost:      params.Basecost,
-		NumUnique:     params.NumUnique,
-		NumGa:         params.NumGa,
-	})
-
-	if err != nil {
-		return shared.CreateErrorResponseAndLogError(500, "Unable to get response from database or malformed query", request.Headers, err)
+		return shared.CreateAPIErrorResponse(shared.FromDBError(err, "Event not found"), request.Headers)
 	}
 
-	responseBody, err := json.Marshal(dbResponse)
-	if err != nil {
-		return shared.CreateErrorResponseAndLogError(500, "Failed to marshal response", request.Headers, err)
+	if shared.GetVersion(ctx) >= shared.Version1 {
+		return shared.CreateTaggedJSONResponse(200, v1.NewEvent(dbResponse, dbVenue.ID), dbResponse.Version, request.Headers)
 	}
-	return events.APIGatewayProxyResponse{
-		StatusCode: 200,
-		Body:       string(responseBody),
-		Headers:    shared.GetResponseHeaders(request.Headers),
-	}, nil
+
+	return shared.CreateTaggedJSONResponse(200, dbResponse, dbResponse.Version, request.Headers)
 }
 
 func handlePatch(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
-	// Grab auth token
-	tk, err := shared.GetTokenFromRequest(request)
-	if err != nil {
-		return shared.CreateErrorResponseAndLogError(401, "Error creating token object from DIDToken", request.Headers, err)
-	}
+	vendorinfo := shared.GetUserInfo(ctx)
 
-	// Grab wallet address from token
-	vendorinfo, err := shared.GetWalletAndUUIDFromToken(tk)
+	// Unmarshal body into models.EventPatchBodyParams
+	var params models.EventPatchBodyParams
+	err := shared.DecodeAndValidate(request.Body, &params)
 	if err != nil {
-		return shared.CreateErrorResponseAndLogError(401, "Error retrieving wallet from token", request.Headers, err)
+		return shared.CreateValidationErrorResponse(err, request.Headers)
 	}
+	// Only the nullable fields can be cleared with an explicit null. Photo can
+	// only be cleared, a cover is set by choosing one of the gallery's approved
+	// photos.
+	nulls := shared.NullFields(request.Body)
 
-	// Unmarshal body into EventPatchBodyParams
-	var params EventPatchBodyParams
-	err = json.Unmarshal([]byte(request.Body), &params)
+	ifMatch, err := shared.GetIfMatchVersion(request)
 	if err != nil {
-		return shared.CreateErrorResponseAndLogError(400, "Invalid body parameters", request.Headers, err)
+		return shared.CreateAPIErrorResponse(err, request.Headers)
 	}
 
 	// Connect to the database
-	conn, err := database.ConnectToDatabase(ctx, connStr)
+	conn, err := shared.ConnectToDatabaseAsUser(ctx, connStr)
 	if err != nil {
 		return shared.CreateErrorResponseAndLogError(500, "Failed to connect to the database", request.Headers, err)
 	}
 	defer conn.Close(ctx)
 	queries := query.New(conn)
 
-	// Process timestamp conversion for Column5.
-	var eventTime pgtype.Timestamptz
+	// Process timestamp conversion for Column5 and Column13.
+	var eventTime, endTime pgtype.Timestamptz
 	if params.Time != "" {
 		tmps := strings.Trim(params.Time, "\x0d\x0a")
 		t, err := time.Parse(time_layout, tmps)
 		eventTime.Scan(t)
 		if err != nil || !eventTime.Valid {
-			return shared.CreateErrorResponseAndLogError(404, "Unable to parse timestamp for event_datetime", request.Headers, err)
+			return shared.CreateErrorResponseAndLogError(400, "Unable to parse timestamp for event_datetime", request.Headers, err)
 		}
 
 	}
+	if params.EndTime != "" {
+		t, err := time.Parse(time_layout, strings.Trim(params.EndTime, "\x0d\x0a"))
+		endTime.Scan(t)
+		if err != nil || !endTime.Valid {
+			return shared.CreateErrorResponseAndLogError(400, "Unable to parse timestamp for end_datetime", request.Headers, err)
+		}
+	}
 
-	// Non-editable: Pk, ID, Vendor, NumUnique, NumGa.
+	// Non-editable: Pk, ID, Vendor. NumUnique and NumGa change through
+	// PATCH /vendor/events/{id}/capacity, which checks them against minted tickets.
 	arg := query.VendorPatchEventParams{
-		Pk:      params.Pk,
-		Wallet:  vendorinfo.Wallet,
-		Column3: params.Name,
-		Column4: params.Type,
-		Column5: eventTime,
-		Column6: params.Description,
-		Column7: params.Disclaimer,
-		Column8: params.Photo,
-		Column9: params.TransactionHash,
+		Pk:       params.Pk,
+		Wallet:   vendorinfo.Wallet,
+		Column3:  params.Name,
+		Column4:  params.Type,
+		Column5:  eventTime,
+		Column6:  params.Description,
+		Column7:  params.Disclaimer,
+		Column8:  params.TransactionHash,
+		Column9:  nulls["Disclaimer"],
+		Column10: nulls["Photo"],
+		Column11: nulls["TransactionHash"],
+		Column12: ifMatch,
+		Column13: endTime,
+	}
+
+	// The event is locked while it is checked and changed, so attendees are
+	// told what changed from the event as it really was and a cancellation
+	// can't slip in between. The outbox is written with the change so neither
+	// is saved without the other.
+	var updatedEvent query.AppEvent
+	err = database.InTransaction(ctx, conn, func(queries *query.Queries) error {
+		current, err := queries.VendorGetEventByPkForUpdate(ctx, query.VendorGetEventByPkForUpdateParams{
+			Pk:     params.Pk,
+			Wallet: vendorinfo.Wallet,
+		})
+		if err != nil {
+			return err
+		}
+		if current.CancelledAt.Valid {
+			return shared.Conflict("Event has been cancelled")
+		}
+
+		// A new time is checked together with whichever end of the event stays put
+		if eventTime.Valid || endTime.Valid {
+			start, end := current.EventDatetime, current.EndDatetime
+			if eventTime.Valid {
+				start = eventTime
+			}
+			if endTime.Valid {
+				end = endTime
+			}
+			if !end.Time.After(start.Time) {
+				return &shared.ValidationError{StatusCode: 422, Code: "validation_failed", Message: "One or more fields are invalid", Errors: shared.EndBeforeStartError}
+			}
+
+			// Events are created with the venue locked too, so no other event
+			// can be moved or added into the same slot before this one is saved
+			_, err = queries.VendorGetVenueByPkForUpdate(ctx, query.VendorGetVenueByPkForUpdateParams{
+				Pk:     current.Venue,
+				Wallet: vendorinfo.Wallet,
+			})
+			if err != nil {
+				return shared.FromDBError(err, "Venue not found")
+			}
+
+			if !params.AllowOverlap {
+				overlaps, err := queries.VendorGetOverlappingEvents(ctx, query.VendorGetOverlappingEventsParams{
+					Venue:   current.Venue,
+					Column2: start,
+					Column3: end,
+					Column4: current.Pk,
+				})
+				if err != nil {
+					return err
+				}
+				if len(overlaps) > 0 {
+					return &shared.OverlapError{Events: overlaps}
+				}
+			}
+		}
+
+		event, err := queries.VendorPatchEvent(ctx, arg)
+		if err != nil {
+			return err
+		}
+		updatedEvent = event
+		// The photo cleared was the gallery's cover
+		if nulls["Photo"] {
+			err = queries.ClearMediaCover(ctx, query.ClearMediaCoverParams{
+				Event: pgtype.Int4{Int32: event.Pk, Valid: true},
+			})
+			if err != nil {
+				return err
+			}
+		}
+		if data, changed := notify.NewEventUpdated(current, updatedEvent); changed {
+			return notify.Enqueue(ctx, queries, notify.EventUpdated, updatedEvent.Pk, data)
+		}
+		return nil
+	})
+	var overlapErr *shared.OverlapError
+	if errors.As(err, &overlapErr) {
+		return createOverlapResponse(overlapErr.Events, request.Headers)
+	}
+	// Refused by the checks above rather than the database
+	var apiErr *shared.APIError
+	var validationErr *shared.ValidationError
+	if errors.As(err, &apiErr) || errors.As(err, &validationErr) {
+		return shared.CreateAPIErrorResponse(err, request.Headers)
+	}
+	if errors.Is(err, pgx.ErrNoRows) && ifMatch != 0 {
+		// Either the event doesn't exist or If-Match is out of date
+		current, currentErr := queries.VendorGetEventByPk(ctx, query.VendorGetEventByPkParams{
+			Pk:     params.Pk,
+			Wallet: vendorinfo.Wallet,
+		})
+		if currentErr == nil {
+			return shared.CreatePreconditionFailedResponse(current.Version, request.Headers)
+		}
 	}
+	if err != nil {
+		return shared.CreateAPIErrorResponse(shared.FromDBError(err, "Event not found"), request.Headers)
+	}
+
+	if shared.GetVersion(ctx) >= shared.Version1 {
+		event, err := newV1Event(ctx, queries, vendorinfo.Wallet, updatedEvent)
+		if err != nil {
+			return shared.CreateAPIErrorResponse(shared.FromDBError(err, "Venue not found"), request.Headers)
+		}
+		return shared.CreateTaggedJSONResponse(200, event, updatedEvent.Version, request.Headers)
+	}
+
+	return shared.CreateTaggedJSONResponse(200, updatedEvent, updatedEvent.Version, request.Headers)
+}
 
-	updatedVenue, err := queries.VendorPatchEvent(ctx, arg)
+// Cancels the event, telling its ticket holders. Cancelled events drop out of
+// listings and can't be changed or sold, but are kept for their history.
+func handlePostCancel(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	vendorinfo := shared.GetUserInfo(ctx)
+
+	// Connect to the database
+	conn, err := shared.ConnectToDatabaseAsUser(ctx, connStr)
 	if err != nil {
-		return shared.CreateErrorResponseAndLogError(404, "Failed to update venue", request.Headers, err)
+		return shared.CreateErrorResponseAndLogError(500, "Failed to connect to the database", request.Headers, err)
 	}
+	defer conn.Close(ctx)
+	queries := query.New(conn)
 
-	responseBody, err := json.Marshal(updatedVenue)
+	event, err := getEventByID(ctx, queries, vendorinfo.Wallet, shared.PathParam(request, "id"))
 	if err != nil {
-		return shared.CreateErrorResponseAndLogError(500, "Failed to marshal updated venue", request.Headers, err)
+		return shared.CreateAPIErrorResponse(err, request.Headers)
+	}
+	if event.CancelledAt.Valid {
+		return shared.CreateAPIErrorResponse(shared.Conflict("Event has already been cancelled"), request.Headers)
 	}
 
-	return events.APIGatewayProxyResponse{
-		StatusCode: 200,
-		Body:       string(responseBody),
-		Headers:    shared.GetResponseHeaders(request.Headers),
-	}, nil
-}
+	var cancelledEvent query.AppEvent
+	err = database.InTransaction(ctx, conn, func(queries *query.Queries) error {
+		cancelled, err := queries.VendorCancelEvent(ctx, query.VendorCancelEventParams{
+			Pk:     event.Pk,
+			Wallet: vendorinfo.Wallet,
+		})
+		if err != nil {
+			return err
+		}
+		cancelledEvent = cancelled
+		return notify.Enqueue(ctx, queries, notify.EventCancelled, cancelled.Pk, notify.NewEventCancelled(cancelled))
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		// Cancelled by another request since it was looked up
+		return shared.CreateAPIErrorResponse(shared.Conflict("Event has already been cancelled"), request.Headers)
+	}
+	if err != nil {
+		return shared.CreateAPIErrorResponse(shared.FromDBError(err, "Event not found"), request.Headers)
+	}
 
-func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
-	if request.HTTPMethod == "GET" {
-		return handleGet(ctx, request)
-	} else if request.HTTPMethod == "POST" {
-		return handlePost(ctx, request)
-	} else if request.HTTPMethod == "PATCH" {
-		return handlePatch(ctx, request)
-	} else {
-		return shared.CreateErrorResponse(405, "Method Not Allowed", request.Headers)
+	if shared.GetVersion(ctx) >= shared.Version1 {
+		response, err := newV1Event(ctx, queries, vendorinfo.Wallet, cancelledEvent)
+		if err != nil {
+			return shared.CreateAPIErrorResponse(shared.FromDBError(err, "Venue not found"), request.Headers)
+		}
+		return shared.CreateTaggedJSONResponse(200, response, cancelledEvent.Version, request.Headers)
 	}
+
+	return shared.CreateTaggedJSONResponse(200, cancelledEvent, cancelledEvent.Version, request.Headers)
 }
 
 func main() {
-	lambda.Start(Handler)
+	router := shared.NewRouter(shared.DefaultMiddleware...)
+	router.GET("/vendor/events", handleGet, shared.Auth)
+	router.GET("/vendor/events/{id}", handleGetOne, shared.Auth)
+	router.GET("/vendor/events/{id}/history", handleGetHistory, shared.Auth)
+	router.POST("/vendor/events", handlePost, shared.Auth, shared.Idempotency(connStr))
+	router.PATCH("/vendor/events", handlePatch, shared.Auth)
+	router.POST("/vendor/events/{id}/cancel", handlePostCancel, shared.Auth)
+	lambda.Start(router.Serve)
 }
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"context"
+	"log"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/opentix/platform/apps/api/models"
+	"github.com/opentix/platform/apps/api/shared"
+	"github.com/opentix/platform/packages/gohelpers/packages/database"
+	"github.com/opentix/platform/packages/gohelpers/packages/geo"
+	"github.com/opentix/platform/packages/gohelpers/packages/query"
+)
+
+// Most rows a single import takes, which keeps it well within the API Gateway
+// timeout
+const maxImportRows = 500
+
+var connStr string
+
+func init() {
+	connStr = database.BuildDatabaseConnectionString()
+	// Venues are geocoded from the bundled zip centroids, and are saved
+	// without coordinates until the dataset has been generated
+	if err := geo.Load(); err != nil {
+		log.Printf("Venues won't be geocoded: %v", err)
+	}
+}
+
+// Creates the record a row describes, returning its uuid. Problems with the row
+// are returned the way shared.InvalidImportRow expects them.
+type createRow func(ctx context.Context, queries *query.Queries, body string) (uuid.UUID, error)
+
+// Runs create for every row in one transaction, each in a savepoint of its own
+// so an invalid row is rolled back without the rows around it. Later rows are
+// checked against the earlier ones, so two rows for the same address are caught
+// like a venue that already exists. A dry run rolls everything back at the end.
+func importRows(ctx context.Context, conn *pgx.Conn, rows []string, dryRun bool, create createRow) (shared.ImportResponse, error) {
+	response := shared.ImportResponse{DryRun: dryRun, Rows: make([]shared.ImportRow, 0, len(rows))}
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return response, err
+	}
+	defer tx.Rollback(ctx)
+
+	for i, body := range rows {
+		savepoint, err := tx.Begin(ctx)
+		if err != nil {
+			return response, err
+		}
+		id, err := create(ctx, query.New(conn).WithTx(savepoint), body)
+		if err != nil {
+			if rollbackErr := savepoint.Rollback(ctx); rollbackErr != nil {
+				return response, rollbackErr
+			}
+			row, ok := shared.InvalidImportRow(i+1, err)
+			if !ok {
+				return response, err
+			}
+			response.Invalid++
+			response.Rows = append(response.Rows, row)
+			continue
+		}
+		if err := savepoint.Commit(ctx); err != nil {
+			return response, err
+		}
+
+		response.Valid++
+		row := shared.ImportRow{Row: i + 1, Status: "created", ID: &id}
+		if dryRun {
+			// The record is rolled back, so its uuid would mean nothing
+			row.Status, row.ID = "valid", nil
+		}
+		response.Rows = append(response.Rows, row)
+	}
+
+	if dryRun {
+		return response, nil
+	}
+	return response, tx.Commit(ctx)
+}
+
+// Decodes the rows of the request and imports them for the signed in vendor.
+// The response is a 200 even when some rows are invalid, their errors are in
+// the rows they belong to.
+func handleImport(ctx context.Context, request events.APIGatewayProxyRequest, sample interface{}, create func(vendor query.AppVendor) createRow) (events.APIGatewayProxyResponse, error) {
+	vendorinfo := shared.GetUserInfo(ctx)
+	dryRun := request.QueryStringParameters["DryRun"] == "true"
+
+	rows, err := shared.DecodeRows(request, sample, maxImportRows)
+	if err != nil {
+		return shared.CreateValidationErrorResponse(err, request.Headers)
+	}
+
+	conn, err := shared.ConnectToDatabaseAsUser(ctx, connStr)
+	if err != nil {
+		return shared.CreateErrorResponseAndLogError(500, "Failed to connect to the database", request.Headers, err)
+	}
+	defer conn.Close(ctx)
+
+	vendor, err := query.New(conn).GetVendorByWallet(ctx, vendorinfo.Wallet)
+	if err != nil {
+		return shared.CreateAPIErrorResponse(shared.FromDBError(err, "Vendor does not exist"), request.Headers)
+	}
+
+	response, err := importRows(ctx, conn, rows, dryRun, create(vendor))
+	if err != nil {
+		return shared.CreateAPIErrorResponse(shared.Internal("Failed to import rows", err), request.Headers)
+	}
+	return shared.CreateJSONResponse(200, response, request.Headers)
+}
+
+// Venues in the shape POST /vendor/venues takes, checked by the same rules
+func handlePostVenues(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	return handleImport(ctx, request, &models.VenuePostBodyParams{}, func(vendor query.AppVendor) createRow {
+		return func(ctx context.Context, queries *query.Queries, body string) (uuid.UUID, error) {
+			params := models.VenuePostBodyParams{Vendor: vendor.Pk}
+			if err := shared.DecodeAndValidate(body, &params); err != nil {
+				return uuid.Nil, err
+			}
+			if err := shared.PrepareVenue(ctx, queries, vendor.Wallet, &params); err != nil {
+				return uuid.Nil, err
+			}
+			venue, err := queries.CreateVenue(ctx, query.CreateVenueParams(params))
+			if err != nil {
+				return uuid.Nil, shared.FromDBError(err, "Venue not found")
+			}
+			return venue.ID, nil
+		}
+	})
+}
+
+// Events in the shape POST /vendor/events takes, checked by the same rules.
+// Venue is the pk of one of the vendor's venues.
+func handlePostEvents(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	return handleImport(ctx, request, &models.EventPostBodyParams{}, func(vendor query.AppVendor) createRow {
+		return func(ctx context.Context, queries *query.Queries, body string) (uuid.UUID, error) {
+			var params models.EventPostBodyParams
+			if err := shared.DecodeAndValidate(body, &params); err != nil {
+				return uuid.Nil, err
+			}
+			params.Vendor = vendor.Pk
+			arg, _, err := shared.PrepareEvent(ctx, queries, vendor.Wallet, params)
+			if err != nil {
+				return uuid.Nil, err
+			}
+			event, err := queries.CreateEvent(ctx, arg)
+			if err != nil {
+				return uuid.Nil, shared.FromDBError(err, "Event not found")
+			}
+			return event.ID, nil
+		}
+	})
+}
+
+func main() {
+	router := shared.NewRouter(shared.DefaultMiddleware...)
+	router.POST("/vendor/venues/import", handlePostVenues, shared.Auth)
+	router.POST("/vendor/events/import", handlePostEvents, shared.Auth)
+	lambda.Start(router.Serve)
+}
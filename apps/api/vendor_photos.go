@@ -4,89 +4,53 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"os"
-	"strings"
+	"log"
 	"time"
 
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
-	"github.com/aws/aws-sdk-go-v2/aws"
-	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
-	"github.com/aws/aws-sdk-go-v2/config"
-	"github.com/aws/aws-sdk-go-v2/service/s3"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 
+	"github.com/opentix/platform/apps/api/models"
+	v1 "github.com/opentix/platform/apps/api/models/v1"
 	"github.com/opentix/platform/apps/api/shared"
 	"github.com/opentix/platform/packages/gohelpers/packages/database"
+	"github.com/opentix/platform/packages/gohelpers/packages/moderation"
+	"github.com/opentix/platform/packages/gohelpers/packages/photo"
 	"github.com/opentix/platform/packages/gohelpers/packages/query"
+	"github.com/opentix/platform/packages/gohelpers/packages/storage"
 )
 
 var connStr string
-var PHOTO_BUCKET string
+var store storage.Store
+
+// Counting photos still being uploaded, until the photo cleanup job removes
+// the ones that never finish
+const maxGalleryPhotos = 20
 
 func init() {
 	connStr = database.BuildDatabaseConnectionString()
-	PHOTO_BUCKET = os.Getenv("PHOTO_BUCKET")
-	if PHOTO_BUCKET == "" {
-		panic("PHOTO_BUCKET must be set")
+	var err error
+	store, err = storage.FromEnv(context.Background())
+	if err != nil {
+		panic("Failed to set up photo storage: " + err.Error())
 	}
 }
 
-type PostVendorPhotoRequest struct {
-	RecordID  string    `json:"ID"`
-	Filename string    `json:"Filename"`
-}
-
-type PostVendorPhotoResponse struct {
-	Request v4.PresignedHTTPRequest
-	ObjectKey string
-}
-
-type DeleteVendorPhotoRequest struct {
-	RecordID  string    `json:"ID"`
-}
-
-func handlePost(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+func handlePost(ctx context.Context, request events.APIGatewayProxyRequest, ImageType string) (events.APIGatewayProxyResponse, error) {
 
 	// Parse the request body.
-	var req PostVendorPhotoRequest
-	if err := json.Unmarshal([]byte(request.Body), &req); err != nil {
-		return shared.CreateErrorResponse(400, "Invalid request body", request.Headers)
-	}
-
-	// Validate the request body.
-	if req.RecordID == "" || req.Filename == "" {
-		return shared.CreateErrorResponse(400, "Missing required fields", request.Headers)
-	}
-
-	var ImageType string
-	if strings.Contains(request.Path, "events") {
-		ImageType = "event"
-	} else if strings.Contains(request.Path, "venues") {
-		ImageType = "venue"
-	} else {
-		return shared.CreateErrorResponse(400, "Invalid path", request.Headers)
-	}
-
-	if ImageType != "event" && ImageType != "venue" {
-		return shared.CreateErrorResponse(400, "Invalid ImageType. event or venue needed.", request.Headers)
-	}
-
-	// Grab auth token
-	tk, err := shared.GetTokenFromRequest(request)
-	if err != nil {
-		return shared.CreateErrorResponseAndLogError(401, "Error creating token object from DIDToken", request.Headers, err)
+	var req models.PostVendorPhotoRequest
+	if err := shared.DecodeAndValidate(request.Body, &req); err != nil {
+		return shared.CreateValidationErrorResponse(err, request.Headers)
 	}
 
-	// Grab wallet address from token
-	vendorinfo, err := shared.GetWalletAndUUIDFromToken(tk)
-	if err != nil {
-		return shared.CreateErrorResponseAndLogError(401, "Error retrieving wallet from token", request.Headers, err)
-	}
+	vendorinfo := shared.GetUserInfo(ctx)
 
 	// Connect to the database
-	conn, err := database.ConnectToDatabase(ctx, connStr)
+	conn, err := shared.ConnectToDatabaseAsUser(ctx, connStr)
 	if err != nil {
 		return shared.CreateErrorResponseAndLogError(500, "Failed to connect to the database", request.Headers, err)
 	}
@@ -98,61 +62,51 @@ func handlePost(ctx context.Context, request events.APIGatewayProxyRequest) (eve
 		return shared.CreateErrorResponse(400, "Invalid RecordID", request.Headers)
 	}
 
-	// Check if the record exists
-	// Check if the vendor is allowed to upload for this record
-	// Check if the record does not already have a photo
-	if ImageType == "event" {
-		// Check if the event exists
-		event, err := queries.VendorGetEventByUuid(ctx, query.VendorGetEventByUuidParams{Wallet: vendorinfo.Wallet, ID: recordUUID})
-		if err != nil {
-			return shared.CreateErrorResponseAndLogError(500, "Failed to get event. Event not found, or vendor is not allowed to modify this event.", request.Headers, err)
-		}
-
-		// Check if the event does not already have a photo
-		if event.Photo.Valid == true {
-			return shared.CreateErrorResponse(400, "Event already has a photo", request.Headers)
-		}
-	} else if ImageType == "venue" {
-		// Check if the venue exists
-		venue, err := queries.VendorGetVenueByUuid(ctx, query.VendorGetVenueByUuidParams{Wallet: vendorinfo.Wallet, ID: recordUUID})
-		if err != nil {
-			return shared.CreateErrorResponseAndLogError(500, "Failed to get venue. Venue not found, or vendor is not allowed to upload for this venue.", request.Headers, err)
-		}
-
-		// Check if the venue does not already have a photo
-		if venue.Photo.Valid == true {
-			return shared.CreateErrorResponse(400, "Venue already has a photo", request.Headers)
-		}
+	// Check the record exists, belongs to the vendor and has room for the photo
+	g, err := getGallery(ctx, queries, vendorinfo.Wallet, ImageType, recordUUID)
+	if err != nil {
+		return shared.CreateAPIErrorResponse(err, request.Headers)
+	}
+	items, err := g.items(ctx, queries)
+	if err != nil {
+		return shared.CreateAPIErrorResponse(shared.FromDBError(err, "Gallery not found"), request.Headers)
+	}
+	if len(items) >= maxGalleryPhotos {
+		return shared.CreateAPIErrorResponse(shared.Conflict(fmt.Sprintf("A gallery can have at most %d photos", maxGalleryPhotos)), request.Headers)
 	}
 
-	// Set default content type.
-	fileType := "application/octet-stream"
-
-	objectKey := req.Filename + "-" + ImageType + "-" +req.RecordID
-
-	// Create an AWS session.
-	cfg, err := config.LoadDefaultConfig(context.TODO(),
-		config.WithRegion("us-east-1"),
-	)
+	// Added to the gallery now so the caption isn't lost, the upload lambda
+	// fills in the photo once it is processed
+	media, err := queries.CreateMedia(ctx, query.CreateMediaParams{
+		Vendor:  g.vendor,
+		Event:   g.event,
+		Venue:   g.venue,
+		Caption: pgtype.Text{String: req.Caption, Valid: req.Caption != ""},
+		AltText: pgtype.Text{String: req.AltText, Valid: req.AltText != ""},
+	})
 	if err != nil {
-		return events.APIGatewayProxyResponse{StatusCode: 500}, fmt.Errorf("failed to create session: %v", err)
+		return shared.CreateAPIErrorResponse(shared.FromDBError(err, "Gallery not found"), request.Headers)
 	}
 
-	// Generate a presigned URL valid for 15 minutes.
-	svc := s3.NewFromConfig(cfg)
-	input := s3.PutObjectInput{
-		Bucket:      aws.String(PHOTO_BUCKET),
-		Key:         aws.String(objectKey),
-		ContentType: aws.String(fileType),
+	// The upload lambda finds the record from the key, so the client's filename
+	// is left out of it
+	objectKey := photo.Upload{RecordType: ImageType, RecordID: recordUUID, MediaID: media.ID}.Key(req.ContentType)
+
+	// Generate a presigned URL valid for 15 minutes. The content type and
+	// length are signed, so the store rejects an upload that doesn't match them.
+	presigned, err := store.PresignUpload(ctx, objectKey, req.ContentType, req.Size, 15*time.Minute)
+	if err != nil {
+		if _, err := queries.DeleteMedia(ctx, media.Pk); err != nil {
+			log.Printf("Failed to remove gallery item %v: %v", media.ID, err)
+		}
+		return shared.CreateAPIErrorResponse(shared.Upstream("Failed to presign upload", err), request.Headers)
 	}
-	presigner := s3.NewPresignClient(svc)
-	presignedURL, err := presigner.PresignPutObject(ctx, &input, s3.WithPresignExpires(15*time.Minute))	
-	
 
 	// Build the response.
-	responseBody, err := json.Marshal(PostVendorPhotoResponse{
-		Request: *presignedURL,
+	responseBody, err := json.Marshal(models.PostVendorPhotoResponse{
+		Request:   presigned,
 		ObjectKey: objectKey,
+		MediaID:   media.ID,
 	})
 	if err != nil {
 		return shared.CreateErrorResponseAndLogError(500, "Failed to marshal response", request.Headers, err)
@@ -161,18 +115,14 @@ func handlePost(ctx context.Context, request events.APIGatewayProxyRequest) (eve
 	return events.APIGatewayProxyResponse{
 		StatusCode: 200,
 		Body:       string(responseBody),
-		Headers: shared.GetResponseHeaders(request.Headers),
+		Headers:    shared.GetResponseHeaders(request.Headers),
 	}, nil
 }
 
-func handleDelete(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
-	var req DeleteVendorPhotoRequest
-	if err := json.Unmarshal([]byte(request.Body), &req); err != nil {
-		return shared.CreateErrorResponse(400, "Invalid request body", request.Headers)
-	}
-
-	if req.RecordID == "" {
-		return shared.CreateErrorResponse(400, "Missing required fields", request.Headers)
+func handleDelete(ctx context.Context, request events.APIGatewayProxyRequest, ImageType string) (events.APIGatewayProxyResponse, error) {
+	var req models.DeleteVendorPhotoRequest
+	if err := shared.DecodeAndValidate(request.Body, &req); err != nil {
+		return shared.CreateValidationErrorResponse(err, request.Headers)
 	}
 
 	recordUUID, err := uuid.Parse(req.RecordID)
@@ -180,33 +130,10 @@ func handleDelete(ctx context.Context, request events.APIGatewayProxyRequest) (e
 		return shared.CreateErrorResponse(400, "Invalid RecordID", request.Headers)
 	}
 
-	var ImageType string
-	if strings.Contains(request.Path, "events") {
-		ImageType = "event"
-	} else if strings.Contains(request.Path, "venues") {
-		ImageType = "venue"
-	} else {
-		return shared.CreateErrorResponse(400, "Invalid path", request.Headers)
-	}
-
-	if ImageType != "event" && ImageType != "venue" {
-		return shared.CreateErrorResponse(400, "Invalid ImageType. event or venue needed.", request.Headers)
-	}
-
-	// Grab auth token
-	tk, err := shared.GetTokenFromRequest(request)
-	if err != nil {
-		return shared.CreateErrorResponseAndLogError(401, "Error creating token object from DIDToken", request.Headers, err)
-	}
-
-	// Grab wallet address from token
-	vendorinfo, err := shared.GetWalletAndUUIDFromToken(tk)
-	if err != nil {
-		return shared.CreateErrorResponseAndLogError(401, "Error retrieving wallet from token", request.Headers, err)
-	}
+	vendorinfo := shared.GetUserInfo(ctx)
 
 	// Connect to the database
-	conn, err := database.ConnectToDatabase(ctx, connStr)
+	conn, err := shared.ConnectToDatabaseAsUser(ctx, connStr)
 	if err != nil {
 		return shared.CreateErrorResponseAndLogError(500, "Failed to connect to the database", request.Headers, err)
 	}
@@ -214,9 +141,25 @@ func handleDelete(ctx context.Context, request events.APIGatewayProxyRequest) (e
 	queries := query.New(conn)
 
 	if ImageType == "event" {
-		event, err := queries.VendorRemoveEventPhoto(ctx, query.VendorRemoveEventPhotoParams{Wallet: vendorinfo.Wallet, ID: recordUUID})
+		// The gallery is kept, it just no longer has a cover
+		var event query.AppEvent
+		err = database.InTransaction(ctx, conn, func(queries *query.Queries) error {
+			removed, err := queries.VendorRemoveEventPhoto(ctx, query.VendorRemoveEventPhotoParams{Wallet: vendorinfo.Wallet, ID: recordUUID})
+			if err != nil {
+				return err
+			}
+			event = removed
+			return queries.ClearMediaCover(ctx, query.ClearMediaCoverParams{Event: pgtype.Int4{Int32: event.Pk, Valid: true}})
+		})
 		if err != nil {
-			return shared.CreateErrorResponseAndLogError(500, "Failed to remove event photo. Potentially, record does not exist or vendor is not authorized for this record.", request.Headers, err)
+			return shared.CreateAPIErrorResponse(shared.FromDBError(err, "Event not found"), request.Headers)
+		}
+		if shared.GetVersion(ctx) >= shared.Version1 {
+			venue, err := queries.VendorGetVenueByPk(ctx, query.VendorGetVenueByPkParams{Pk: event.Venue, Wallet: vendorinfo.Wallet})
+			if err != nil {
+				return shared.CreateAPIErrorResponse(shared.FromDBError(err, "Venue not found"), request.Headers)
+			}
+			return shared.CreateJSONResponse(202, v1.NewEvent(event, venue.ID), request.Headers)
 		}
 		responseBody, err := json.Marshal(event)
 		if err != nil {
@@ -225,12 +168,23 @@ func handleDelete(ctx context.Context, request events.APIGatewayProxyRequest) (e
 		return events.APIGatewayProxyResponse{
 			StatusCode: 202,
 			Body:       string(responseBody),
-			Headers: shared.GetResponseHeaders(request.Headers),
+			Headers:    shared.GetResponseHeaders(request.Headers),
 		}, nil
 	} else {
-		venue, err := queries.VendorRemoveVenuePhoto(ctx, query.VendorRemoveVenuePhotoParams{Wallet: vendorinfo.Wallet, ID: recordUUID})
+		var venue query.AppVenue
+		err = database.InTransaction(ctx, conn, func(queries *query.Queries) error {
+			removed, err := queries.VendorRemoveVenuePhoto(ctx, query.VendorRemoveVenuePhotoParams{Wallet: vendorinfo.Wallet, ID: recordUUID})
+			if err != nil {
+				return err
+			}
+			venue = removed
+			return queries.ClearMediaCover(ctx, query.ClearMediaCoverParams{Venue: pgtype.Int4{Int32: venue.Pk, Valid: true}})
+		})
 		if err != nil {
-			return shared.CreateErrorResponseAndLogError(500, "Failed to remove venue photo. Potentially, record does not exist or vendor is not authorized for this record.", request.Headers, err)
+			return shared.CreateAPIErrorResponse(shared.FromDBError(err, "Venue not found"), request.Headers)
+		}
+		if shared.GetVersion(ctx) >= shared.Version1 {
+			return shared.CreateJSONResponse(202, v1.NewVenue(venue), request.Headers)
 		}
 		responseBody, err := json.Marshal(venue)
 		if err != nil {
@@ -239,27 +193,328 @@ func handleDelete(ctx context.Context, request events.APIGatewayProxyRequest) (e
 		return events.APIGatewayProxyResponse{
 			StatusCode: 202,
 			Body:       string(responseBody),
-			Headers: shared.GetResponseHeaders(request.Headers),
+			Headers:    shared.GetResponseHeaders(request.Headers),
 		}, nil
 	}
 
 }
 
-func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
-	if request.HTTPMethod == "POST" {
-		return handlePost(ctx, request)
-	} else if request.HTTPMethod == "DELETE" {
-		return handleDelete(ctx, request)
+// The gallery of an event or venue belonging to the signed in vendor. Exactly
+// one of event and venue is set, to the record's pk.
+type gallery struct {
+	imageType string
+	id        uuid.UUID
+	vendor    int32
+	event     pgtype.Int4
+	venue     pgtype.Int4
+}
+
+func getGallery(ctx context.Context, queries *query.Queries, wallet string, imageType string, id uuid.UUID) (gallery, error) {
+	if imageType == "event" {
+		event, err := queries.VendorGetEventByUuid(ctx, query.VendorGetEventByUuidParams{Wallet: wallet, ID: id})
+		if err != nil {
+			return gallery{}, shared.FromDBError(err, "Event not found")
+		}
+		return gallery{imageType: imageType, id: id, vendor: event.Vendor, event: pgtype.Int4{Int32: event.Pk, Valid: true}}, nil
+	}
+	venue, err := queries.VendorGetVenueByUuid(ctx, query.VendorGetVenueByUuidParams{Wallet: wallet, ID: id})
+	if err != nil {
+		return gallery{}, shared.FromDBError(err, "Venue not found")
+	}
+	return gallery{imageType: imageType, id: id, vendor: venue.Vendor, venue: pgtype.Int4{Int32: venue.Pk, Valid: true}}, nil
+}
+
+// Looks up the gallery of the {id} path parameter.
+func getGalleryFromPath(ctx context.Context, queries *query.Queries, request events.APIGatewayProxyRequest, imageType string) (gallery, error) {
+	id, err := uuid.Parse(shared.PathParam(request, "id"))
+	if err != nil {
+		return gallery{}, shared.BadRequest("Invalid uuid")
+	}
+	return getGallery(ctx, queries, shared.GetUserInfo(ctx).Wallet, imageType, id)
+}
+
+// Its photos in order, including ones still being processed.
+func (g gallery) items(ctx context.Context, queries *query.Queries) ([]query.AppMedia, error) {
+	return queries.GetMedia(ctx, query.GetMediaParams{Event: g.event, Venue: g.venue})
+}
+
+// Looks up one of its photos by uuid.
+func (g gallery) item(ctx context.Context, queries *query.Queries, id string) (query.AppMedia, error) {
+	u, err := uuid.Parse(id)
+	if err != nil {
+		return query.AppMedia{}, shared.BadRequest("Invalid uuid")
+	}
+	media, err := queries.VendorGetMediaByUuid(ctx, query.VendorGetMediaByUuidParams{ID: u, Wallet: shared.GetUserInfo(ctx).Wallet})
+	if err != nil {
+		return media, shared.FromDBError(err, "Photo not found")
+	}
+	if media.Event != g.event || media.Venue != g.venue {
+		return media, shared.NotFound("Photo not found")
+	}
+	return media, nil
+}
+
+// Makes media the cover, copying its urls onto the event or venue.
+func (g gallery) setCover(ctx context.Context, queries *query.Queries, media query.AppMedia) error {
+	err := queries.ClearMediaCover(ctx, query.ClearMediaCoverParams{Event: g.event, Venue: g.venue})
+	if err != nil {
+		return err
+	}
+	if _, err := queries.SetMediaCover(ctx, media.Pk); err != nil {
+		return err
+	}
+	if g.imageType == "event" {
+		_, err = queries.InsecureUpdateEventPhoto(ctx, query.InsecureUpdateEventPhotoParams{
+			ID:             g.id,
+			Photo:          media.Photo,
+			PhotoThumbnail: media.PhotoThumbnail,
+			PhotoCard:      media.PhotoCard,
+		})
 	} else {
-		body, _ := json.Marshal(map[string]string{"message": "Method Not Allowed."})
-		return events.APIGatewayProxyResponse{
-			StatusCode: 405,
-			Body:       string(body),
-			Headers:    shared.GetResponseHeaders(request.Headers),
-		}, nil
+		_, err = queries.InsecureUpdateVenuePhoto(ctx, query.InsecureUpdateVenuePhotoParams{
+			ID:             g.id,
+			Photo:          media.Photo,
+			PhotoThumbnail: media.PhotoThumbnail,
+			PhotoCard:      media.PhotoCard,
+		})
+	}
+	return err
+}
+
+// Clears the photo of the event or venue once its gallery has no cover.
+func (g gallery) removeCover(ctx context.Context, queries *query.Queries) error {
+	var err error
+	if g.imageType == "event" {
+		_, err = queries.InsecureRemoveEventPhoto(ctx, g.id)
+	} else {
+		_, err = queries.InsecureRemoveVenuePhoto(ctx, g.id)
+	}
+	return err
+}
+
+func createGalleryResponse(ctx context.Context, queries *query.Queries, g gallery, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	items, err := g.items(ctx, queries)
+	if err != nil {
+		return shared.CreateAPIErrorResponse(shared.FromDBError(err, "Gallery not found"), request.Headers)
+	}
+	return shared.CreateJSONResponse(200, v1.NewGallery(items), request.Headers)
+}
+
+// Galleries have no legacy format, they are always sent as v1.
+func handleGetGallery(ctx context.Context, request events.APIGatewayProxyRequest, ImageType string) (events.APIGatewayProxyResponse, error) {
+	conn, err := database.ConnectToDatabase(ctx, connStr)
+	if err != nil {
+		return shared.CreateErrorResponseAndLogError(500, "Failed to connect to the database", request.Headers, err)
+	}
+	defer conn.Close(ctx)
+	queries := query.New(conn)
+
+	g, err := getGalleryFromPath(ctx, queries, request, ImageType)
+	if err != nil {
+		return shared.CreateAPIErrorResponse(err, request.Headers)
+	}
+	return createGalleryResponse(ctx, queries, g, request)
+}
+
+func handlePutOrder(ctx context.Context, request events.APIGatewayProxyRequest, ImageType string) (events.APIGatewayProxyResponse, error) {
+	var req models.GalleryOrderPutBodyParams
+	if err := shared.DecodeAndValidate(request.Body, &req); err != nil {
+		return shared.CreateValidationErrorResponse(err, request.Headers)
+	}
+
+	conn, err := shared.ConnectToDatabaseAsUser(ctx, connStr)
+	if err != nil {
+		return shared.CreateErrorResponseAndLogError(500, "Failed to connect to the database", request.Headers, err)
+	}
+	defer conn.Close(ctx)
+	queries := query.New(conn)
+
+	g, err := getGalleryFromPath(ctx, queries, request, ImageType)
+	if err != nil {
+		return shared.CreateAPIErrorResponse(err, request.Headers)
+	}
+	items, err := g.items(ctx, queries)
+	if err != nil {
+		return shared.CreateAPIErrorResponse(shared.FromDBError(err, "Gallery not found"), request.Headers)
+	}
+
+	// The order has to name every photo once, so none are left without a place
+	pks := map[string]int32{}
+	for _, item := range items {
+		pks[item.ID.String()] = item.Pk
+	}
+	order := make([]int32, 0, len(req.IDs))
+	for _, id := range req.IDs {
+		u, err := uuid.Parse(id)
+		pk, ok := pks[u.String()]
+		if err != nil || !ok {
+			break
+		}
+		delete(pks, u.String())
+		order = append(order, pk)
+	}
+	if len(order) != len(req.IDs) || len(order) != len(items) {
+		return shared.CreateFieldErrorResponse(422, "validation_failed", "Request body failed validation", []shared.FieldError{{
+			Field:   "IDs",
+			Code:    "order",
+			Message: "IDs must list every photo in the gallery exactly once",
+		}}, request.Headers)
+	}
+
+	err = database.InTransaction(ctx, conn, func(queries *query.Queries) error {
+		for position, pk := range order {
+			err := queries.UpdateMediaPosition(ctx, query.UpdateMediaPositionParams{Pk: pk, Position: int32(position)})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return shared.CreateAPIErrorResponse(shared.FromDBError(err, "Photo not found"), request.Headers)
+	}
+	return createGalleryResponse(ctx, queries, g, request)
+}
+
+func handlePutCover(ctx context.Context, request events.APIGatewayProxyRequest, ImageType string) (events.APIGatewayProxyResponse, error) {
+	var req models.GalleryCoverPutBodyParams
+	if err := shared.DecodeAndValidate(request.Body, &req); err != nil {
+		return shared.CreateValidationErrorResponse(err, request.Headers)
+	}
+
+	conn, err := shared.ConnectToDatabaseAsUser(ctx, connStr)
+	if err != nil {
+		return shared.CreateErrorResponseAndLogError(500, "Failed to connect to the database", request.Headers, err)
+	}
+	defer conn.Close(ctx)
+	queries := query.New(conn)
+
+	g, err := getGalleryFromPath(ctx, queries, request, ImageType)
+	if err != nil {
+		return shared.CreateAPIErrorResponse(err, request.Headers)
+	}
+	media, err := g.item(ctx, queries, req.ID)
+	if err != nil {
+		return shared.CreateAPIErrorResponse(err, request.Headers)
+	}
+	if !media.Photo.Valid {
+		return shared.CreateAPIErrorResponse(shared.Conflict("Photo is still being processed"), request.Headers)
+	}
+	if media.Status != string(moderation.Approved) {
+		return shared.CreateAPIErrorResponse(shared.Conflict("Photo has not been approved"), request.Headers)
+	}
+
+	err = database.InTransaction(ctx, conn, func(queries *query.Queries) error {
+		return g.setCover(ctx, queries, media)
+	})
+	if err != nil {
+		return shared.CreateAPIErrorResponse(shared.FromDBError(err, "Photo not found"), request.Headers)
+	}
+	return createGalleryResponse(ctx, queries, g, request)
+}
+
+// Caption and AltText are cleared by sending null, leaving one out keeps it.
+func handlePatchPhoto(ctx context.Context, request events.APIGatewayProxyRequest, ImageType string) (events.APIGatewayProxyResponse, error) {
+	var req models.GalleryPhotoPatchBodyParams
+	if err := shared.DecodeAndValidate(request.Body, &req); err != nil {
+		return shared.CreateValidationErrorResponse(err, request.Headers)
+	}
+	nulls := shared.NullFields(request.Body)
+
+	conn, err := shared.ConnectToDatabaseAsUser(ctx, connStr)
+	if err != nil {
+		return shared.CreateErrorResponseAndLogError(500, "Failed to connect to the database", request.Headers, err)
+	}
+	defer conn.Close(ctx)
+	queries := query.New(conn)
+
+	g, err := getGalleryFromPath(ctx, queries, request, ImageType)
+	if err != nil {
+		return shared.CreateAPIErrorResponse(err, request.Headers)
+	}
+	media, err := g.item(ctx, queries, shared.PathParam(request, "photoId"))
+	if err != nil {
+		return shared.CreateAPIErrorResponse(err, request.Headers)
+	}
+
+	media, err = queries.UpdateMediaDetails(ctx, query.UpdateMediaDetailsParams{
+		Pk:      media.Pk,
+		Column2: req.Caption,
+		Column3: req.AltText,
+		Column4: nulls["Caption"],
+		Column5: nulls["AltText"],
+	})
+	if err != nil {
+		return shared.CreateAPIErrorResponse(shared.FromDBError(err, "Photo not found"), request.Headers)
+	}
+	return shared.CreateJSONResponse(200, v1.NewMedia(media), request.Headers)
+}
+
+// Deleting the cover makes the next approved photo the cover, or clears the
+// record's photo when there is none.
+func handleDeletePhoto(ctx context.Context, request events.APIGatewayProxyRequest, ImageType string) (events.APIGatewayProxyResponse, error) {
+	conn, err := shared.ConnectToDatabaseAsUser(ctx, connStr)
+	if err != nil {
+		return shared.CreateErrorResponseAndLogError(500, "Failed to connect to the database", request.Headers, err)
+	}
+	defer conn.Close(ctx)
+	queries := query.New(conn)
+
+	g, err := getGalleryFromPath(ctx, queries, request, ImageType)
+	if err != nil {
+		return shared.CreateAPIErrorResponse(err, request.Headers)
+	}
+	media, err := g.item(ctx, queries, shared.PathParam(request, "photoId"))
+	if err != nil {
+		return shared.CreateAPIErrorResponse(err, request.Headers)
+	}
+
+	err = database.InTransaction(ctx, conn, func(queries *query.Queries) error {
+		if _, err := queries.DeleteMedia(ctx, media.Pk); err != nil {
+			return err
+		}
+		if !media.IsCover {
+			return nil
+		}
+		items, err := g.items(ctx, queries)
+		if err != nil {
+			return err
+		}
+		for _, item := range items {
+			if item.Photo.Valid && item.Status == string(moderation.Approved) {
+				return g.setCover(ctx, queries, item)
+			}
+		}
+		return g.removeCover(ctx, queries)
+	})
+	if err != nil {
+		return shared.CreateAPIErrorResponse(shared.FromDBError(err, "Photo not found"), request.Headers)
+	}
+	return createGalleryResponse(ctx, queries, g, request)
+}
+
+// Binds a handler to the record type its route is for, "event" or "venue".
+func forImageType(ImageType string, handler func(context.Context, events.APIGatewayProxyRequest, string) (events.APIGatewayProxyResponse, error)) shared.HandlerFunc {
+	return func(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		return handler(ctx, request, ImageType)
 	}
 }
 
 func main() {
-	lambda.Start(Handler)
+	router := shared.NewRouter(shared.DefaultMiddleware...)
+	router.POST("/vendor/events/photos", forImageType("event", handlePost), shared.Auth)
+	router.DELETE("/vendor/events/photos", forImageType("event", handleDelete), shared.Auth)
+	router.POST("/vendor/venues/photos", forImageType("venue", handlePost), shared.Auth)
+	router.DELETE("/vendor/venues/photos", forImageType("venue", handleDelete), shared.Auth)
+	router.GET("/vendor/events/{id}/photos", forImageType("event", handleGetGallery), shared.Auth)
+	router.PUT("/vendor/events/{id}/photos/order", forImageType("event", handlePutOrder), shared.Auth)
+	router.PUT("/vendor/events/{id}/photos/cover", forImageType("event", handlePutCover), shared.Auth)
+	router.PATCH("/vendor/events/{id}/photos/{photoId}", forImageType("event", handlePatchPhoto), shared.Auth)
+	router.DELETE("/vendor/events/{id}/photos/{photoId}", forImageType("event", handleDeletePhoto), shared.Auth)
+	router.GET("/vendor/venues/{id}/photos", forImageType("venue", handleGetGallery), shared.Auth)
+	router.PUT("/vendor/venues/{id}/photos/order", forImageType("venue", handlePutOrder), shared.Auth)
+	router.PUT("/vendor/venues/{id}/photos/cover", forImageType("venue", handlePutCover), shared.Auth)
+	router.PATCH("/vendor/venues/{id}/photos/{photoId}", forImageType("venue", handlePatchPhoto), shared.Auth)
+	router.DELETE("/vendor/venues/{id}/photos/{photoId}", forImageType("venue", handleDeletePhoto), shared.Auth)
+	lambda.Start(router.Serve)
 }
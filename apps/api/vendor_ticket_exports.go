@@ -0,0 +1,161 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime"
+	"slices"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/google/uuid"
+
+	v1 "github.com/opentix/platform/apps/api/models/v1"
+	"github.com/opentix/platform/apps/api/shared"
+	"github.com/opentix/platform/packages/gohelpers/packages/database"
+	"github.com/opentix/platform/packages/gohelpers/packages/export"
+	"github.com/opentix/platform/packages/gohelpers/packages/query"
+	"github.com/opentix/platform/packages/gohelpers/packages/storage"
+)
+
+// Events with more tickets than this are exported by the ticket export job,
+// keeping responses well under the Lambda payload limit
+const maxInlineExport = 5000
+
+// How long a download link of a finished export works for
+const downloadLinkExpiry = 15 * time.Minute
+
+var connStr string
+var store storage.Store
+
+func init() {
+	connStr = database.BuildDatabaseConnectionString()
+
+	var err error
+	store, err = storage.ExportsFromEnv(context.Background())
+	if err != nil {
+		panic(err)
+	}
+}
+
+// The event's tickets as a CSV or NDJSON file, csv unless Format says
+// otherwise. Events with many tickets, or any event when Async is true, are
+// exported in the background instead: the response is a 202 with the export,
+// which GET /vendor/exports/{id} reports on until it can be downloaded.
+func handleGetExport(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	vendorinfo := shared.GetUserInfo(ctx)
+
+	format := export.CSV
+	if tmp, ok := request.QueryStringParameters["Format"]; ok && tmp != "" {
+		format = export.Format(tmp)
+	}
+	if !slices.Contains(export.Formats, format) {
+		return shared.CreateErrorResponse(400, "Format must be csv or ndjson", request.Headers)
+	}
+	async := request.QueryStringParameters["Async"] == "true"
+
+	u, err := uuid.Parse(shared.PathParam(request, "id"))
+	if err != nil {
+		return shared.CreateErrorResponseAndLogError(400, "Error parsing UUID", request.Headers, err)
+	}
+
+	conn, err := shared.ConnectToDatabaseAsUser(ctx, connStr)
+	if err != nil {
+		return shared.CreateErrorResponseAndLogError(500, "Error connecting to database", request.Headers, err)
+	}
+	defer conn.Close(ctx)
+	queries := query.New(conn)
+
+	event, err := shared.GetOwnedEvent(ctx, queries, vendorinfo.Wallet, u)
+	if err != nil {
+		return shared.CreateAPIErrorResponse(err, request.Headers)
+	}
+
+	tickets, err := queries.GetEventTicketSummary(ctx, event.Pk)
+	if err != nil {
+		return shared.CreateAPIErrorResponse(shared.FromDBError(err, "Event not found"), request.Headers)
+	}
+
+	if async || tickets.Minted > maxInlineExport {
+		job, err := queries.VendorCreateTicketExport(ctx, query.VendorCreateTicketExportParams{
+			Event:  event.Pk,
+			Wallet: vendorinfo.Wallet,
+			Format: string(format),
+		})
+		if err != nil {
+			return shared.CreateAPIErrorResponse(shared.FromDBError(err, "Event not found"), request.Headers)
+		}
+		return shared.CreateJSONResponse(202, v1.NewTicketExport(job, ""), request.Headers)
+	}
+
+	rows, err := queries.GetTicketExportRows(ctx, event.Pk)
+	if err != nil {
+		return shared.CreateAPIErrorResponse(shared.FromDBError(err, "Event not found"), request.Headers)
+	}
+	var body bytes.Buffer
+	if err := export.WriteTickets(&body, format, rows); err != nil {
+		return shared.CreateAPIErrorResponse(shared.Internal("Failed to write export", err), request.Headers)
+	}
+
+	headers := shared.GetResponseHeaders(request.Headers)
+	headers["Content-Type"] = format.ContentType()
+	headers["Content-Disposition"] = mime.FormatMediaType("attachment", map[string]string{
+		"filename": export.Filename(event.ID, format),
+	})
+	return events.APIGatewayProxyResponse{
+		StatusCode: 200,
+		Body:       body.String(),
+		Headers:    headers,
+	}, nil
+}
+
+// An export the signed in vendor asked for, with a link to download it once
+// it's done
+func handleGetJob(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	vendorinfo := shared.GetUserInfo(ctx)
+
+	u, err := uuid.Parse(shared.PathParam(request, "id"))
+	if err != nil {
+		return shared.CreateErrorResponseAndLogError(400, "Error parsing UUID", request.Headers, err)
+	}
+
+	conn, err := database.ConnectToDatabase(ctx, connStr)
+	if err != nil {
+		return shared.CreateErrorResponseAndLogError(500, "Error connecting to database", request.Headers, err)
+	}
+	defer conn.Close(ctx)
+	queries := query.New(conn)
+
+	job, err := queries.VendorGetTicketExport(ctx, query.VendorGetTicketExportParams{
+		ID:     u,
+		Wallet: vendorinfo.Wallet,
+	})
+	if err != nil {
+		return shared.CreateAPIErrorResponse(shared.FromDBError(err, "Export not found"), request.Headers)
+	}
+
+	var downloadURL string
+	if job.Status == "done" && job.ObjectKey.Valid {
+		event, err := queries.VendorGetEventByPk(ctx, query.VendorGetEventByPkParams{
+			Pk:     job.Event,
+			Wallet: vendorinfo.Wallet,
+		})
+		if err != nil {
+			return shared.CreateAPIErrorResponse(shared.FromDBError(err, "Event not found"), request.Headers)
+		}
+		downloadURL, err = store.PresignDownload(ctx, job.ObjectKey.String, export.Filename(event.ID, export.Format(job.Format)), downloadLinkExpiry)
+		if err != nil {
+			return shared.CreateAPIErrorResponse(shared.Internal(fmt.Sprintf("Failed to sign download of %s", job.ObjectKey.String), err), request.Headers)
+		}
+	}
+	return shared.CreateJSONResponse(200, v1.NewTicketExport(job, downloadURL), request.Headers)
+}
+
+func main() {
+	router := shared.NewRouter(shared.DefaultMiddleware...)
+	router.GET("/vendor/events/{id}/tickets/export", handleGetExport, shared.Auth)
+	router.GET("/vendor/exports/{id}", handleGetJob, shared.Auth)
+	lambda.Start(router.Serve)
+}
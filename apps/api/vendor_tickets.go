@@ -2,12 +2,12 @@ package main
 
 import (
 	"context"
-	"encoding/json"
 
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
 	"github.com/google/uuid"
 
+	"github.com/opentix/platform/apps/api/models"
 	"github.com/opentix/platform/apps/api/shared"
 	"github.com/opentix/platform/packages/gohelpers/packages/database"
 	"github.com/opentix/platform/packages/gohelpers/packages/query"
@@ -15,43 +15,20 @@ import (
 
 var connStr string
 
-
-type TicketCheckBodyParams struct {
-	Event string `json:"Event"`
-	TicketID int `json:"TicketID"`
-}
-
 func init() {
 	connStr = database.BuildDatabaseConnectionString()
 }
 
 func handlePatch(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
-	// Grab auth token
-	tk, err := shared.GetTokenFromRequest(request)
-	if err != nil {
-		return shared.CreateErrorResponseAndLogError(401, "Error creating token object from DIDToken", request.Headers, err)
-	}
-
-	// Grab vendor information from token
-	vendorinfo, err := shared.GetWalletAndUUIDFromToken(tk)
-	if err != nil {
-		return shared.CreateErrorResponseAndLogError(401, "Error retrieving wallet from token", request.Headers, err)
-	}
+	vendorinfo := shared.GetUserInfo(ctx)
 
-	var params = TicketCheckBodyParams{
-		Event: "",
-		TicketID: -1,
-	}
-
-	err = json.Unmarshal([]byte(request.Body), &params)
+	var params models.TicketCheckBodyParams
+	err := shared.DecodeAndValidate(request.Body, &params)
 	if err != nil {
-		return shared.CreateErrorResponseAndLogError(400, "Error parsing request body", request.Headers, err)
-	}
-	if params.Event == "" || params.TicketID == -1 {
-		return shared.CreateErrorResponse(400, "Missing required parameters", request.Headers)
+		return shared.CreateValidationErrorResponse(err, request.Headers)
 	}
 
-	conn, err := database.ConnectToDatabase(ctx, connStr)
+	conn, err := shared.ConnectToDatabaseAsUser(ctx, connStr)
 	if err != nil {
 		return shared.CreateErrorResponseAndLogError(500, "Error connecting to database", request.Headers, err)
 	}
@@ -63,12 +40,9 @@ func handlePatch(ctx context.Context, request events.APIGatewayProxyRequest) (ev
 	if err != nil {
 		return shared.CreateErrorResponseAndLogError(400, "Error parsing UUID", request.Headers, err)
 	}
-	event, err := queries.VendorGetEventByUuid(ctx, query.VendorGetEventByUuidParams{Wallet: vendorinfo.Wallet, ID: u})
+	event, err := shared.GetOwnedEvent(ctx, queries, vendorinfo.Wallet, u)
 	if err != nil {
-		return shared.CreateErrorResponseAndLogError(500, "Error querying database or vendor does not own event.", request.Headers, err)
-	}
-	if event.ID == uuid.Nil {
-		return shared.CreateErrorResponse(403, "Vendor does not own event", request.Headers)
+		return shared.CreateAPIErrorResponse(err, request.Headers)
 	}
 
 	ticket, err := queries.GetTicket(ctx, query.GetTicketParams{
@@ -76,22 +50,27 @@ func handlePatch(ctx context.Context, request events.APIGatewayProxyRequest) (ev
 		Event: event.Pk,
 	})
 	if err != nil {
-		return shared.CreateErrorResponseAndLogError(500, "Error querying database or ticket doesn't exist", request.Headers, err)
-	}
-	if ticket.Pk == 0 {
-		return shared.CreateErrorResponse(404, "Ticket does not exist", request.Headers)
+		return shared.CreateAPIErrorResponse(shared.FromDBError(err, "Ticket does not exist"), request.Headers)
 	}
 
 	if ticket.CheckedIn {
-		return shared.CreateErrorResponse(400, "Ticket already checked in", request.Headers)
+		return shared.CreateAPIErrorResponse(shared.Conflict("Ticket already checked in"), request.Headers)
 	}
 
-	_, err = queries.UpdateCheckin(ctx, query.UpdateCheckinParams{
-		Pk: ticket.Pk,
-		CheckedIn: true,
+	// A check-in counts the ticket as sold, so it waits for any capacity change
+	// of the event, which is checked against the tickets sold
+	err = database.InTransaction(ctx, conn, func(queries *query.Queries) error {
+		if err := queries.ShareLockEvent(ctx, event.Pk); err != nil {
+			return err
+		}
+		_, err := queries.UpdateCheckin(ctx, query.UpdateCheckinParams{
+			Pk:        ticket.Pk,
+			CheckedIn: true,
+		})
+		return err
 	})
 	if err != nil {
-		return shared.CreateErrorResponseAndLogError(500, "Error updating ticket", request.Headers, err)
+		return shared.CreateAPIErrorResponse(shared.FromDBError(err, "Ticket does not exist"), request.Headers)
 	}
 
 	return events.APIGatewayProxyResponse{
@@ -100,14 +79,8 @@ func handlePatch(ctx context.Context, request events.APIGatewayProxyRequest) (ev
 	}, nil
 }
 
-func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
-	if request.HTTPMethod == "PATCH" {
-		return handlePatch(ctx, request)
-	} else {
-		return shared.CreateErrorResponse(405, "Method Not Allowed", request.Headers)
-	}
-}
-
 func main() {
-	lambda.Start(Handler)
+	router := shared.NewRouter(shared.DefaultMiddleware...)
+	router.PATCH("/vendor/events/tickets", handlePatch, shared.Auth)
+	lambda.Start(router.Serve)
 }
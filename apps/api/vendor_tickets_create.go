@@ -3,7 +3,8 @@ package main
 import (
 	"context"
 	"encoding/json"
-	"log"
+	"errors"
+	"fmt"
 	"os"
 
 	"github.com/aws/aws-lambda-go/events"
@@ -11,7 +12,10 @@ import (
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/sns"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 
+	"github.com/opentix/platform/apps/api/models"
+	v1 "github.com/opentix/platform/apps/api/models/v1"
 	"github.com/opentix/platform/apps/api/shared"
 	"github.com/opentix/platform/packages/gohelpers/packages/database"
 	"github.com/opentix/platform/packages/gohelpers/packages/query"
@@ -21,13 +25,6 @@ var connStr string
 var snsArn string
 var region string
 
-type TicketCreatePostBodyParams struct {
-	Event string `json:"Event"`
-	Contract string `json:"Contract"`
-	TicketMin int `json:"TicketMin"`
-	TicketMax int `json:"TicketMax"`
-}
-
 func init() {
 	connStr = database.BuildDatabaseConnectionString()
 
@@ -38,32 +35,47 @@ func init() {
 	region = "us-east-1"
 }
 
-func handlePost(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
-	// Grab auth token
-	tk, err := shared.GetTokenFromRequest(request)
+// Sends a range of tickets to the ticket creation topic, which records each one
+// that doesn't exist yet
+func publishTicketCreation(ctx context.Context, params models.TicketCreatePostBodyParams) error {
+	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
 	if err != nil {
-		return shared.CreateErrorResponseAndLogError(401, "Error creating token object from DIDToken", request.Headers, err)
+		return shared.Internal("Error loading AWS config", err)
 	}
+	svc := sns.NewFromConfig(cfg)
 
-	// Grab vendor information from token
-	vendorinfo, err := shared.GetWalletAndUUIDFromToken(tk)
+	snsMessage, err := json.Marshal(params)
 	if err != nil {
-		return shared.CreateErrorResponseAndLogError(401, "Error retrieving wallet from token", request.Headers, err)
+		return shared.Internal("Error serializing snsMessage", err)
 	}
-
-	var params = TicketCreatePostBodyParams{
-		Event: "",
-		Contract: "",
-		TicketMin: -1,
-		TicketMax: -1,
+	msg := string(snsMessage)
+	_, err = svc.Publish(
+		ctx,
+		&sns.PublishInput{
+			TopicArn: &snsArn,
+			Message:  &msg,
+		},
+	)
+	if err != nil {
+		return shared.Upstream("Error publishing to SNS", err)
 	}
+	return nil
+}
+
+func handlePost(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	vendorinfo := shared.GetUserInfo(ctx)
 
-	err = json.Unmarshal([]byte(request.Body), &params)
+	var params models.TicketCreatePostBodyParams
+	err := shared.DecodeAndValidate(request.Body, &params)
 	if err != nil {
-		return shared.CreateErrorResponseAndLogError(400, "Error parsing request body", request.Headers, err)
+		return shared.CreateValidationErrorResponse(err, request.Headers)
 	}
-	if params.Event == "" || params.Contract == "" || params.TicketMin == -1 || params.TicketMax == -1 {
-		return shared.CreateErrorResponse(400, "Missing required parameters", request.Headers)
+	if params.TicketMax < params.TicketMin {
+		return shared.CreateFieldErrorResponse(422, "validation_failed", "One or more fields are invalid", []shared.FieldError{{
+			Field:   "TicketMax",
+			Code:    "min",
+			Message: "TicketMax must be at least TicketMin",
+		}}, request.Headers)
 	}
 
 	conn, err := database.ConnectToDatabase(ctx, connStr)
@@ -78,54 +90,165 @@ func handlePost(ctx context.Context, request events.APIGatewayProxyRequest) (eve
 	if err != nil {
 		return shared.CreateErrorResponseAndLogError(400, "Error parsing UUID", request.Headers, err)
 	}
-	event, err := queries.VendorGetEventByUuid(ctx, query.VendorGetEventByUuidParams{Wallet: vendorinfo.Wallet, ID: u})
+	_, err = shared.GetOwnedEvent(ctx, queries, vendorinfo.Wallet, u)
 	if err != nil {
-		return shared.CreateErrorResponseAndLogError(500, "Error querying database or vendor does not own event.", request.Headers, err)
+		return shared.CreateAPIErrorResponse(err, request.Headers)
 	}
-	if event.ID == uuid.Nil {
-		return shared.CreateErrorResponse(403, "Vendor does not own event", request.Headers)
+
+	err = publishTicketCreation(ctx, params)
+	if err != nil {
+		return shared.CreateAPIErrorResponse(err, request.Headers)
 	}
 
-	// Load AWS configuration
-	cfg, err := config.LoadDefaultConfig(context.TODO(), config.WithRegion(region))
+	return events.APIGatewayProxyResponse{
+		StatusCode: 202,
+		Headers:    shared.GetResponseHeaders(request.Headers),
+	}, nil
+}
+
+// Changes how many tickets an event has. Sold tickets can't be taken back, so
+// the new total can't go below them, and neither count can exceed the venue's
+// capacity. The checks and the change are made with the event locked, so the
+// tickets counted can't change in between. Once the event has tickets, raising
+// the total mints the missing ones through the same flow as
+// /vendor/events/tickets/create, numbered after the event's highest ticket.
+func handlePatchCapacity(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	vendorinfo := shared.GetUserInfo(ctx)
+
+	var params models.EventCapacityPatchBodyParams
+	err := shared.DecodeAndValidate(request.Body, &params)
 	if err != nil {
-		log.Println("Error loading AWS config:", err)
-		panic(err)
+		return shared.CreateValidationErrorResponse(err, request.Headers)
 	}
 
-	svc := sns.NewFromConfig(cfg)
+	ifMatch, err := shared.GetIfMatchVersion(request)
+	if err != nil {
+		return shared.CreateAPIErrorResponse(err, request.Headers)
+	}
 
-	// Send ticket mint event to sns
-	snsMessage, err := json.Marshal(params)
+	u, err := uuid.Parse(shared.PathParam(request, "id"))
 	if err != nil {
-		return shared.CreateErrorResponseAndLogError(500, "Error serializing snsMessage", request.Headers, err)
+		return shared.CreateErrorResponseAndLogError(400, "Error parsing UUID", request.Headers, err)
 	}
-	msg := string(snsMessage)
-	_, err = svc.Publish(
-		ctx,
-		&sns.PublishInput{
-			TopicArn: &snsArn,
-			Message:  &msg,
-		},
-	)
+
+	conn, err := shared.ConnectToDatabaseAsUser(ctx, connStr)
 	if err != nil {
-		return shared.CreateErrorResponseAndLogError(500, "Error publishing to SNS", request.Headers, err)
+		return shared.CreateErrorResponseAndLogError(500, "Error connecting to database", request.Headers, err)
 	}
+	defer conn.Close(ctx)
+	queries := query.New(conn)
 
-	return events.APIGatewayProxyResponse{
-		StatusCode: 202,
-		Headers:    shared.GetResponseHeaders(request.Headers),
-	}, nil
-}
+	event, err := shared.GetOwnedEvent(ctx, queries, vendorinfo.Wallet, u)
+	if err != nil {
+		return shared.CreateAPIErrorResponse(err, request.Headers)
+	}
+
+	var venue query.AppVenue
+	var tickets query.GetEventTicketSummaryRow
+	var updatedEvent query.AppEvent
+	total := int64(params.NumUnique) + int64(params.NumGa)
+	err = database.InTransaction(ctx, conn, func(queries *query.Queries) error {
+		current, err := queries.VendorGetEventByPkForUpdate(ctx, query.VendorGetEventByPkForUpdateParams{
+			Pk:     event.Pk,
+			Wallet: vendorinfo.Wallet,
+		})
+		if err != nil {
+			return err
+		}
+		if current.CancelledAt.Valid {
+			return shared.Conflict("Event has been cancelled")
+		}
+
+		// Locked so the venue's capacity can't shrink under the new counts
+		venue, err = queries.VendorGetVenueByPkForUpdate(ctx, query.VendorGetVenueByPkForUpdateParams{
+			Pk:     current.Venue,
+			Wallet: vendorinfo.Wallet,
+		})
+		if err != nil {
+			return shared.FromDBError(err, "Venue not found")
+		}
+
+		tickets, err = queries.GetEventTicketSummary(ctx, current.Pk)
+		if err != nil {
+			return err
+		}
+
+		var capacityErrors []shared.FieldError
+		if venue.NumUnique < params.NumUnique {
+			capacityErrors = append(capacityErrors, shared.FieldError{
+				Field:   "NumUnique",
+				Code:    "max",
+				Message: fmt.Sprintf("NumUnique must be at most the venue's %d unique seats", venue.NumUnique),
+			})
+		}
+		if venue.NumGa < params.NumGa {
+			capacityErrors = append(capacityErrors, shared.FieldError{
+				Field:   "NumGa",
+				Code:    "max",
+				Message: fmt.Sprintf("NumGa must be at most the venue's %d general admission tickets", venue.NumGa),
+			})
+		}
+		if total < tickets.Sold {
+			message := fmt.Sprintf("NumUnique and NumGa must add up to at least the %d tickets already sold", tickets.Sold)
+			capacityErrors = append(capacityErrors,
+				shared.FieldError{Field: "NumUnique", Code: "min", Message: message},
+				shared.FieldError{Field: "NumGa", Code: "min", Message: message},
+			)
+		}
+		if len(capacityErrors) > 0 {
+			return &shared.ValidationError{StatusCode: 422, Code: "validation_failed", Message: "Number of tickets is outside the allowed capacity.", Errors: capacityErrors}
+		}
+
+		updatedEvent, err = queries.VendorUpdateEventCapacity(ctx, query.VendorUpdateEventCapacityParams{
+			Pk:        current.Pk,
+			Wallet:    vendorinfo.Wallet,
+			NumUnique: params.NumUnique,
+			NumGa:     params.NumGa,
+			Column5:   ifMatch,
+		})
+		return err
+	})
+	// Refused by the checks above rather than the database
+	var apiErr *shared.APIError
+	var validationErr *shared.ValidationError
+	if errors.As(err, &apiErr) || errors.As(err, &validationErr) {
+		return shared.CreateAPIErrorResponse(err, request.Headers)
+	}
+	if errors.Is(err, pgx.ErrNoRows) && ifMatch != 0 {
+		// The event was just found, so If-Match is out of date
+		current, currentErr := queries.GetEventByUuid(ctx, u)
+		if currentErr == nil {
+			return shared.CreatePreconditionFailedResponse(current.Version, request.Headers)
+		}
+	}
+	if err != nil {
+		return shared.CreateAPIErrorResponse(shared.FromDBError(err, "Event not found"), request.Headers)
+	}
 
-func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
-	if request.HTTPMethod == "POST" {
-		return handlePost(ctx, request)
-	} else {
-		return shared.CreateErrorResponse(405, "Method Not Allowed", request.Headers)
+	// Before the first mint the new counts are simply what gets minted. Retrying
+	// after a failed publish is safe since existing tickets are skipped.
+	if tickets.Minted > 0 && total > tickets.Minted {
+		err = publishTicketCreation(ctx, models.TicketCreatePostBodyParams{
+			Event:     updatedEvent.ID.String(),
+			Contract:  tickets.Contract,
+			TicketMin: int(tickets.MaxTicketID) + 1,
+			TicketMax: int(tickets.MaxTicketID) + int(total-tickets.Minted),
+		})
+		if err != nil {
+			return shared.CreateAPIErrorResponse(err, request.Headers)
+		}
 	}
+
+	if shared.GetVersion(ctx) >= shared.Version1 {
+		return shared.CreateTaggedJSONResponse(200, v1.NewEvent(updatedEvent, venue.ID), updatedEvent.Version, request.Headers)
+	}
+
+	return shared.CreateTaggedJSONResponse(200, updatedEvent, updatedEvent.Version, request.Headers)
 }
 
 func main() {
-	lambda.Start(Handler)
+	router := shared.NewRouter(shared.DefaultMiddleware...)
+	router.POST("/vendor/events/tickets/create", handlePost, shared.Auth, shared.Idempotency(connStr))
+	router.PATCH("/vendor/events/{id}/capacity", handlePatchCapacity, shared.Auth)
+	lambda.Start(router.Serve)
 }
@@ -1,56 +1,45 @@
 package main
 
 import (
+	"cmp"
 	"context"
 	"encoding/json"
-	"fmt"
+	"errors"
+	"log"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 
+	"github.com/opentix/platform/apps/api/models"
+	v1 "github.com/opentix/platform/apps/api/models/v1"
 	"github.com/opentix/platform/apps/api/shared"
+	"github.com/opentix/platform/packages/gohelpers/packages/address"
 	"github.com/opentix/platform/packages/gohelpers/packages/database"
+	"github.com/opentix/platform/packages/gohelpers/packages/geo"
 	"github.com/opentix/platform/packages/gohelpers/packages/query"
+	"github.com/opentix/platform/packages/gohelpers/packages/search"
 )
 
 var connStr string
 
-// Type for POST request to unmarshal body
-type VenuePostBodyParams struct {
-	Name          string `json:"Name"`
-	StreetAddress string `json:"StreetAddress"`
-	Zip           string `json:"Zip"`
-	City          string `json:"City"`
-	StateCode     string `json:"StateCode"`
-	StateName     string `json:"StateName"`
-	CountryCode   string `json:"CountryCode"`
-	CountryName   string `json:"CountryName"`
-	NumUnique     int32  `json:"NumUnique"`
-	NumGa         int32  `json:"NumGa"`
-	Vendor        int32
-}
-
-type VenuePatchBodyParams struct {
-	Pk            int32  `json:"Pk"`
-	Name          string `json:"Name"`
-	StreetAddress string `json:"StreetAddress"`
-	Zip           string `json:"Zip"`
-	City          string `json:"City"`
-	StateCode     string `json:"StateCode"`
-	StateName     string `json:"StateName"`
-	CountryCode   string `json:"CountryCode"`
-	CountryName   string `json:"CountryName"`
-	Photo         string `json:"Photo"`
-}
-
 func init() {
 	connStr = database.BuildDatabaseConnectionString()
+	// Venues are geocoded from the bundled zip centroids, and are saved
+	// without coordinates until the dataset has been generated
+	if err := geo.Load(); err != nil {
+		log.Printf("Venues won't be geocoded: %v", err)
+	}
 }
 
-func handleGetAll(ctx context.Context, request events.APIGatewayProxyRequest, vendorinfo shared.GetWalletAndUUIDFromTokenResponse) (events.APIGatewayProxyResponse, error) {
+func handleGetAll(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	vendorinfo := shared.GetUserInfo(ctx)
+
 	// Connect to the database
 	conn, err := database.ConnectToDatabase(ctx, connStr)
 	if err != nil {
@@ -62,7 +51,11 @@ func handleGetAll(ctx context.Context, request events.APIGatewayProxyRequest, ve
 	// Get all venues for the vendor
 	dbResponse, err := queries.VendorGetAllVenues(ctx, vendorinfo.Wallet)
 	if err != nil {
-		return shared.CreateErrorResponseAndLogError(404, "Unable to get response from database or malformed query", request.Headers, err)
+		return shared.CreateAPIErrorResponse(shared.FromDBError(err, "Venue not found"), request.Headers)
+	}
+
+	if shared.GetVersion(ctx) >= shared.Version1 {
+		return shared.CreateJSONResponse(200, v1.Map(dbResponse, v1.NewVenueSummary), request.Headers)
 	}
 
 	responseBody, err := json.Marshal(dbResponse)
@@ -76,7 +69,37 @@ func handleGetAll(ctx context.Context, request events.APIGatewayProxyRequest, ve
 	}, nil
 }
 
-func handleGetByPk(ctx context.Context, request events.APIGatewayProxyRequest, pk int32, vendorinfo shared.GetWalletAndUUIDFromTokenResponse) (events.APIGatewayProxyResponse, error) {
+// Looks up one of the vendor's venues by uuid, or by pk when the id is numeric.
+func getVenueByID(ctx context.Context, queries *query.Queries, wallet string, id string) (query.AppVenue, error) {
+	var venue query.AppVenue
+	if pk, err := strconv.ParseInt(id, 10, 32); err == nil {
+		venue, err = queries.VendorGetVenueByPk(ctx, query.VendorGetVenueByPkParams{
+			Pk:     int32(pk),
+			Wallet: wallet,
+		})
+		if err != nil {
+			return venue, shared.FromDBError(err, "Venue not found")
+		}
+		return venue, nil
+	}
+
+	u, err := uuid.Parse(id)
+	if err != nil {
+		return venue, shared.BadRequest("Invalid uuid")
+	}
+	venue, err = queries.VendorGetVenueByUuid(ctx, query.VendorGetVenueByUuidParams{
+		ID:     u,
+		Wallet: wallet,
+	})
+	if err != nil {
+		return venue, shared.FromDBError(err, "Venue not found")
+	}
+	return venue, nil
+}
+
+func handleGetOne(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	vendorinfo := shared.GetUserInfo(ctx)
+
 	// Connect to the database
 	conn, err := database.ConnectToDatabase(ctx, connStr)
 	if err != nil {
@@ -85,27 +108,22 @@ func handleGetByPk(ctx context.Context, request events.APIGatewayProxyRequest, p
 	defer conn.Close(ctx)
 	queries := query.New(conn)
 
-	// Get venue by pk
-	dbResponse, err := queries.VendorGetVenueByPk(ctx, query.VendorGetVenueByPkParams{
-		Pk:     pk,
-		Wallet: vendorinfo.Wallet,
-	})
+	dbResponse, err := getVenueByID(ctx, queries, vendorinfo.Wallet, shared.PathParam(request, "id"))
 	if err != nil {
-		return shared.CreateErrorResponseAndLogError(404, "Unable to get response from database or malformed query", request.Headers, err)
+		return shared.CreateAPIErrorResponse(err, request.Headers)
 	}
 
-	responseBody, err := json.Marshal(dbResponse)
-	if err != nil {
-		return shared.CreateErrorResponseAndLogError(500, "Failed to marshal response", request.Headers, err)
+	if shared.GetVersion(ctx) >= shared.Version1 {
+		return shared.CreateTaggedJSONResponse(200, v1.NewVenue(dbResponse), dbResponse.Version, request.Headers)
 	}
-	return events.APIGatewayProxyResponse{
-		StatusCode: 200,
-		Body:       string(responseBody),
-		Headers:    shared.GetResponseHeaders(request.Headers),
-	}, nil
+
+	return shared.CreateTaggedJSONResponse(200, dbResponse, dbResponse.Version, request.Headers)
 }
 
-func handleGetByUuid(ctx context.Context, request events.APIGatewayProxyRequest, id string, vendorinfo shared.GetWalletAndUUIDFromTokenResponse) (events.APIGatewayProxyResponse, error) {
+// The changes made to a venue, newest first.
+func handleGetHistory(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	vendorinfo := shared.GetUserInfo(ctx)
+
 	// Connect to the database
 	conn, err := database.ConnectToDatabase(ctx, connStr)
 	if err != nil {
@@ -114,83 +132,91 @@ func handleGetByUuid(ctx context.Context, request events.APIGatewayProxyRequest,
 	defer conn.Close(ctx)
 	queries := query.New(conn)
 
-	u, err := uuid.Parse(id)
+	venue, err := getVenueByID(ctx, queries, vendorinfo.Wallet, shared.PathParam(request, "id"))
 	if err != nil {
-		return shared.CreateErrorResponse(400, "Invalid uuid", request.Headers)
+		return shared.CreateAPIErrorResponse(err, request.Headers)
 	}
-	// Get venue by uuid
-	dbResponse, err := queries.VendorGetVenueByUuid(ctx, query.VendorGetVenueByUuidParams{
-		ID:     u,
-		Wallet: vendorinfo.Wallet,
-	})
+	return shared.CreateHistoryResponse(ctx, queries, "venue", venue.Pk, request)
+}
+
+// How far ahead the calendar looks without a To, and the longest window it serves
+const defaultCalendarWindow = 90 * 24 * time.Hour
+const maxCalendarWindow = 366 * 24 * time.Hour
+
+// The times the venue is taken by events, from now on unless From and To say
+// otherwise.
+func handleGetCalendar(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	vendorinfo := shared.GetUserInfo(ctx)
+
+	fromParam, err := shared.GetTimeParam(request, "From")
 	if err != nil {
-		return shared.CreateErrorResponseAndLogError(404, "Unable to get response from database or malformed query", request.Headers, err)
+		return shared.CreateAPIErrorResponse(err, request.Headers)
 	}
-
-	responseBody, err := json.Marshal(dbResponse)
+	toParam, err := shared.GetTimeParam(request, "To")
 	if err != nil {
-		return shared.CreateErrorResponseAndLogError(500, "Failed to marshal response", request.Headers, err)
+		return shared.CreateAPIErrorResponse(err, request.Headers)
+	}
+	from := time.Now()
+	if fromParam != nil {
+		from = *fromParam
+	}
+	to := from.Add(defaultCalendarWindow)
+	if toParam != nil {
+		to = *toParam
+	}
+	if !to.After(from) {
+		return shared.CreateAPIErrorResponse(shared.BadRequest("To must be after From"), request.Headers)
+	}
+	if to.Sub(from) > maxCalendarWindow {
+		return shared.CreateAPIErrorResponse(shared.BadRequest("From and To can be at most 366 days apart"), request.Headers)
 	}
-	return events.APIGatewayProxyResponse{
-		StatusCode: 200,
-		Body:       string(responseBody),
-		Headers:    shared.GetResponseHeaders(request.Headers),
-	}, nil
-}
 
-func handleGet(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
-	// Grab auth token
-	tk, err := shared.GetTokenFromRequest(request)
+	// Connect to the database
+	conn, err := database.ConnectToDatabase(ctx, connStr)
 	if err != nil {
-		return shared.CreateErrorResponseAndLogError(401, "Error creating token object from DIDToken", request.Headers, err)
+		return shared.CreateErrorResponseAndLogError(500, "Failed to connect to the database", request.Headers, err)
 	}
+	defer conn.Close(ctx)
+	queries := query.New(conn)
 
-	// Grab wallet address from token
-	vendorinfo, err := shared.GetWalletAndUUIDFromToken(tk)
+	venue, err := getVenueByID(ctx, queries, vendorinfo.Wallet, shared.PathParam(request, "id"))
 	if err != nil {
-		return shared.CreateErrorResponseAndLogError(401, "Error retrieving wallet from token", request.Headers, err)
+		return shared.CreateAPIErrorResponse(err, request.Headers)
 	}
 
-	/* ---- Breakaway for different queries based on input params ---- */
-	tmp, ok := request.QueryStringParameters["all"]
-	if ok && tmp == "true" {
-		return handleGetAll(ctx, request, vendorinfo)
+	rows, err := queries.VendorGetVenueCalendar(ctx, query.VendorGetVenueCalendarParams{
+		Venue:   venue.Pk,
+		Column2: pgtype.Timestamptz{Time: from, Valid: true},
+		Column3: pgtype.Timestamptz{Time: to, Valid: true},
+	})
+	if err != nil {
+		return shared.CreateAPIErrorResponse(shared.FromDBError(err, "Venue not found"), request.Headers)
 	}
 
-	tmp, ok = request.QueryStringParameters["Pk"]
-	if ok {
-		pk, err := strconv.ParseInt(tmp, 10, 32)
-		if err != nil {
-			return shared.CreateErrorResponse(400, "Invalid Pk", request.Headers)
-		}
-		return handleGetByPk(ctx, request, int32(pk), vendorinfo)
-	}
+	return shared.CreateJSONResponse(200, v1.VenueCalendar{
+		From:   from.UTC(),
+		To:     to.UTC(),
+		Events: v1.Map(rows, v1.NewCalendarEntry),
+	}, request.Headers)
+}
 
-	tmp, ok = request.QueryStringParameters["ID"]
-	if ok {
-		return handleGetByUuid(ctx, request, tmp, vendorinfo)
-	}
-	/* ---- End ---- */
+func handleGet(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	vendorinfo := shared.GetUserInfo(ctx)
 
-	// Return all venues as paginated response
+	limit, err := shared.GetPageSizeFromRequest(request, 25)
+	if err != nil {
+		return shared.CreateErrorResponse(400, "Invalid Limit", request.Headers)
+	}
 
-	// Get query parameters and set defaults if not ok
-	tmp, ok = request.QueryStringParameters["Page"]
-	var page int32
-	if !ok {
-		page = 1
-	} else {
-		p, err := strconv.ParseInt(tmp, 10, 32)
-		page = int32(p)
-		if err != nil {
-			page = 1
-		}
+	cursor, hasCursor, err := shared.GetCursorFromRequest(request)
+	if err != nil {
+		return shared.CreateErrorResponse(400, "Invalid Cursor", request.Headers)
 	}
 
 	var filter string = ""
-	tmp, ok = request.QueryStringParameters["Filter"]
+	tmp, ok := request.QueryStringParameters["Filter"]
 	if ok && tmp != "" {
-		filter = tmp
+		filter = strings.TrimSpace(tmp)
 	}
 
 	// Connect to the database
@@ -201,18 +227,52 @@ func handleGet(ctx context.Context, request events.APIGatewayProxyRequest) (even
 	defer conn.Close(ctx)
 	queries := query.New(conn)
 
-	// Get events for current page
+	// Get venues for current page, fetching one extra row to know if there is another page
 	dbResponse, err := queries.VendorGetVenuesPaginated(ctx, query.VendorGetVenuesPaginatedParams{
-		Column1: page,
+		Column1: limit + 1,
 		Wallet:  vendorinfo.Wallet,
-		Column3: strings.ToLower(filter),
+		Column3: filter,
+		Column4: search.PrefixQuery(filter),
+		Column5: hasCursor,
+		Column6: cursor.Name,
+		Column7: cursor.Pk,
 	})
 
 	if err != nil {
-		return shared.CreateErrorResponseAndLogError(404, "Unable to get response from database or malformed query", request.Headers, err)
+		return shared.CreateAPIErrorResponse(shared.FromDBError(err, "Venue not found"), request.Headers)
 	}
 
-	responseBody, err := json.Marshal(dbResponse)
+	total, err := queries.VendorCountVenues(ctx, query.VendorCountVenuesParams{
+		Wallet:  vendorinfo.Wallet,
+		Column2: filter,
+		Column3: search.PrefixQuery(filter),
+	})
+	if err != nil {
+		return shared.CreateAPIErrorResponse(shared.FromDBError(err, "Venue not found"), request.Headers)
+	}
+
+	response := shared.PaginatedResponse{
+		Items:      []query.AppVenue{},
+		TotalCount: total,
+	}
+	if len(dbResponse) > int(limit) {
+		dbResponse = dbResponse[:limit]
+		last := dbResponse[len(dbResponse)-1]
+		response.NextCursor = shared.EncodeCursor(shared.Cursor{
+			Name: last.Name,
+			Pk:   last.Pk,
+		})
+	}
+	if dbResponse != nil {
+		response.Items = dbResponse
+	}
+
+	if shared.GetVersion(ctx) >= shared.Version1 {
+		items := v1.Map(response.Items.([]query.AppVenue), v1.NewVenue)
+		return shared.CreateJSONResponse(200, v1.NewPage(items, response.NextCursor, response.TotalCount), request.Headers)
+	}
+
+	responseBody, err := json.Marshal(response)
 	if err != nil {
 		return shared.CreateErrorResponseAndLogError(500, "Failed to marshal response", request.Headers, err)
 	}
@@ -224,20 +284,10 @@ func handleGet(ctx context.Context, request events.APIGatewayProxyRequest) (even
 }
 
 func handlePost(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
-	// Grab auth token
-	tk, err := shared.GetTokenFromRequest(request)
-	if err != nil {
-		return shared.CreateErrorResponseAndLogError(401, "Error creating token object from DIDToken", request.Headers, err)
-	}
-
-	// Grab wallet address from token
-	userinfo, err := shared.GetWalletAndUUIDFromToken(tk)
-	if err != nil {
-		return shared.CreateErrorResponseAndLogError(401, "Error retrieving wallet from token", request.Headers, err)
-	}
+	userinfo := shared.GetUserInfo(ctx)
 
 	// Connect to the database
-	conn, err := database.ConnectToDatabase(ctx, connStr)
+	conn, err := shared.ConnectToDatabaseAsUser(ctx, connStr)
 	if err != nil {
 		return shared.CreateErrorResponseAndLogError(500, "Failed to connect to the database", request.Headers, err)
 	}
@@ -247,65 +297,118 @@ func handlePost(ctx context.Context, request events.APIGatewayProxyRequest) (eve
 
 	resp, err := queries.GetVendorByWallet(ctx, userinfo.Wallet)
 	if err != nil {
-		return shared.CreateErrorResponse(404, "Vendor does not exist", request.Headers)
+		return shared.CreateAPIErrorResponse(shared.FromDBError(err, "Vendor does not exist"), request.Headers)
 	}
 	vendor := resp.Pk
 
-	var params VenuePostBodyParams = VenuePostBodyParams{
+	var params models.VenuePostBodyParams = models.VenuePostBodyParams{
 		Vendor: vendor, // We can set the vendor since we got it from the token
 	}
 
-	err = json.Unmarshal([]byte(request.Body), &params)
+	err = shared.DecodeAndValidate(request.Body, &params)
 	if err != nil {
-		return shared.CreateErrorResponseAndLogError(500, "Failed to unmarshal body params", request.Headers, fmt.Errorf("err = %v\nparams = %v\n", err, params))
+		return shared.CreateValidationErrorResponse(err, request.Headers)
+	}
+
+	// The same checks the bulk import makes of every row
+	if err := shared.PrepareVenue(ctx, queries, userinfo.Wallet, &params); err != nil {
+		return shared.CreateAPIErrorResponse(err, request.Headers)
 	}
 
 	// Insert the venue into the app.venue table
 	dbResp, err := queries.CreateVenue(ctx, query.CreateVenueParams(params))
 	if err != nil {
-		return shared.CreateErrorResponseAndLogError(404, "Unable to insert into table or malformed query", request.Headers, err)
+		return shared.CreateAPIErrorResponse(shared.FromDBError(err, "Venue not found"), request.Headers)
 	}
 
-	responseBody, err := json.Marshal(dbResp)
-	if err != nil {
-		return shared.CreateErrorResponseAndLogError(500, "Failed to marshal response", request.Headers, err)
+	if shared.GetVersion(ctx) >= shared.Version1 {
+		return shared.CreateTaggedJSONResponse(201, v1.NewVenue(dbResp), dbResp.Version, request.Headers)
 	}
-	return events.APIGatewayProxyResponse{
-		StatusCode: 201,
-		Body:       string(responseBody),
-		Headers:    shared.GetResponseHeaders(request.Headers),
-	}, nil
+
+	return shared.CreateTaggedJSONResponse(201, dbResp, dbResp.Version, request.Headers)
 }
 
 func handlePatch(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
-	// Grab auth token
-	tk, err := shared.GetTokenFromRequest(request)
-	if err != nil {
-		return shared.CreateErrorResponseAndLogError(401, "Error creating token object from DIDToken", request.Headers, err)
-	}
+	vendorinfo := shared.GetUserInfo(ctx)
 
-	// Grab wallet address from token
-	vendorinfo, err := shared.GetWalletAndUUIDFromToken(tk)
+	// Unmarshal body into models.VenuePatchBodyParams
+	var params models.VenuePatchBodyParams
+	err := shared.DecodeAndValidate(request.Body, &params)
 	if err != nil {
-		return shared.CreateErrorResponseAndLogError(401, "Error retrieving wallet from token", request.Headers, err)
+		return shared.CreateValidationErrorResponse(err, request.Headers)
 	}
+	// Only the photo can be cleared with an explicit null, a cover is set by
+	// choosing one of the gallery's approved photos
+	nulls := shared.NullFields(request.Body)
 
-	// Unmarshal body into VenuePatchBodyParams
-	var params VenuePatchBodyParams
-	err = json.Unmarshal([]byte(request.Body), &params)
+	ifMatch, err := shared.GetIfMatchVersion(request)
 	if err != nil {
-		return shared.CreateErrorResponseAndLogError(400, "Invalid body parameters", request.Headers, err)
+		return shared.CreateAPIErrorResponse(err, request.Headers)
 	}
 
 	// Connect to the database
-	conn, err := database.ConnectToDatabase(ctx, connStr)
+	conn, err := shared.ConnectToDatabaseAsUser(ctx, connStr)
 	if err != nil {
 		return shared.CreateErrorResponseAndLogError(500, "Failed to connect to the database", request.Headers, err)
 	}
 	defer conn.Close(ctx)
 	queries := query.New(conn)
 
-	// Non-editable: Pk, ID, Vendor, NumUnique, NumGa.
+	// Address fields are validated as a whole, so fill in whatever the patch
+	// leaves out from the current venue before normalizing
+	params.Name = address.CollapseWhitespace(params.Name)
+	if params.StreetAddress != "" || params.Zip != "" || params.City != "" || params.StateCode != "" || params.CountryCode != "" {
+		current, err := queries.VendorGetVenueByPk(ctx, query.VendorGetVenueByPkParams{
+			Pk:     params.Pk,
+			Wallet: vendorinfo.Wallet,
+		})
+		if err != nil {
+			return shared.CreateAPIErrorResponse(shared.FromDBError(err, "Venue not found"), request.Headers)
+		}
+
+		addr := address.Address{
+			StreetAddress: cmp.Or(params.StreetAddress, current.StreetAddress),
+			Zip:           cmp.Or(params.Zip, current.Zip),
+			City:          cmp.Or(params.City, current.City),
+			StateCode:     cmp.Or(params.StateCode, current.StateCode),
+			CountryCode:   cmp.Or(params.CountryCode, current.CountryCode),
+		}
+		addr, fieldErrors := shared.NormalizeVenueAddress(addr)
+		if len(fieldErrors) > 0 {
+			return shared.CreateFieldErrorResponse(422, "validation_failed", "Venue is invalid", fieldErrors, request.Headers)
+		}
+
+		duplicate, err := shared.IsDuplicateVenue(ctx, queries, vendorinfo.Wallet, addr, params.Pk)
+		if err != nil {
+			return shared.CreateAPIErrorResponse(shared.FromDBError(err, "Venue not found"), request.Headers)
+		}
+		if duplicate {
+			return shared.CreateFieldErrorResponse(409, "duplicate_venue", "Venue already exists", shared.DuplicateVenueError, request.Headers)
+		}
+
+		params.StreetAddress = addr.StreetAddress
+		params.Zip = addr.Zip
+		params.City = addr.City
+		params.StateCode = addr.StateCode
+		params.StateName = addr.StateName
+		params.CountryCode = addr.CountryCode
+		params.CountryName = addr.CountryName
+	} else {
+		// Names are derived from the codes and can't be set on their own
+		params.StateName = ""
+		params.CountryName = ""
+	}
+
+	// Moving the venue means its old coordinates no longer apply. The new ones
+	// are saved with the rest of the patch.
+	relocated := params.Zip != "" || params.CountryCode != ""
+	var lat, lng pgtype.Float8
+	if relocated {
+		lat, lng = shared.GetVenueLocation(params.CountryCode, params.Zip)
+	}
+
+	// Non-editable: Pk, ID, Vendor. NumUnique and NumGa change through
+	// PATCH /vendor/venues/{id}/capacity, which checks them against upcoming events.
 	arg := query.VendorPatchVenueParams{
 		Pk:       params.Pk,
 		Wallet:   vendorinfo.Wallet,
@@ -317,38 +420,147 @@ func handlePatch(ctx context.Context, request events.APIGatewayProxyRequest) (ev
 		Column8:  params.StateName,
 		Column9:  params.CountryCode,
 		Column10: params.CountryName,
-		Column11: params.Photo,
+		Column11: nulls["Photo"],
+		Column12: ifMatch,
+		Column13: relocated,
+		Column14: lat.Valid,
+		Column15: lat.Float64,
+		Column16: lng.Float64,
 	}
 
-	updatedVenue, err := queries.VendorPatchVenue(ctx, arg)
+	var updatedVenue query.AppVenue
+	err = database.InTransaction(ctx, conn, func(queries *query.Queries) error {
+		venue, err := queries.VendorPatchVenue(ctx, arg)
+		if err != nil {
+			return err
+		}
+		updatedVenue = venue
+		// The photo cleared was the gallery's cover
+		if nulls["Photo"] {
+			return queries.ClearMediaCover(ctx, query.ClearMediaCoverParams{
+				Venue: pgtype.Int4{Int32: venue.Pk, Valid: true},
+			})
+		}
+		return nil
+	})
+	if errors.Is(err, pgx.ErrNoRows) && ifMatch != 0 {
+		// Either the venue doesn't exist or If-Match is out of date
+		current, currentErr := queries.VendorGetVenueByPk(ctx, query.VendorGetVenueByPkParams{
+			Pk:     params.Pk,
+			Wallet: vendorinfo.Wallet,
+		})
+		if currentErr == nil {
+			return shared.CreatePreconditionFailedResponse(current.Version, request.Headers)
+		}
+	}
 	if err != nil {
-		return shared.CreateErrorResponseAndLogError(404, "Failed to update venue", request.Headers, err)
+		return shared.CreateAPIErrorResponse(shared.FromDBError(err, "Venue not found"), request.Headers)
 	}
 
-	responseBody, err := json.Marshal(updatedVenue)
-	if err != nil {
-		return shared.CreateErrorResponseAndLogError(500, "Failed to marshal updated venue", request.Headers, err)
+	if shared.GetVersion(ctx) >= shared.Version1 {
+		return shared.CreateTaggedJSONResponse(200, v1.NewVenue(updatedVenue), updatedVenue.Version, request.Headers)
 	}
 
-	return events.APIGatewayProxyResponse{
-		StatusCode: 200,
-		Body:       string(responseBody),
-		Headers:    shared.GetResponseHeaders(request.Headers),
-	}, nil
+	return shared.CreateTaggedJSONResponse(200, updatedVenue, updatedVenue.Version, request.Headers)
 }
 
-func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
-	if request.HTTPMethod == "GET" {
-		return handleGet(ctx, request)
-	} else if request.HTTPMethod == "POST" {
-		return handlePost(ctx, request)
-	} else if request.HTTPMethod == "PATCH" {
-		return handlePatch(ctx, request)
-	} else {
-		return shared.CreateErrorResponse(405, "Method Not Allowed", request.Headers)
+// Changes how many tickets a venue holds. Upcoming events at the venue have to
+// fit in the new capacity, and when some don't the change is refused with a 409
+// listing them. Events that have already ended are left alone.
+func handlePatchCapacity(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	vendorinfo := shared.GetUserInfo(ctx)
+
+	var params models.VenueCapacityPatchBodyParams
+	err := shared.DecodeAndValidate(request.Body, &params)
+	if err != nil {
+		return shared.CreateValidationErrorResponse(err, request.Headers)
 	}
+
+	ifMatch, err := shared.GetIfMatchVersion(request)
+	if err != nil {
+		return shared.CreateAPIErrorResponse(err, request.Headers)
+	}
+
+	// Connect to the database
+	conn, err := shared.ConnectToDatabaseAsUser(ctx, connStr)
+	if err != nil {
+		return shared.CreateErrorResponseAndLogError(500, "Failed to connect to the database", request.Headers, err)
+	}
+	defer conn.Close(ctx)
+	queries := query.New(conn)
+
+	// Only finds the vendor's own venues, so events at the venue are theirs too
+	venue, err := getVenueByID(ctx, queries, vendorinfo.Wallet, shared.PathParam(request, "id"))
+	if err != nil {
+		return shared.CreateAPIErrorResponse(err, request.Headers)
+	}
+
+	// Events are created and patched with the venue locked, so none can be
+	// added to it between the check and the update
+	var conflicts []query.VendorGetVenueCapacityConflictsRow
+	var updatedVenue query.AppVenue
+	err = database.InTransaction(ctx, conn, func(queries *query.Queries) error {
+		_, err := queries.VendorGetVenueByPkForUpdate(ctx, query.VendorGetVenueByPkForUpdateParams{
+			Pk:     venue.Pk,
+			Wallet: vendorinfo.Wallet,
+		})
+		if err != nil {
+			return err
+		}
+		conflicts, err = queries.VendorGetVenueCapacityConflicts(ctx, query.VendorGetVenueCapacityConflictsParams{
+			Venue:   venue.Pk,
+			Column2: params.NumUnique,
+			Column3: params.NumGa,
+		})
+		if err != nil || len(conflicts) > 0 {
+			return err
+		}
+		updatedVenue, err = queries.VendorUpdateVenueCapacity(ctx, query.VendorUpdateVenueCapacityParams{
+			Pk:        venue.Pk,
+			Wallet:    vendorinfo.Wallet,
+			NumUnique: params.NumUnique,
+			NumGa:     params.NumGa,
+			Column5:   ifMatch,
+		})
+		return err
+	})
+	if err == nil && len(conflicts) > 0 {
+		return shared.CreateJSONResponse(409, models.VenueCapacityConflictResponse{
+			Message: "Upcoming events at the venue need more tickets than the new capacity",
+			Code:    "capacity_conflict",
+			Events:  conflicts,
+		}, request.Headers)
+	}
+	if errors.Is(err, pgx.ErrNoRows) && ifMatch != 0 {
+		// The venue was just found, so If-Match is out of date
+		current, currentErr := queries.VendorGetVenueByPk(ctx, query.VendorGetVenueByPkParams{
+			Pk:     venue.Pk,
+			Wallet: vendorinfo.Wallet,
+		})
+		if currentErr == nil {
+			return shared.CreatePreconditionFailedResponse(current.Version, request.Headers)
+		}
+	}
+	if err != nil {
+		return shared.CreateAPIErrorResponse(shared.FromDBError(err, "Venue not found"), request.Headers)
+	}
+
+	if shared.GetVersion(ctx) >= shared.Version1 {
+		return shared.CreateTaggedJSONResponse(200, v1.NewVenue(updatedVenue), updatedVenue.Version, request.Headers)
+	}
+
+	return shared.CreateTaggedJSONResponse(200, updatedVenue, updatedVenue.Version, request.Headers)
 }
 
 func main() {
-	lambda.Start(Handler)
+	router := shared.NewRouter(shared.DefaultMiddleware...)
+	router.GET("/vendor/venues", handleGet, shared.Auth)
+	router.GET("/vendor/venues/all", handleGetAll, shared.Auth)
+	router.GET("/vendor/venues/{id}", handleGetOne, shared.Auth)
+	router.GET("/vendor/venues/{id}/history", handleGetHistory, shared.Auth)
+	router.GET("/vendor/venues/{id}/calendar", handleGetCalendar, shared.Auth)
+	router.POST("/vendor/venues", handlePost, shared.Auth, shared.Idempotency(connStr))
+	router.PATCH("/vendor/venues", handlePatch, shared.Auth)
+	router.PATCH("/vendor/venues/{id}/capacity", handlePatchCapacity, shared.Auth)
+	lambda.Start(router.Serve)
 }
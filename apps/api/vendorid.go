@@ -3,13 +3,17 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
 
 	"regexp"
 
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 
+	"github.com/opentix/platform/apps/api/models"
+	v1 "github.com/opentix/platform/apps/api/models/v1"
 	"github.com/opentix/platform/apps/api/shared"
 	"github.com/opentix/platform/packages/gohelpers/packages/database"
 	"github.com/opentix/platform/packages/gohelpers/packages/query"
@@ -20,10 +24,6 @@ var (
 	connStr     string
 )
 
-type PostPatchVendorIdRequestBody struct {
-	Name string `json:"Name"`
-}
-
 func init() {
 	walletRegex = regexp.MustCompile("^[0-9A-Fa-f]{40}$")
 	connStr = database.BuildDatabaseConnectionString()
@@ -31,17 +31,7 @@ func init() {
 
 // This gets the current vendor's info based off the authorization token
 func handleGet(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
-	// Grab auth token
-	tk, err := shared.GetTokenFromRequest(request)
-	if err != nil {
-		return shared.CreateErrorResponseAndLogError(401, "Error creating token object", request.Headers, err)
-	}
-
-	// Grab wallet address from token
-	userinfo, err := shared.GetWalletAndUUIDFromToken(tk)
-	if err != nil {
-		return shared.CreateErrorResponseAndLogError(401, "Error retrieving wallet from token", request.Headers, err)
-	}
+	userinfo := shared.GetUserInfo(ctx)
 
 	// Connect to the database
 	conn, err := database.ConnectToDatabase(ctx, connStr)
@@ -55,7 +45,11 @@ func handleGet(ctx context.Context, request events.APIGatewayProxyRequest) (even
 	// get vendor
 	vendor, err := queries.GetVendorByWallet(ctx, userinfo.Wallet)
 	if err != nil {
-		return shared.CreateErrorResponse(404, "Vendor does not exist", request.Headers)
+		return shared.CreateAPIErrorResponse(shared.FromDBError(err, "Vendor does not exist"), request.Headers)
+	}
+
+	if shared.GetVersion(ctx) >= shared.Version1 {
+		return shared.CreateJSONResponse(200, v1.NewVendor(vendor), request.Headers)
 	}
 
 	responseBody, err := json.Marshal(vendor)
@@ -72,29 +66,16 @@ func handleGet(ctx context.Context, request events.APIGatewayProxyRequest) (even
 // This takes in the auth token and the name of the vendor and creates a new vendor if it does not already exist
 func handlePost(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
 	// Grab and validate request body
-	var body PostPatchVendorIdRequestBody
-	err := json.Unmarshal([]byte(request.Body), &body)
-	if err != nil {
-		return shared.CreateErrorResponse(400, "Invalid request body", request.Headers)
-	}
-	if body.Name == "" {
-		return shared.CreateErrorResponse(400, "Name is required", request.Headers)
-	}
-
-	// Grab auth token
-	tk, err := shared.GetTokenFromRequest(request)
+	var body models.PostPatchVendorIdRequestBody
+	err := shared.DecodeAndValidate(request.Body, &body)
 	if err != nil {
-		return shared.CreateErrorResponseAndLogError(401, "Error creating token object", request.Headers, err)
+		return shared.CreateValidationErrorResponse(err, request.Headers)
 	}
 
-	// Grab wallet address from token
-	userinfo, err := shared.GetWalletAndUUIDFromToken(tk)
-	if err != nil {
-		return shared.CreateErrorResponseAndLogError(401, "Error retrieving wallet from token", request.Headers, err)
-	}
+	userinfo := shared.GetUserInfo(ctx)
 
 	// Connect to the database
-	conn, err := database.ConnectToDatabase(ctx, connStr)
+	conn, err := shared.ConnectToDatabaseAsUser(ctx, connStr)
 	if err != nil {
 		return shared.CreateErrorResponseAndLogError(500, "Failed to connect to the database", request.Headers, err)
 	}
@@ -105,7 +86,10 @@ func handlePost(ctx context.Context, request events.APIGatewayProxyRequest) (eve
 	// ensure vendor does not already exist
 	_, err = queries.GetVendorByWallet(ctx, userinfo.Wallet)
 	if err == nil {
-		return shared.CreateErrorResponse(409, "Vendor already exists", request.Headers)
+		return shared.CreateAPIErrorResponse(shared.Conflict("Vendor already exists"), request.Headers)
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return shared.CreateAPIErrorResponse(shared.FromDBError(err, "Vendor does not exist"), request.Headers)
 	}
 
 	// create vendor
@@ -115,7 +99,11 @@ func handlePost(ctx context.Context, request events.APIGatewayProxyRequest) (eve
 	}
 	vendor, err := queries.CreateVendorWithUUID(ctx, query.CreateVendorWithUUIDParams{ID: u, Wallet: userinfo.Wallet, Name: body.Name})
 	if err != nil {
-		return shared.CreateErrorResponseAndLogError(500, "Failed to create vendor", request.Headers, err)
+		return shared.CreateAPIErrorResponse(shared.FromDBError(err, "Vendor does not exist"), request.Headers)
+	}
+
+	if shared.GetVersion(ctx) >= shared.Version1 {
+		return shared.CreateJSONResponse(201, v1.NewVendor(vendor), request.Headers)
 	}
 
 	responseBody, err := json.Marshal(vendor)
@@ -130,29 +118,16 @@ func handlePost(ctx context.Context, request events.APIGatewayProxyRequest) (eve
 // This updates the Name of the vendor
 func handlePatch(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
 	// Grab and validate request body
-	var body PostPatchVendorIdRequestBody
-	err := json.Unmarshal([]byte(request.Body), &body)
-	if err != nil {
-		return shared.CreateErrorResponse(400, "Invalid request body", request.Headers)
-	}
-	if body.Name == "" {
-		return shared.CreateErrorResponse(400, "Name is required", request.Headers)
-	}
-
-	// Grab auth token
-	tk, err := shared.GetTokenFromRequest(request)
+	var body models.PostPatchVendorIdRequestBody
+	err := shared.DecodeAndValidate(request.Body, &body)
 	if err != nil {
-		return shared.CreateErrorResponseAndLogError(401, "Error creating token object", request.Headers, err)
+		return shared.CreateValidationErrorResponse(err, request.Headers)
 	}
 
-	// Grab wallet address from token
-	userinfo, err := shared.GetWalletAndUUIDFromToken(tk)
-	if err != nil {
-		return shared.CreateErrorResponseAndLogError(401, "Error retrieving wallet from token", request.Headers, err)
-	}
+	userinfo := shared.GetUserInfo(ctx)
 
 	// Connect to the database
-	conn, err := database.ConnectToDatabase(ctx, connStr)
+	conn, err := shared.ConnectToDatabaseAsUser(ctx, connStr)
 	if err != nil {
 		return shared.CreateErrorResponseAndLogError(500, "Failed to connect to the database", request.Headers, err)
 	}
@@ -163,14 +138,18 @@ func handlePatch(ctx context.Context, request events.APIGatewayProxyRequest) (ev
 	// ensure vendor exists
 	_, err = queries.GetVendorByWallet(ctx, userinfo.Wallet)
 	if err != nil {
-		return shared.CreateErrorResponse(404, "Vendor does not exist. Use POST", request.Headers)
+		return shared.CreateAPIErrorResponse(shared.FromDBError(err, "Vendor does not exist. Use POST"), request.Headers)
 	}
 
 	// update vendor
 	vendor, err := queries.UpdateVendorName(ctx, query.UpdateVendorNameParams{Wallet: userinfo.Wallet, Name: body.Name})
 	if err != nil {
-		return shared.CreateErrorResponseAndLogError(500, "Failed to update vendor", request.Headers, err)
+		return shared.CreateAPIErrorResponse(shared.FromDBError(err, "Vendor does not exist. Use POST"), request.Headers)
+	}
+	if shared.GetVersion(ctx) >= shared.Version1 {
+		return shared.CreateJSONResponse(200, v1.NewVendor(vendor), request.Headers)
 	}
+
 	responseBody, err := json.Marshal(vendor)
 	return events.APIGatewayProxyResponse{
 		StatusCode: 200,
@@ -179,23 +158,31 @@ func handlePatch(ctx context.Context, request events.APIGatewayProxyRequest) (ev
 	}, nil
 }
 
-func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
-	if request.HTTPMethod == "GET" {
-		return handleGet(ctx, request)
-	} else if request.HTTPMethod == "POST" {
-		return handlePost(ctx, request)
-	} else if request.HTTPMethod == "PATCH" {
-		return handlePatch(ctx, request)
-	} else {
-		body, _ := json.Marshal(map[string]string{"message": "Method Not Allowed."})
-		return events.APIGatewayProxyResponse{
-			StatusCode: 405,
-			Body:       string(body),
-			Headers:    shared.GetResponseHeaders(request.Headers),
-		}, nil
+// The changes made to the vendor's own details, newest first.
+func handleGetHistory(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	userinfo := shared.GetUserInfo(ctx)
+
+	// Connect to the database
+	conn, err := database.ConnectToDatabase(ctx, connStr)
+	if err != nil {
+		return shared.CreateErrorResponseAndLogError(500, "Failed to connect to the database", request.Headers, err)
+	}
+	defer conn.Close(ctx)
+
+	queries := query.New(conn)
+
+	vendor, err := queries.GetVendorByWallet(ctx, userinfo.Wallet)
+	if err != nil {
+		return shared.CreateAPIErrorResponse(shared.FromDBError(err, "Vendor does not exist"), request.Headers)
 	}
+	return shared.CreateHistoryResponse(ctx, queries, "vendor", vendor.Pk, request)
 }
 
 func main() {
-	lambda.Start(Handler)
+	router := shared.NewRouter(shared.DefaultMiddleware...)
+	router.GET("/vendor/id", handleGet, shared.Auth)
+	router.POST("/vendor/id", handlePost, shared.Auth)
+	router.PATCH("/vendor/id", handlePatch, shared.Auth)
+	router.GET("/vendor/id/history", handleGetHistory, shared.Auth)
+	lambda.Start(router.Serve)
 }
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"os"
+	"time"
+
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/opentix/platform/packages/gohelpers/packages/database"
+	"github.com/opentix/platform/packages/gohelpers/packages/notify"
+	"github.com/opentix/platform/packages/gohelpers/packages/query"
+)
+
+var connStr string
+var channels []notify.Channel
+
+// How many notifications one run sends, the rest wait for the next run
+const notificationBatch = 20
+
+// How many runs may fail to send a notification before it is given up on
+const notificationAttempts = 5
+
+// How often the outbox is checked when running locally
+const localPollInterval = 10 * time.Second
+
+func init() {
+	connStr = database.BuildDatabaseConnectionString()
+	var err error
+	channels, err = notify.FromEnv(context.Background())
+	if err != nil {
+		panic("Failed to set up notification channels: " + err.Error())
+	}
+}
+
+// Runs on a schedule. Sends the notifications the API left in the outbox to
+// the ticket holders of their events, on every channel they haven't turned
+// off. Each delivery is recorded before it is sent, so a notification that is
+// retried only goes to the recipients it failed for.
+func HandleNotificationEvent(ctx context.Context) error {
+	// Connect to the database
+	conn, err := pgx.Connect(ctx, connStr)
+	if err != nil {
+		connStr = database.BuildDatabaseConnectionString()
+		conn, err = pgx.Connect(ctx, connStr)
+		if err != nil {
+			panic("Failed to connect to database: " + err.Error())
+		}
+	}
+	defer conn.Close(ctx)
+	queries := query.New(conn)
+
+	notifications, err := queries.InsecureClaimNotifications(ctx, notificationBatch)
+	if err != nil {
+		return err
+	}
+	for _, notification := range notifications {
+		if err := sendNotification(ctx, queries, notification); err != nil {
+			log.Printf("Error sending notification %d: %v", notification.Pk, err)
+			err = queries.InsecureFailNotification(ctx, query.InsecureFailNotificationParams{
+				Pk:      notification.Pk,
+				Error:   pgtype.Text{String: err.Error(), Valid: true},
+				Column3: notificationAttempts,
+			})
+		} else {
+			err = queries.InsecureCompleteNotification(ctx, notification.Pk)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Sends notification to everyone it hasn't reached yet, returning an error
+// when any delivery failed
+func sendNotification(ctx context.Context, queries *query.Queries, notification query.AppNotificationOutbox) error {
+	var data notify.Data
+	if err := json.Unmarshal(notification.Payload, &data); err != nil {
+		return err
+	}
+	message, err := notify.Render(notify.Kind(notification.Kind), data)
+	if err != nil {
+		return err
+	}
+	rows, err := queries.GetNotificationRecipients(ctx, notification.Event)
+	if err != nil {
+		return err
+	}
+
+	var failed error
+	for _, row := range rows {
+		recipient := notify.Recipient{
+			Wallet:     row.Wallet,
+			Email:      row.Email.String,
+			PushTokens: row.PushTokens,
+		}
+		for _, channel := range channels {
+			if !enabled(row, channel) || !channel.Reaches(recipient) {
+				continue
+			}
+			if err := deliver(ctx, queries, notification.Pk, recipient, channel, message); err != nil {
+				log.Printf("Error sending notification %d to %s by %s: %v", notification.Pk, recipient.Wallet, channel.Name(), err)
+				failed = errors.New("some deliveries failed")
+			}
+		}
+	}
+	return failed
+}
+
+func enabled(row query.GetNotificationRecipientsRow, channel notify.Channel) bool {
+	switch channel.Name() {
+	case "email":
+		return row.EmailEnabled
+	case "push":
+		return row.PushEnabled
+	}
+	return true
+}
+
+// Sends message to recipient on channel unless it already has been or another
+// run is sending it, and records whether it was sent. A send still unfinished
+// after 15 minutes is taken to have died with its run and is tried again.
+func deliver(ctx context.Context, queries *query.Queries, notification int32, recipient notify.Recipient, channel notify.Channel, message notify.Message) error {
+	delivery, err := queries.InsecureClaimNotificationDelivery(ctx, query.InsecureClaimNotificationDeliveryParams{
+		Notification: notification,
+		Wallet:       recipient.Wallet,
+		Channel:      channel.Name(),
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	sendErr := channel.Send(ctx, recipient, message)
+	// Uninstalled apps are forgotten, the message still reached the other devices
+	var stale *notify.StaleTokensError
+	if errors.As(sendErr, &stale) {
+		for _, token := range stale.Tokens {
+			if err := queries.InsecureDeletePushToken(ctx, token); err != nil {
+				return err
+			}
+		}
+		sendErr = nil
+	}
+
+	params := query.InsecureFinishNotificationDeliveryParams{Pk: delivery, Status: "sent"}
+	if sendErr != nil {
+		params.Status = "failed"
+		params.Error = pgtype.Text{String: sendErr.Error(), Valid: true}
+	}
+	if err := queries.InsecureFinishNotificationDelivery(ctx, params); err != nil {
+		return err
+	}
+	return sendErr
+}
+
+func main() {
+	// With SMTP_SINK_DIR set the handler runs locally, taking its own email
+	// through a sink at SMTP_HOST and checking the outbox every
+	// localPollInterval
+	if dir := os.Getenv("SMTP_SINK_DIR"); dir != "" {
+		var addr string
+		for _, channel := range channels {
+			if smtp, ok := channel.(*notify.SMTPChannel); ok {
+				addr = smtp.Addr
+			}
+		}
+		if addr == "" {
+			log.Fatal("SMTP_HOST must be set to run the SMTP sink")
+		}
+		sink := notify.Sink{Dir: dir}
+		go func() {
+			log.Fatal(sink.ListenAndServe(addr))
+		}()
+		for ; ; time.Sleep(localPollInterval) {
+			if err := HandleNotificationEvent(context.Background()); err != nil {
+				log.Printf("Failed to send notifications: %v", err)
+			}
+		}
+	}
+	lambda.Start(HandleNotificationEvent)
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/opentix/platform/packages/gohelpers/packages/database"
+	"github.com/opentix/platform/packages/gohelpers/packages/export"
+	"github.com/opentix/platform/packages/gohelpers/packages/query"
+	"github.com/opentix/platform/packages/gohelpers/packages/storage"
+)
+
+var connStr string
+var store storage.Store
+
+// How many deleted photos are handled per query
+const deletedPhotoBatch = 500
+
+// Objects newer than this are left alone when reconciling, as they can be
+// uploads still being processed or variants that are about to be attached
+const reconcileGracePeriod = 24 * time.Hour
+
+// Gallery items whose upload hasn't been attached by then are removed, so
+// uploads that were started but never finished stop counting against the
+// gallery's limit. Their presigned urls expired long before.
+const staleUploadAge = 24 * time.Hour
+
+func init() {
+	connStr = database.BuildDatabaseConnectionString()
+	var err error
+	store, err = storage.FromEnv(context.Background())
+	if err != nil {
+		panic("Failed to set up photo storage: " + err.Error())
+	}
+}
+
+// Input of the schedules that invoke the lambda. Reconciling lists the whole
+// store, so it runs less often than the cleanup of deleted photos.
+type PhotoCleanupEvent struct {
+	Reconcile bool `json:"Reconcile"`
+}
+
+func HandleCleanupEvent(ctx context.Context, event PhotoCleanupEvent) error {
+	// Connect to the database
+	conn, err := pgx.Connect(ctx, connStr)
+	if err != nil {
+		connStr = database.BuildDatabaseConnectionString()
+		conn, err = pgx.Connect(ctx, connStr)
+		if err != nil {
+			panic("Failed to connect to database: " + err.Error())
+		}
+	}
+	defer conn.Close(ctx)
+	queries := query.New(conn)
+
+	if err := expireStaleUploads(ctx, queries); err != nil {
+		return err
+	}
+	if err := deleteRemovedPhotos(ctx, queries); err != nil {
+		return err
+	}
+	if event.Reconcile {
+		return reconcile(ctx, queries)
+	}
+	return nil
+}
+
+func expireStaleUploads(ctx context.Context, queries *query.Queries) error {
+	expired, err := queries.InsecureExpireStaleUploads(ctx, pgtype.Timestamptz{
+		Time:  time.Now().Add(-staleUploadAge),
+		Valid: true,
+	})
+	if err != nil {
+		return err
+	}
+	if expired > 0 {
+		log.Printf("Removed %d gallery items whose upload never finished", expired)
+	}
+	return nil
+}
+
+// Deletes the objects of the photo urls the deleted_photo trigger recorded,
+// unless another row still references them. A failed delete leaves its url
+// recorded for the next run.
+func deleteRemovedPhotos(ctx context.Context, queries *query.Queries) error {
+	for {
+		deleted, err := queries.InsecureGetDeletedPhotos(ctx, deletedPhotoBatch)
+		if err != nil {
+			return err
+		}
+
+		var handled []int32
+		var deleteErr error
+		for _, photo := range deleted {
+			key, ok := store.KeyOf(photo.Url)
+			if ok && !photo.Referenced {
+				if deleteErr = store.Delete(ctx, key); deleteErr != nil {
+					break
+				}
+			}
+			handled = append(handled, photo.Pk)
+		}
+
+		if len(handled) > 0 {
+			if err := queries.InsecureDeleteDeletedPhotos(ctx, handled); err != nil {
+				return err
+			}
+		}
+		if deleteErr != nil || len(deleted) < deletedPhotoBatch {
+			return deleteErr
+		}
+	}
+}
+
+// Deletes the objects in the store that no event, venue or gallery photo
+// references, catching anything deleteRemovedPhotos missed
+func reconcile(ctx context.Context, queries *query.Queries) error {
+	urls, err := queries.InsecureGetPhotoUrls(ctx)
+	if err != nil {
+		return err
+	}
+	referenced := map[string]bool{}
+	for _, u := range urls {
+		key, ok := store.KeyOf(u)
+		if !ok {
+			continue
+		}
+		referenced[key] = true
+		// Photos uploaded before keys were decoded have the URL encoded key
+		// from the S3 event in their url
+		if decoded, err := url.QueryUnescape(key); err == nil {
+			referenced[decoded] = true
+		}
+	}
+	// Rather than deleting everything when the urls are from another store
+	if len(urls) > 0 && len(referenced) == 0 {
+		return errors.New("none of the photo urls are in the store")
+	}
+
+	objects, err := store.List(ctx, "")
+	if err != nil {
+		return err
+	}
+	cutoff := time.Now().Add(-reconcileGracePeriod)
+	removed := 0
+	for _, object := range objects {
+		// Ticket exports share a local store, the export job expires them itself
+		if strings.HasPrefix(object.Key, export.Prefix) {
+			continue
+		}
+		if referenced[object.Key] || object.LastModified.After(cutoff) {
+			continue
+		}
+		if err := store.Delete(ctx, object.Key); err != nil {
+			return err
+		}
+		removed++
+	}
+	log.Printf("Removed %d of %d objects from photo storage", removed, len(objects))
+	return nil
+}
+
+func main() {
+	lambda.Start(HandleCleanupEvent)
+}
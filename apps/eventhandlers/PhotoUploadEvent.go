@@ -2,60 +2,52 @@ package main
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
+	"io"
 	"log"
 	"os"
 	"strings"
 
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
-	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgtype"
 
 	"github.com/opentix/platform/packages/gohelpers/packages/database"
+	"github.com/opentix/platform/packages/gohelpers/packages/moderation"
+	"github.com/opentix/platform/packages/gohelpers/packages/photo"
 	"github.com/opentix/platform/packages/gohelpers/packages/query"
+	"github.com/opentix/platform/packages/gohelpers/packages/storage"
 )
 
 var connStr string
-var PHOTO_BUCKET string
+var store storage.Store
+
+// Approve photos the classifiers pass instead of leaving them for an admin
+var autoApprove bool
 
 func init() {
 	connStr = database.BuildDatabaseConnectionString()
-	PHOTO_BUCKET = os.Getenv("PHOTO_BUCKET")
-	if PHOTO_BUCKET == "" {
-		panic("PHOTO_BUCKET must be set")
+	var err error
+	store, err = storage.FromEnv(context.Background())
+	if err != nil {
+		panic("Failed to set up photo storage: " + err.Error())
 	}
+	autoApprove = os.Getenv("PHOTO_AUTO_APPROVE") == "true"
 }
 
-type SNSMessage struct {
-	Type      string `json:"Type"`
-	MessageId string `json:"MessageId"`
-	TopicArn  string `json:"TopicArn"`
-	Subject   string `json:"Subject"`
-	Message   string `json:"Message"`
-	Timestamp string `json:"Timestamp"`
+// Lets SQS retry only the messages that failed. Requires ReportBatchItemFailures
+// on the event source mapping.
+type SQSBatchResponse struct {
+	BatchItemFailures []SQSBatchItemFailure `json:"batchItemFailures"`
 }
 
-type PhotoS3Event struct {
-	Records []struct {
-		EventVersion string `json:"eventVersion"`
-		EventSource  string `json:"eventSource"`
-		AwsRegion    string `json:"awsRegion"`
-		EventTime    string `json:"eventTime"`
-		EventName    string `json:"eventName"`
-		S3           struct {
-			Bucket struct {
-				Name string `json:"name"`
-			} `json:"bucket"`
-			Object struct {
-				Key string `json:"key"`
-			} `json:"object"`
-		} `json:"s3"`
-	} `json:"Records"`
+type SQSBatchItemFailure struct {
+	ItemIdentifier string `json:"itemIdentifier"`
 }
 
-func HandleSQSEvent(ctx context.Context, sqsEvent events.SQSEvent) error {
+func HandleSQSEvent(ctx context.Context, sqsEvent events.SQSEvent) (SQSBatchResponse, error) {
 	// Connect to the database
 	conn, err := pgx.Connect(ctx, connStr)
 	if err != nil {
@@ -68,78 +60,237 @@ func HandleSQSEvent(ctx context.Context, sqsEvent events.SQSEvent) error {
 	defer conn.Close(ctx)
 	queries := query.New(conn)
 
-	for _, record := range sqsEvent.Records {
-
-		// Unmarshal the SNS notification in record.Body.
-		var snsMsg SNSMessage
-		if err := json.Unmarshal([]byte(record.Body), &snsMsg); err != nil {
-			log.Printf("Error unmarshalling SNS message: %v", err)
-			continue
-		}
+	classifier, err := newClassifier(ctx, queries)
+	if err != nil {
+		return SQSBatchResponse{}, err
+	}
 
-		// Unmarshal the embedded S3 event
-		var s3Evt PhotoS3Event
-		if err := json.Unmarshal([]byte(snsMsg.Message), &s3Evt); err != nil {
-			log.Printf("Error unmarshalling S3 event: %v", err)
+	var response SQSBatchResponse
+	for _, record := range sqsEvent.Records {
+		keys, err := store.CreatedKeys(record.Body)
+		if err != nil {
+			log.Printf("Error unmarshalling notification: %v", err)
 			continue
 		}
 
-		// Loop through S3 records and extract the needed fields.
-		for _, r := range s3Evt.Records {
-			eventName := r.EventName
-			bucketName := r.S3.Bucket.Name
-			objectKey := r.S3.Object.Key
-			permalink := "https://" + bucketName + ".s3.amazonaws.com/" + objectKey
-
-			if eventName != "ObjectCreated:Put"  || bucketName != PHOTO_BUCKET {
-				log.Printf("Skipping event: %s", eventName)
+		failed := false
+		for _, objectKey := range keys {
+			// Written by processPhoto below
+			if strings.HasPrefix(objectKey, photo.VariantPrefix) {
 				continue
 			}
 
-			var imageType, uuid_string string
-			// e.g. filename-venue/event-uuid.png
-			parts := strings.SplitN(objectKey, "-", 3)
-			if len(parts) == 3 {
-				imageType = parts[1]
-				uuid_string = parts[2]
-			} else {
-				log.Printf("Invalid object key: %s", objectKey)
+			upload, err := photo.ParseUploadKey(objectKey)
+			if err != nil {
+				log.Printf("Invalid object key %q: %v", objectKey, err)
 				continue
 			}
 
-			if imageType != "event" && imageType != "venue" {
-				log.Printf("Invalid image type: %s", imageType)
-				continue
-			}
-			u, err := uuid.Parse(uuid_string)
-			if err != nil {
-				log.Printf("Invalid UUID: %s", uuid_string)
-				continue
+			if err := processPhoto(ctx, queries, classifier, objectKey, upload); err != nil {
+				log.Printf("Failed to process photo %s, will retry: %v", objectKey, err)
+				failed = true
 			}
+		}
+		if failed {
+			response.BatchItemFailures = append(response.BatchItemFailures, SQSBatchItemFailure{ItemIdentifier: record.MessageId})
+		}
+	}
+	return response, nil
+}
+
+// Builds the classifiers uploads are moderated with. The blocklist is read for
+// every batch, so a newly blocked photo applies straight away.
+func newClassifier(ctx context.Context, queries *query.Queries) (moderation.Classifier, error) {
+	hashes, err := queries.InsecureGetPhotoBlocklist(ctx)
+	if err != nil {
+		return nil, err
+	}
+	blocked := make([]uint64, len(hashes))
+	for i, hash := range hashes {
+		blocked[i] = uint64(hash)
+	}
+
+	chain := moderation.Chain{moderation.NewBlocklist(blocked), moderation.DefaultRules}
+	if !autoApprove {
+		chain = append(chain, moderation.RequireReview{})
+	}
+	return chain, nil
+}
+
+// Turns an upload into the resized variants of its gallery item. The variants
+// are re-encoded from the decoded pixels, so the EXIF data of the upload is
+// dropped, and the upload itself is deleted once they are saved. The first
+// approved photo of a gallery becomes its cover and is copied onto the event or
+// venue, photos left pending wait for an admin's review.
+//
+// Anything that isn't an image is deleted along with its gallery item, and
+// photos the classifier rejects are kept in the gallery as rejected, without
+// variants. An upload whose item was removed in the meantime is just deleted.
+// Errors are the ones worth retrying; variants written before one happened are
+// deleted, so a retry starts again from the upload.
+func processPhoto(ctx context.Context, queries *query.Queries, classifier moderation.Classifier, objectKey string, upload photo.Upload) error {
+	object, err := store.Get(ctx, objectKey)
+	if errors.Is(err, storage.ErrNotFound) {
+		// Already processed by an earlier delivery of the same event
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	data, err := io.ReadAll(io.LimitReader(object, photo.MaxUploadSize+1))
+	object.Close()
+	if err != nil {
+		return err
+	}
+
+	// A redelivery of an upload that was already attached only finishes what
+	// the earlier delivery started, so it can't undo an admin's review
+	media, err := queries.InsecureGetMediaByUuid(ctx, upload.MediaID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		log.Printf("Discarding upload %s, it was removed from the %s's gallery", objectKey, upload.RecordType)
+		return store.Delete(ctx, objectKey)
+	}
+	if err != nil {
+		return err
+	}
+	if media.Status != string(moderation.Pending) || media.Photo.Valid {
+		return showPhoto(ctx, queries, objectKey, upload, media)
+	}
 
+	img, err := photo.Decode(data)
+	if errors.Is(err, photo.ErrNotAnImage) || errors.Is(err, photo.ErrTooLarge) {
+		log.Printf("Rejecting upload %s: %v", objectKey, err)
+		if err := queries.InsecureDeleteMedia(ctx, upload.MediaID); err != nil {
+			return err
+		}
+		return store.Delete(ctx, objectKey)
+	}
+	if err != nil {
+		return err
+	}
+
+	result, err := classifier.Classify(ctx, img)
+	if err != nil {
+		return err
+	}
+	hash := pgtype.Int8{Int64: int64(photo.Hash(img)), Valid: true}
+	reason := pgtype.Text{String: result.Reason, Valid: result.Reason != ""}
+	if result.Status == moderation.Rejected {
+		log.Printf("Rejecting upload %s: %s", objectKey, result.Reason)
+		_, err := queries.InsecureRejectMedia(ctx, query.InsecureRejectMediaParams{
+			ID:               upload.MediaID,
+			ModerationReason: reason,
+			PhotoHash:        hash,
+		})
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return err
+		}
+		return store.Delete(ctx, objectKey)
+	}
 
-			if imageType == "event" {
-				_, err = queries.InsecureUpdateEventPhoto(ctx, query.InsecureUpdateEventPhotoParams{
-					ID: u,
-					Photo: pgtype.Text{
-						String: permalink,
-						Valid: true,
-					},
-				})
-			} else {
-				_, err = queries.InsecureUpdateVenuePhoto(ctx, query.InsecureUpdateVenuePhotoParams{
-					ID: u,
-					Photo: pgtype.Text{
-						String: permalink,
-						Valid: true,
-					},
-				})
+	dir := upload.VariantDir()
+	var written []string
+	removeVariants := func() {
+		for _, key := range written {
+			if err := store.Delete(ctx, key); err != nil {
+				log.Printf("Failed to delete variant %s: %v", key, err)
 			}
 		}
 	}
-	return nil
+
+	urls := map[string]pgtype.Text{}
+	for _, variant := range photo.Variants {
+		encoded, err := photo.Encode(photo.Resize(img, variant))
+		if err != nil {
+			removeVariants()
+			return err
+		}
+		key := dir + variant.Name + "." + photo.EncodedExtension
+		if err := store.Put(ctx, key, encoded, photo.EncodedContentType); err != nil {
+			removeVariants()
+			return err
+		}
+		written = append(written, key)
+		urls[variant.Name] = pgtype.Text{String: store.URL(key), Valid: true}
+	}
+
+	// The hero variant stands in for the upload as the item's photo
+	media, err = queries.InsecureAttachMedia(ctx, query.InsecureAttachMediaParams{
+		ID:               upload.MediaID,
+		Photo:            urls[photo.Hero.Name],
+		PhotoThumbnail:   urls[photo.Thumbnail.Name],
+		PhotoCard:        urls[photo.Card.Name],
+		Status:           string(result.Status),
+		ModerationReason: reason,
+		PhotoHash:        hash,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		log.Printf("Discarding upload %s, it was removed from the %s's gallery", objectKey, upload.RecordType)
+		removeVariants()
+		return store.Delete(ctx, objectKey)
+	}
+	if err != nil {
+		removeVariants()
+		return err
+	}
+	return showPhoto(ctx, queries, objectKey, upload, media)
+}
+
+// Makes an approved photo the cover of its gallery when there is none yet,
+// copying it onto the event or venue, and deletes the upload
+func showPhoto(ctx context.Context, queries *query.Queries, objectKey string, upload photo.Upload, media query.AppMedia) error {
+	var err error
+	if media.Status != string(moderation.Approved) {
+		// Becomes the cover when an admin approves it, if there is none by then
+		return store.Delete(ctx, objectKey)
+	}
+
+	if !media.IsCover {
+		media, err = queries.InsecureClaimMediaCover(ctx, media.Pk)
+		var pgErr *pgconn.PgError
+		if errors.Is(err, pgx.ErrNoRows) || errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			// The gallery has a cover, or another upload just became it
+			return store.Delete(ctx, objectKey)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if upload.RecordType == "event" {
+		_, err = queries.InsecureUpdateEventPhoto(ctx, query.InsecureUpdateEventPhotoParams{
+			ID:             upload.RecordID,
+			Photo:          media.Photo,
+			PhotoThumbnail: media.PhotoThumbnail,
+			PhotoCard:      media.PhotoCard,
+		})
+	} else {
+		_, err = queries.InsecureUpdateVenuePhoto(ctx, query.InsecureUpdateVenuePhotoParams{
+			ID:             upload.RecordID,
+			Photo:          media.Photo,
+			PhotoThumbnail: media.PhotoThumbnail,
+			PhotoCard:      media.PhotoCard,
+		})
+	}
+	if err != nil {
+		return err
+	}
+
+	return store.Delete(ctx, objectKey)
 }
 
 func main() {
+	// Without AWS the store's own server takes the uploads, and hands each one
+	// to HandleSQSEvent as S3 would through SQS
+	if local, ok := store.(*storage.LocalStore); ok {
+		log.Fatal(local.ListenAndServe(func(ctx context.Context, notification string) {
+			response, err := HandleSQSEvent(ctx, events.SQSEvent{
+				Records: []events.SQSMessage{{MessageId: "local", Body: notification}},
+			})
+			if err != nil || len(response.BatchItemFailures) > 0 {
+				log.Printf("Failed to process notification %s: %v", notification, err)
+			}
+		}))
+	}
 	lambda.Start(HandleSQSEvent)
-}
\ No newline at end of file
+}
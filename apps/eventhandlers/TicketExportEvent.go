@@ -0,0 +1,122 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"time"
+
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/opentix/platform/packages/gohelpers/packages/database"
+	"github.com/opentix/platform/packages/gohelpers/packages/export"
+	"github.com/opentix/platform/packages/gohelpers/packages/query"
+	"github.com/opentix/platform/packages/gohelpers/packages/storage"
+)
+
+var connStr string
+var store storage.Store
+
+// How many exports one run writes, the rest wait for the next run
+const exportBatch = 5
+
+// How long finished exports are kept before they are deleted from storage
+const exportRetention = 7 * 24 * time.Hour
+
+func init() {
+	connStr = database.BuildDatabaseConnectionString()
+	var err error
+	store, err = storage.ExportsFromEnv(context.Background())
+	if err != nil {
+		panic("Failed to set up export storage: " + err.Error())
+	}
+}
+
+// Runs on a schedule. Writes the ticket exports the API left pending to
+// storage, then deletes the ones past exportRetention.
+func HandleExportEvent(ctx context.Context) error {
+	// Connect to the database
+	conn, err := pgx.Connect(ctx, connStr)
+	if err != nil {
+		connStr = database.BuildDatabaseConnectionString()
+		conn, err = pgx.Connect(ctx, connStr)
+		if err != nil {
+			panic("Failed to connect to database: " + err.Error())
+		}
+	}
+	defer conn.Close(ctx)
+	queries := query.New(conn)
+
+	jobs, err := queries.InsecureClaimTicketExports(ctx, exportBatch)
+	if err != nil {
+		return err
+	}
+	for _, job := range jobs {
+		key, err := writeExport(ctx, queries, job)
+		if err != nil {
+			log.Printf("Error exporting tickets for export %s: %v", job.ID, err)
+			err = queries.InsecureFailTicketExport(ctx, query.InsecureFailTicketExportParams{
+				Pk:    job.Pk,
+				Error: pgtype.Text{String: "Failed to write the export", Valid: true},
+			})
+		} else {
+			err = queries.InsecureCompleteTicketExport(ctx, query.InsecureCompleteTicketExportParams{
+				Pk:        job.Pk,
+				ObjectKey: pgtype.Text{String: key, Valid: true},
+			})
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return expireExports(ctx, queries)
+}
+
+// Writes the tickets of the job's event to storage, returning the object key
+func writeExport(ctx context.Context, queries *query.Queries, job query.AppTicketExport) (string, error) {
+	rows, err := queries.GetTicketExportRows(ctx, job.Event)
+	if err != nil {
+		return "", err
+	}
+	format := export.Format(job.Format)
+	var body bytes.Buffer
+	if err := export.WriteTickets(&body, format, rows); err != nil {
+		return "", err
+	}
+	key := export.Key(job.ID, format)
+	if err := store.Put(ctx, key, body.Bytes(), format.ContentType()); err != nil {
+		return "", err
+	}
+	return key, nil
+}
+
+// Deletes the files of exports past exportRetention, then marks them expired.
+// A file is only forgotten once it is gone, so a failed delete is retried on
+// the next run.
+func expireExports(ctx context.Context, queries *query.Queries) error {
+	expired, err := queries.InsecureGetExpiredTicketExports(ctx, pgtype.Timestamptz{
+		Time:  time.Now().Add(-exportRetention),
+		Valid: true,
+	})
+	if err != nil {
+		return err
+	}
+	for _, job := range expired {
+		if job.ObjectKey.Valid {
+			if err := store.Delete(ctx, job.ObjectKey.String); err != nil {
+				return err
+			}
+		}
+		if err := queries.InsecureExpireTicketExport(ctx, job.Pk); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func main() {
+	lambda.Start(HandleExportEvent)
+}
@@ -84,7 +84,7 @@ export default function EventView({
 	// query our backend for the event data using the uuid
 	async function getEventByUUID(UUID: string) {
 		const resp = await fetch(
-			`https://api.dev.opentix.co/user/events?ID=${UUID}`,
+			`https://api.dev.opentix.co/user/events/${UUID}`,
 			{
 				method: 'GET',
 				headers: { Authorization: `Bearer ${client.auth.token}` }
@@ -45,7 +45,7 @@ export function EventCard({ event }: EventCardProps) {
 								width: '100%',
 								maxHeight: '10em'
 							}}
-							src={event.Photo}
+							src={event.Photo ?? undefined}
 							alt="Image of venue"
 						/>
 						<Avatar.Fallback>
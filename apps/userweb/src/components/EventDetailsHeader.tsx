@@ -13,11 +13,16 @@ export default function EventDetailsHeader({ data }: EventDetailsHeaderProps) {
 	const month = eventDate.toLocaleString('default', { month: 'short' });
 	const day = eventDate.getDate();
 	const dayOfWeek = eventDate.toLocaleString('default', { weekday: 'long' });
-	const time = eventDate.toLocaleString('default', {
+	const timeFormat: Intl.DateTimeFormatOptions = {
 		hour: 'numeric',
 		minute: '2-digit',
 		hour12: true
-	});
+	};
+	const time = eventDate.toLocaleString('default', timeFormat);
+	const endTime = new Date(data.EndDatetime).toLocaleString(
+		'default',
+		timeFormat
+	);
 
 	return (
 		<Box width="100%" position="relative" height="15em" overflow="hidden">
@@ -59,7 +64,7 @@ export default function EventDetailsHeader({ data }: EventDetailsHeaderProps) {
 						<br />
 						<Heading size="2" mt="3" style={{ color: '#fff' }}>
 							{dayOfWeek}, {month} {day},{' '}
-							{eventDate.getFullYear()} • {time}
+							{eventDate.getFullYear()} • {time} – {endTime}
 						</Heading>
 					</Box>
 				</Flex>
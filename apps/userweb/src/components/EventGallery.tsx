@@ -0,0 +1,67 @@
+import { AppMedia } from '@platform/types';
+import { Text } from '@radix-ui/themes';
+import { useState } from 'react';
+import styled from 'styled-components';
+
+export interface EventGalleryProps {
+	photos: AppMedia[];
+}
+
+const Grid = styled.div`
+	display: grid;
+	grid-template-columns: repeat(auto-fill, minmax(96px, 1fr));
+	gap: 0.5em;
+`;
+
+const Thumbnail = styled.img<{ $selected: boolean }>`
+	width: 100%;
+	aspect-ratio: 1;
+	object-fit: cover;
+	border-radius: 4px;
+	cursor: pointer;
+	outline: ${(props) => (props.$selected ? '2px solid #00c7b7' : 'none')};
+`;
+
+const Preview = styled.img`
+	width: 100%;
+	max-height: 480px;
+	object-fit: contain;
+	display: block;
+	margin-bottom: 0.5em;
+`;
+
+export default function EventGallery({ photos }: EventGalleryProps) {
+	const [selected, setSelected] = useState<number>(0);
+	const photo = photos[selected] ?? photos[0];
+
+	if (!photo) {
+		return null;
+	}
+
+	return (
+		<div>
+			<Preview
+				src={photo.Photo ?? undefined}
+				alt={photo.AltText ?? photo.Caption ?? ''}
+			/>
+			{photo.Caption && (
+				<Text as="p" size="2" color="gray" mb="2">
+					{photo.Caption}
+				</Text>
+			)}
+			{photos.length > 1 && (
+				<Grid>
+					{photos.map((p, idx) => (
+						<Thumbnail
+							key={p.ID}
+							src={(p.PhotoThumbnail || p.Photo) ?? undefined}
+							alt={p.AltText ?? p.Caption ?? ''}
+							$selected={idx === selected}
+							onClick={() => setSelected(idx)}
+						/>
+					))}
+				</Grid>
+			)}
+		</div>
+	);
+}
@@ -1,5 +1,5 @@
 import { getAuthToken } from '@dynamic-labs/sdk-react-core';
-import { UserEventResponse } from '@platform/types';
+import { PaginatedResponse, UserEventResponse } from '@platform/types';
 import { Box, Flex, Heading, Button, Card, Text } from '@radix-ui/themes';
 import { useEffect, useState, useRef, memo } from 'react';
 import { EventCard } from './EventCard';
@@ -10,36 +10,49 @@ interface rowProps {
 	name: string;
 	cost: string;
 	eventDate: string;
+	location?: string;
 }
 
-function EventRow({ zip, type, name, cost, eventDate }: rowProps) {
+function EventRow({
+	zip,
+	type,
+	name,
+	cost,
+	eventDate,
+	location
+}: rowProps) {
 	const flexRef = useRef(null);
 	const [cards, setCards] = useState<React.ReactNode>(null);
 	const [page, setPage] = useState(1);
+	// cursors[n] is the cursor used to fetch page n, page 1 has none
+	const cursors = useRef<string[]>(['', '']);
 
 	const moveCards = (dist: number, pageDist: number) => {
 		if (!flexRef || !flexRef.current) return;
 		(flexRef.current as Element).scrollLeft += dist;
-		setPage(page + pageDist < 1 ? page : page + pageDist);
+		const next = page + pageDist;
+		if (next < 1 || (next > page && !cursors.current[next])) return;
+		setPage(next);
 	};
 
+	useEffect(() => {
+		cursors.current = ['', ''];
+		setPage(1);
+	}, [zip, type, name, cost, eventDate, location]);
+
 	useEffect(() => {
 		Promise.resolve(
 			getEvents(
-				`Page=${page}&Zip=${zip}&Type=${type === 'Near You' ? '' : type}&Name=${''}&Basecost=${cost}&EventDatetime=${eventDate}`
+				`Cursor=${cursors.current[page] ?? ''}&Zip=${zip}&Type=${type === 'Near You' ? '' : type}&Search=${''}&Basecost=${cost}&EventDatetime=${eventDate}${location ? `&${location}` : ''}`
 			)
 		)
 			.then((resp) => {
-				if (resp !== undefined) {
-					setCards(resp);
-				} else if (page <= 1) {
-					setPage(page);
-				} else {
-					setPage(page - 1);
-				}
+				if (resp === undefined) return;
+				cursors.current[page + 1] = resp.nextCursor;
+				if (resp.cards !== undefined) setCards(resp.cards);
 			})
 			.catch((error) => console.error('EventRow: ', error));
-	}, [page, zip, type, name, cost, eventDate]);
+	}, [page, zip, type, name, cost, eventDate, location]);
 
 	if (cards === null) return null;
 	return (
@@ -97,20 +110,30 @@ async function getEvents(pageRequest: string) {
 
 	if (!resp.ok) {
 		console.error('There was an error fetching data');
-		return (
-			<Card>
-				<Text>There was an error fetching data</Text>
-			</Card>
-		);
+		return {
+			nextCursor: '',
+			cards: (
+				<Card>
+					<Text>There was an error fetching data</Text>
+				</Card>
+			)
+		};
 	}
 
-	const data = await resp.json();
+	const data: PaginatedResponse<UserEventResponse> = await resp.json();
 
-	return await (data && data.length !== 0
-		? data.map((event: UserEventResponse, idx: number) => (
-				<EventCard key={`${idx}:${event.Name}`} event={event} />
-			))
-		: undefined);
+	return {
+		nextCursor: data.next_cursor,
+		cards:
+			data.items.length !== 0
+				? data.items.map((event: UserEventResponse, idx: number) => (
+						<EventCard
+							key={`${idx}:${event.Name}`}
+							event={event}
+						/>
+					))
+				: undefined
+	};
 }
 
 export default memo(EventRow);
@@ -112,7 +112,7 @@ export function TicketCard({
 									width: '100%',
 									maxHeight: '10em'
 								}}
-								src={event.Eventphoto}
+								src={event.Eventphoto ?? undefined}
 								alt="Image of venue"
 							/>
 							<Avatar.Fallback>
@@ -10,6 +10,7 @@ import { FullscreenLoadingMessage } from '@platform/ui';
 import BuyTicketsModal from '../components/BuyTicketsModal';
 import EventDetailsHeader from '../components/EventDetailsHeader';
 import EventDetailsMap from '../components/EventDetailsMap';
+import EventGallery from '../components/EventGallery';
 import ListOfNFTsForEvent from '../components/ListOfNFTsForEvent';
 
 const ColumnsContainer = styled.div`
@@ -76,7 +77,7 @@ export default function EventDetailsPage() {
 	async function getEventDetails() {
 		const authToken = getAuthToken();
 		const resp = await fetch(
-			`${process.env.NX_PUBLIC_API_BASEURL}/user/events?ID=${id}`,
+			`${process.env.NX_PUBLIC_API_BASEURL}/user/events/${id}`,
 			{
 				method: 'GET',
 				headers: { Authorization: `Bearer ${authToken}` }
@@ -139,6 +140,14 @@ export default function EventDetailsPage() {
 										{data.Description}
 									</Text>
 								</Card>
+								{data.Gallery?.length > 0 && (
+									<Card>
+										<Heading size={'4'} mb="2">
+											Photos:
+										</Heading>
+										<EventGallery photos={data.Gallery} />
+									</Card>
+								)}
 								<Card>
 									<Heading size={'4'} mb="2">
 										Tickets for this event:
@@ -1,5 +1,9 @@
 import { getAuthToken } from '@dynamic-labs/sdk-react-core';
-import { AllEventTypesArray, UserEventResponse } from '@platform/types';
+import {
+	AllEventTypesArray,
+	PaginatedResponse,
+	UserEventResponse
+} from '@platform/types';
 import { Text, Box, Flex, Select, TextField, Progress } from '@radix-ui/themes';
 import { useEffect, useState } from 'react';
 import { useSearchParams } from 'react-router-dom';
@@ -28,7 +32,6 @@ export default function EventSearchPage() {
 
 	const [timeoutId, setTimeoutID] = useState<NodeJS.Timeout>();
 	const [eventCards, setEventCards] = useState<JSX.Element[][]>([]);
-	const [pageSize] = useState(1);
 
 	const [, setDataChanged] = useSessionStorage('DataChanged', true);
 
@@ -36,20 +39,16 @@ export default function EventSearchPage() {
 		clearTimeout(timeoutId);
 		setTimeoutID(
 			setTimeout(() => {
-				const requests = [];
+				const requests = [
+					getEvents(
+						params.get('Zip') ?? '',
+						params.get('Type') ?? '',
+						params.get('Name') ?? '',
+						Number(params.get('Cost') ?? defaultCost),
+						params.get('Date') ?? ''
+					)
+				];
 				const cards: JSX.Element[][] = [];
-				for (let i = 0; i < pageSize; i++) {
-					requests.push(
-						getEvents(
-							i + Number(params.get('Page') ?? 1) * pageSize,
-							params.get('Zip') ?? '',
-							params.get('Type') ?? '',
-							params.get('Name') ?? '',
-							Number(params.get('Cost') ?? defaultCost),
-							params.get('Date') ?? ''
-						)
-					);
-				}
 				Promise.allSettled(requests).then((responses) => {
 					responses.map(
 						(response: PromiseSettledResult<JSX.Element[]>) =>
@@ -61,7 +60,7 @@ export default function EventSearchPage() {
 				});
 			}, 500)
 		);
-	}, [params, pageSize]);
+	}, [params]);
 
 	return (
 		<Flex pt="3" justify={'start'} gap="3">
@@ -197,14 +196,13 @@ export default function EventSearchPage() {
 }
 
 async function getEvents(
-	page: number,
 	zip: string,
 	type: string,
 	name: string | null,
 	cost: number,
 	eventDate: string
 ) {
-	const url = `${process.env.NX_PUBLIC_API_BASEURL}/user/events?Page=${page}&Zip=${zip}&Type=${type}&Name=${name ?? ''}&Basecost=${cost}&EventDatetime=${eventDate ? eventDate + ':00.000Z' : ''}`;
+	const url = `${process.env.NX_PUBLIC_API_BASEURL}/user/events?Limit=25&Zip=${zip}&Type=${type}&Search=${name ?? ''}&Basecost=${cost}&EventDatetime=${eventDate ? eventDate + ':00.000Z' : ''}`;
 	const authToken = getAuthToken();
 	const resp = await fetch(url, {
 		method: 'GET',
@@ -216,10 +214,10 @@ async function getEvents(
 		return [];
 	}
 
-	const data = await resp.json();
+	const data: PaginatedResponse<UserEventResponse> = await resp.json();
 
-	return await (data && data.length !== 0
-		? data.map((event: UserEventResponse, idx: number) => (
+	return await (data && data.items.length !== 0
+		? data.items.map((event: UserEventResponse, idx: number) => (
 				<EventCard key={`${idx}:${event.Name}`} event={event} />
 			))
 		: undefined);
@@ -5,28 +5,15 @@ import EventRow from '../components/EventRow';
 
 export default function Home() {
 	const [cards, setCards] = useState<React.ReactNode>(null);
-	const [nearZips, setNearZips] = useState<string>('');
+	const [location, setLocation] = useState<string>('');
 	const [shouldShow, setShouldShow] = useState<boolean>(true);
 
 	useEffect(() => {
 		navigator?.geolocation?.getCurrentPosition(
-			async (position) => {
+			(position) => {
 				const lat = position?.coords?.latitude;
 				const lon = position?.coords?.longitude;
-				const resp = await fetch(
-					`${process.env.NX_PUBLIC_API_BASEURL}/user/zips?Radius=50&Latitude=${lat}&Longitude=${lon}`,
-					{
-						method: 'GET',
-						referrer: 'https://client.dev.opentix.co'
-					}
-				);
-				const json = await resp.json();
-				let zips = '';
-				json?.postcodes?.forEach((pc: number) => {
-					zips += pc.toString() + ', ';
-				});
-				zips = zips.slice(0, -1);
-				if (zips.length > 3) setNearZips(zips);
+				setLocation(`Latitude=${lat}&Longitude=${lon}&Radius=50`);
 			},
 			(error) => {
 				console.error('Geolocation error:', error);
@@ -53,7 +40,7 @@ export default function Home() {
 			setShouldShow(false);
 			showEvents();
 		}
-	}, [shouldShow, setShouldShow, nearZips]);
+	}, [shouldShow, setShouldShow]);
 
 	return (
 		<Flex
@@ -63,10 +50,11 @@ export default function Home() {
 			justify={'center'}
 		>
 			<Box style={{ maxWidth: '80vw' }}>
-				{nearZips !== '' ? (
+				{location !== '' ? (
 					<EventRow
 						key={'Near You'}
-						zip={nearZips}
+						zip={''}
+						location={location}
 						type={'Near You'}
 						name={''}
 						cost={'1000000'}
@@ -217,7 +217,7 @@ export default function Profile() {
 	// query our backend for the event data using the uuid
 	async function getEventByUUID(UUID: string) {
 		const tk = getAuthToken();
-		const resp = await fetch(`${BASEURL}/user/events?ID=${UUID}`, {
+		const resp = await fetch(`${BASEURL}/user/events/${UUID}`, {
 			method: 'GET',
 			headers: { Authorization: `Bearer ${tk}` }
 		});
@@ -166,7 +166,7 @@ export default function EventDetails({
 
 	const getFunc = useCallback(async () => {
 		const resp = await fetch(
-			`${process.env.EXPO_PUBLIC_API_BASEURL}/vendor/events?ID=${Event}`,
+			`${process.env.EXPO_PUBLIC_API_BASEURL}/vendor/events/${Event}`,
 			{
 				headers: { Authorization: `Bearer ${client.auth.token}` }
 			}
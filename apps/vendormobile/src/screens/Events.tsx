@@ -1,5 +1,5 @@
 import AntDesign from '@expo/vector-icons/AntDesign';
-import { Event } from '@platform/types';
+import { Event, PaginatedResponse } from '@platform/types';
 import { useNavigation } from '@react-navigation/native';
 import { NativeStackScreenProps } from '@react-navigation/native-stack';
 import { useEffect, useCallback, useState, useRef } from 'react';
@@ -50,13 +50,13 @@ export default function Events({
 
 	const getEvents = useCallback(async () => {
 		const resp = await fetch(
-			`${process.env.EXPO_PUBLIC_API_BASEURL}/vendor/events?Venue=${Venue}&EventDatetime=${new Date().toISOString()}`,
+			`${process.env.EXPO_PUBLIC_API_BASEURL}/vendor/events?Venue=${Venue}&EventDatetime=${new Date().toISOString()}&Limit=100`,
 			{ headers: { Authorization: `Bearer ${client.auth.token}` } }
 		);
-		const data = await resp.json();
+		const data: PaginatedResponse<Event> = await resp.json();
 		setShouldFetch(false);
 
-		setEvents(data);
+		setEvents(data.items);
 	}, []);
 
 	useEffect(() => {
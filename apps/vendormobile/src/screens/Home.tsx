@@ -1,5 +1,5 @@
 import AntDesign from '@expo/vector-icons/AntDesign';
-import { Venue } from '@platform/types';
+import { PaginatedResponse, Venue } from '@platform/types';
 import { useNavigation } from '@react-navigation/native';
 import { useEffect, useCallback, useState } from 'react';
 import {
@@ -25,7 +25,6 @@ export default function Home() {
 	const [shouldFetch, setShouldFetch] = useState<boolean>(false);
 	const [timeoutDone, setTimeoutDone] = useState<boolean>(false);
 	const [venues, setVenues] = useState<Venue[]>([]);
-	const [page, setPage] = useState<number>(1);
 
 	const [cardHeights, setCardHeights] = useState<Record<string, number>>({});
 
@@ -45,14 +44,14 @@ export default function Home() {
 
 	const getVenues = useCallback(async () => {
 		const resp = await fetch(
-			`${process.env.EXPO_PUBLIC_API_BASEURL}/vendor/venues?Page=${page}`,
+			`${process.env.EXPO_PUBLIC_API_BASEURL}/vendor/venues?Limit=100`,
 			{ headers: { Authorization: `Bearer ${client.auth.token}` } }
 		);
 
-		const data = await resp.json();
+		const data: PaginatedResponse<Venue> = await resp.json();
 		setShouldFetch(false);
 
-		setVenues(data);
+		setVenues(data.items);
 	}, []);
 
 	useEffect(() => {
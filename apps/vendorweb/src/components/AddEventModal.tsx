@@ -2,12 +2,14 @@ import { getAuthToken } from '@dynamic-labs/sdk-react-core';
 import {
 	AllVenuesListSimplifiedResponse,
 	EventCreationFormData,
-	AllEventTypesArray
+	EventOverlapResponse,
+	AllEventTypesArray,
+	getFieldErrors
 } from '@platform/types';
 import { Select, TextField, Text } from '@radix-ui/themes';
 import { useEffect, useState } from 'react';
 import { useNavigate } from 'react-router-dom';
-import { BaseModalForm } from '@platform/ui';
+import { BaseModalForm, FieldErrorText } from '@platform/ui';
 
 type AddEventModalProps = {
 	onClose: () => void;
@@ -27,6 +29,17 @@ export default function AddEventModal({
 	const [isSubmitting, setIsSubmitting] = useState<boolean>(false);
 	const [shouldShowError, setShouldShowError] = useState<boolean>(false);
 	const [errorMessage, setErrorMessage] = useState<string>('');
+	// Kept across retries of a submission so a timed out request that went
+	// through isn't created twice
+	const [idempotencyKey, setIdempotencyKey] = useState(() =>
+		crypto.randomUUID()
+	);
+	const [fieldErrors, setFieldErrors] = useState<Record<string, string>>(
+		{}
+	);
+	// Set once the server reports overlapping events, so submitting again
+	// schedules the event anyway
+	const [allowOverlap, setAllowOverlap] = useState<boolean>(false);
 	const [venueList, setVenueList] = useState<
 		AllVenuesListSimplifiedResponse[]
 	>([]);
@@ -35,6 +48,7 @@ export default function AddEventModal({
 		Name: '',
 		Type: '',
 		EventDatetime: '',
+		EndDatetime: '',
 		Description: '',
 		Disclaimer: '',
 		Basecost: '',
@@ -44,6 +58,10 @@ export default function AddEventModal({
 
 	const handleChange = (e: React.ChangeEvent<HTMLInputElement>) => {
 		const { name, value } = e.target;
+		// A different time has to be checked for overlaps again
+		if (name === 'EventDatetime' || name === 'EndDatetime') {
+			setAllowOverlap(false);
+		}
 
 		setLengths({
 			...lengths,
@@ -73,6 +91,8 @@ export default function AddEventModal({
 			event.Name === '' ||
 			event.Type === '' ||
 			!isIsoString(event.EventDatetime) ||
+			!isIsoString(event.EndDatetime) ||
+			event.EndDatetime <= event.EventDatetime ||
 			event.Description === '' ||
 			event.Disclaimer === '' ||
 			Number.isNaN(event.Basecost) ||
@@ -104,11 +124,13 @@ export default function AddEventModal({
 			Name: formData.Name,
 			Type: formData.Type,
 			EventDatetime: new Date(formData.EventDatetime).toISOString(),
+			EndDatetime: new Date(formData.EndDatetime).toISOString(),
 			Description: formData.Description,
 			Disclaimer: formData.Disclaimer,
 			Basecost: parseFloat(formData.Basecost),
 			NumUnique: parseInt(formData.NumUnique),
-			NumGa: parseInt(formData.NumGa)
+			NumGa: parseInt(formData.NumGa),
+			AllowOverlap: allowOverlap
 		};
 
 		if (!validate(eventToSubmit)) {
@@ -116,6 +138,7 @@ export default function AddEventModal({
 			return;
 		}
 		setShouldShowError(false);
+		setFieldErrors({});
 		setIsSubmitting(true);
 		try {
 			const authToken = getAuthToken();
@@ -125,14 +148,28 @@ export default function AddEventModal({
 					method: 'POST',
 					headers: {
 						'Content-Type': 'application/json',
-						Authorization: `Bearer ${authToken}`
+						Authorization: `Bearer ${authToken}`,
+						'Idempotency-Key': idempotencyKey
 					},
 					body: JSON.stringify(eventToSubmit)
 				}
 			);
 			const data = await res.json();
 			if (!res.ok) {
-				setErrorMessage(res.status + ': ' + data.message);
+				// The server has answered, an edited form is a new request
+				setIdempotencyKey(crypto.randomUUID());
+				setFieldErrors(getFieldErrors(data));
+				if (data.code === 'event_overlap') {
+					const overlaps = (data as EventOverlapResponse).events
+						.map((e) => e.Name)
+						.join(', ');
+					setAllowOverlap(true);
+					setErrorMessage(
+						`Overlaps ${overlaps} at this venue. Submit again to schedule it anyway.`
+					);
+				} else {
+					setErrorMessage(res.status + ': ' + data.message);
+				}
 				setShouldShowError(true);
 				setIsSubmitting(false);
 				return;
@@ -145,6 +182,7 @@ export default function AddEventModal({
 			return;
 		}
 		setIsSubmitting(false);
+		setIdempotencyKey(crypto.randomUUID());
 		onSuccess();
 		onClose();
 	};
@@ -153,7 +191,7 @@ export default function AddEventModal({
 		try {
 			const authToken = getAuthToken();
 			const res = await fetch(
-				process.env.NX_PUBLIC_API_BASEURL + '/vendor/venues?all=true',
+				process.env.NX_PUBLIC_API_BASEURL + '/vendor/venues/all',
 				{
 					method: 'GET',
 					headers: {
@@ -214,6 +252,7 @@ export default function AddEventModal({
 					)}
 				</Select.Content>
 			</Select.Root>
+			<FieldErrorText message={fieldErrors.Venue} />
 			<label>
 				<Text as="div" size="2" mb="1" weight="bold">
 					Name
@@ -223,7 +262,9 @@ export default function AddEventModal({
 					placeholder="My Event"
 					value={formData.Name}
 					onChange={handleChange}
+					color={fieldErrors.Name ? 'red' : undefined}
 				/>
+				<FieldErrorText message={fieldErrors.Name} />
 			</label>
 			<label>
 				<Text as="div" size="2" mb="1" weight="bold">
@@ -247,6 +288,7 @@ export default function AddEventModal({
 					))}
 				</Select.Content>
 			</Select.Root>
+			<FieldErrorText message={fieldErrors.Type} />
 			<label>
 				<Text as="div" size="2" mb="1" weight="bold">
 					Event Date
@@ -256,7 +298,22 @@ export default function AddEventModal({
 					value={formData.EventDatetime}
 					onChange={handleChange}
 					type="datetime-local"
+					color={fieldErrors.EventDatetime ? 'red' : undefined}
+				/>
+				<FieldErrorText message={fieldErrors.EventDatetime} />
+			</label>
+			<label>
+				<Text as="div" size="2" mb="1" weight="bold">
+					End Date
+				</Text>
+				<TextField.Root
+					name="EndDatetime"
+					value={formData.EndDatetime}
+					onChange={handleChange}
+					type="datetime-local"
+					color={fieldErrors.EndDatetime ? 'red' : undefined}
 				/>
+				<FieldErrorText message={fieldErrors.EndDatetime} />
 			</label>
 			<label>
 				<Text as="div" size="2" mb="1" weight="bold">
@@ -267,7 +324,9 @@ export default function AddEventModal({
 					placeholder="This is a description"
 					value={formData.Description}
 					onChange={handleChange}
+					color={fieldErrors.Description ? 'red' : undefined}
 				/>
+				<FieldErrorText message={fieldErrors.Description} />
 			</label>
 			<label>
 				<Text as="div" size="2" mb="1" weight="bold">
@@ -278,7 +337,9 @@ export default function AddEventModal({
 					placeholder="This is a disclaimer"
 					value={formData.Disclaimer}
 					onChange={handleChange}
+					color={fieldErrors.Disclaimer ? 'red' : undefined}
 				/>
+				<FieldErrorText message={fieldErrors.Disclaimer} />
 			</label>
 			<label>
 				<Text as="div" size="2" mb="1" weight="bold">
@@ -289,7 +350,9 @@ export default function AddEventModal({
 					placeholder="100"
 					value={formData.Basecost}
 					onChange={handleChange}
+					color={fieldErrors.Basecost ? 'red' : undefined}
 				/>
+				<FieldErrorText message={fieldErrors.Basecost} />
 			</label>
 			<label>
 				<Text as="div" size="2" mb="1" weight="bold">
@@ -300,7 +363,9 @@ export default function AddEventModal({
 					placeholder="100"
 					value={formData.NumUnique}
 					onChange={handleChange}
+					color={fieldErrors.NumUnique ? 'red' : undefined}
 				/>
+				<FieldErrorText message={fieldErrors.NumUnique} />
 			</label>
 			<label>
 				<Text as="div" size="2" mb="1" weight="bold">
@@ -311,7 +376,9 @@ export default function AddEventModal({
 					placeholder="100"
 					value={formData.NumGa}
 					onChange={handleChange}
+					color={fieldErrors.NumGa ? 'red' : undefined}
 				/>
+				<FieldErrorText message={fieldErrors.NumGa} />
 			</label>
 		</BaseModalForm>
 	);
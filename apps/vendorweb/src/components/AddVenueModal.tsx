@@ -1,9 +1,9 @@
 import { getAuthToken } from '@dynamic-labs/sdk-react-core';
-import { VenueCreationFormData } from '@platform/types';
+import { getFieldErrors, VenueCreationFormData } from '@platform/types';
 import { TextField, Text } from '@radix-ui/themes';
 import { useState } from 'react';
 import { useNavigate } from 'react-router-dom';
-import { BaseModalForm } from '@platform/ui';
+import { BaseModalForm, FieldErrorText } from '@platform/ui';
 
 type AddVenueModalProps = {
 	onClose: () => void;
@@ -18,15 +18,21 @@ export default function AddVenueModal({
 	const [isSubmitting, setIsSubmitting] = useState<boolean>(false);
 	const [shouldShowError, setShouldShowError] = useState<boolean>(false);
 	const [errorMessage, setErrorMessage] = useState<string>('');
+	// Kept across retries of a submission so a timed out request that went
+	// through isn't created twice
+	const [idempotencyKey, setIdempotencyKey] = useState(() =>
+		crypto.randomUUID()
+	);
+	const [fieldErrors, setFieldErrors] = useState<Record<string, string>>(
+		{}
+	);
 	const [formData, setFormData] = useState({
 		Name: '',
 		StreetAddress: '',
 		Zip: '',
 		City: '',
 		StateCode: '',
-		StateName: '',
 		CountryCode: '',
-		CountryName: '',
 		NumUnique: '',
 		NumGa: ''
 	});
@@ -43,12 +49,10 @@ export default function AddVenueModal({
 		if (
 			venue.Name === '' ||
 			venue.StreetAddress === '' ||
-			!/^\d{5}$/.test(venue.Zip.toString()) ||
 			venue.City === '' ||
-			!/^[A-Z]{2}-[A-Z]{2}$/.test(venue.StateCode) ||
+			!/^[A-Z]{2}-[A-Z0-9]{1,3}$/.test(venue.StateCode) ||
 			venue.StateCode === '' ||
 			!/^[A-Z]{2}$/.test(venue.CountryCode) ||
-			venue.CountryName === '' ||
 			Number.isNaN(venue.NumGa) ||
 			Number.isNaN(venue.NumUnique) ||
 			(venue.NumUnique === 0 && venue.NumGa === 0) ||
@@ -70,9 +74,7 @@ export default function AddVenueModal({
 			Zip: formData.Zip,
 			City: formData.City,
 			StateCode: formData.StateCode,
-			StateName: formData.StateName,
 			CountryCode: formData.CountryCode,
-			CountryName: formData.CountryName,
 			NumUnique: parseInt(formData.NumUnique),
 			NumGa: parseInt(formData.NumGa)
 		};
@@ -83,6 +85,7 @@ export default function AddVenueModal({
 		}
 
 		setShouldShowError(false);
+		setFieldErrors({});
 		setIsSubmitting(true);
 		try {
 			const authToken = getAuthToken();
@@ -92,13 +95,17 @@ export default function AddVenueModal({
 					method: 'POST',
 					headers: {
 						'Content-Type': 'application/json',
-						Authorization: `Bearer ${authToken}`
+						Authorization: `Bearer ${authToken}`,
+						'Idempotency-Key': idempotencyKey
 					},
 					body: JSON.stringify(obj)
 				}
 			);
 			const data = await res.json();
 			if (!res.ok) {
+				// The server has answered, an edited form is a new request
+				setIdempotencyKey(crypto.randomUUID());
+				setFieldErrors(getFieldErrors(data));
 				setErrorMessage(res.status + ': ' + data.message);
 				setShouldShowError(true);
 				setIsSubmitting(false);
@@ -112,6 +119,7 @@ export default function AddVenueModal({
 			return;
 		}
 		setIsSubmitting(false);
+		setIdempotencyKey(crypto.randomUUID());
 		onSuccess();
 		onClose();
 	};
@@ -134,7 +142,9 @@ export default function AddVenueModal({
 					placeholder="My Venue"
 					value={formData.Name}
 					onChange={handleChange}
+					color={fieldErrors.Name ? 'red' : undefined}
 				/>
+				<FieldErrorText message={fieldErrors.Name} />
 			</label>
 			<label>
 				<Text as="div" size="2" mb="1" weight="bold">
@@ -145,7 +155,9 @@ export default function AddVenueModal({
 					placeholder="123 Main Street"
 					value={formData.StreetAddress}
 					onChange={handleChange}
+					color={fieldErrors.StreetAddress ? 'red' : undefined}
 				/>
+				<FieldErrorText message={fieldErrors.StreetAddress} />
 			</label>
 			<label>
 				<Text as="div" size="2" mb="1" weight="bold">
@@ -156,7 +168,9 @@ export default function AddVenueModal({
 					placeholder="12345"
 					value={formData.Zip}
 					onChange={handleChange}
+					color={fieldErrors.Zip ? 'red' : undefined}
 				/>
+				<FieldErrorText message={fieldErrors.Zip} />
 			</label>
 			<label>
 				<Text as="div" size="2" mb="1" weight="bold">
@@ -167,7 +181,9 @@ export default function AddVenueModal({
 					placeholder="Knoxville"
 					value={formData.City}
 					onChange={handleChange}
+					color={fieldErrors.City ? 'red' : undefined}
 				/>
+				<FieldErrorText message={fieldErrors.City} />
 			</label>
 			<label>
 				<Text as="div" size="2" mb="1" weight="bold">
@@ -175,22 +191,13 @@ export default function AddVenueModal({
 				</Text>
 				<TextField.Root
 					name="StateCode"
-					placeholder="TN-US"
+					placeholder="US-TN"
 					value={formData.StateCode}
 					onChange={handleChange}
-					pattern="^[A-Z]{2}-[A-Z]{2}$"
-				/>
-			</label>
-			<label>
-				<Text as="div" size="2" mb="1" weight="bold">
-					State Name
-				</Text>
-				<TextField.Root
-					name="StateName"
-					placeholder="Tennessee"
-					value={formData.StateName}
-					onChange={handleChange}
+					pattern="^[A-Z]{2}-[A-Z0-9]{1,3}$"
+					color={fieldErrors.StateCode ? 'red' : undefined}
 				/>
+				<FieldErrorText message={fieldErrors.StateCode} />
 			</label>
 			<label>
 				<Text as="div" size="2" mb="1" weight="bold">
@@ -202,18 +209,9 @@ export default function AddVenueModal({
 					value={formData.CountryCode}
 					onChange={handleChange}
 					pattern="^[A-Z]{2}$"
+					color={fieldErrors.CountryCode ? 'red' : undefined}
 				/>
-			</label>
-			<label>
-				<Text as="div" size="2" mb="1" weight="bold">
-					Country Name
-				</Text>
-				<TextField.Root
-					name="CountryName"
-					placeholder="United States"
-					value={formData.CountryName}
-					onChange={handleChange}
-				/>
+				<FieldErrorText message={fieldErrors.CountryCode} />
 			</label>
 			<label>
 				<Text as="div" size="2" mb="1" weight="bold">
@@ -224,7 +222,9 @@ export default function AddVenueModal({
 					placeholder="Unique Seats Quantity"
 					value={formData.NumUnique}
 					onChange={handleChange}
+					color={fieldErrors.NumUnique ? 'red' : undefined}
 				/>
+				<FieldErrorText message={fieldErrors.NumUnique} />
 			</label>
 			<label>
 				<Text as="div" size="2" mb="1" weight="bold">
@@ -235,7 +235,9 @@ export default function AddVenueModal({
 					placeholder="General Admission Quantity"
 					value={formData.NumGa}
 					onChange={handleChange}
+					color={fieldErrors.NumGa ? 'red' : undefined}
 				/>
+				<FieldErrorText message={fieldErrors.NumGa} />
 			</label>
 		</BaseModalForm>
 	);
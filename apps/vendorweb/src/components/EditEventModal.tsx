@@ -1,19 +1,23 @@
 import { getAuthToken } from '@dynamic-labs/sdk-react-core';
-import { EventEditableFields } from '@platform/types';
+import { EventEditableFields, getFieldErrors } from '@platform/types';
 import { AllEventTypesArray } from '@platform/types';
 import { TextField, Text, Select } from '@radix-ui/themes';
 import { useState } from 'react';
 import { useNavigate } from 'react-router-dom';
-import { BaseModalForm } from '@platform/ui';
+import { BaseModalForm, FieldErrorText } from '@platform/ui';
 
 type EditEventModalProps = {
 	onClose: () => void;
 	onSuccess: () => void;
 	pk: number;
+	// Version the record was loaded at, so edits made meanwhile aren't
+	// overwritten
+	version: number;
 };
 
 export default function EditEventModal({
 	pk,
+	version,
 	onClose,
 	onSuccess
 }: EditEventModalProps) {
@@ -26,6 +30,9 @@ export default function EditEventModal({
 	const [isSubmitting, setIsSubmitting] = useState<boolean>(false);
 	const [shouldShowError, setShouldShowError] = useState<boolean>(false);
 	const [errorMessage, setErrorMessage] = useState<string>('');
+	const [fieldErrors, setFieldErrors] = useState<Record<string, string>>(
+		{}
+	);
 	const [formData, setFormData] = useState<EventEditableFields>({
 		Type: '',
 		Description: '',
@@ -71,6 +78,7 @@ export default function EditEventModal({
 		body = { ...body, Pk: pk };
 		console.log(body);
 		setShouldShowError(false);
+		setFieldErrors({});
 		setIsSubmitting(true);
 		try {
 			const authToken = getAuthToken();
@@ -80,13 +88,15 @@ export default function EditEventModal({
 					method: 'PATCH',
 					headers: {
 						'Content-Type': 'application/json',
-						Authorization: `Bearer ${authToken}`
+						Authorization: `Bearer ${authToken}`,
+						...(version ? { 'If-Match': `"${version}"` } : {})
 					},
 					body: JSON.stringify(body)
 				}
 			);
 			const data = await res.json();
 			if (!res.ok) {
+				setFieldErrors(getFieldErrors(data));
 				setErrorMessage(res.status + ': ' + data.message);
 				setShouldShowError(true);
 				setIsSubmitting(false);
@@ -137,6 +147,7 @@ export default function EditEventModal({
 					))}
 				</Select.Content>
 			</Select.Root>
+			<FieldErrorText message={fieldErrors.Type} />
 
 			<label>
 				<Text as="div" size="2" mb="1" weight="bold">
@@ -147,7 +158,9 @@ export default function EditEventModal({
 					placeholder="This is a description"
 					value={formData.Description}
 					onChange={handleChange}
+					color={fieldErrors.Description ? 'red' : undefined}
 				/>
+				<FieldErrorText message={fieldErrors.Description} />
 			</label>
 			<label>
 				<Text as="div" size="2" mb="1" weight="bold">
@@ -158,7 +171,9 @@ export default function EditEventModal({
 					placeholder="This is a disclaimer"
 					value={formData.Disclaimer}
 					onChange={handleChange}
+					color={fieldErrors.Disclaimer ? 'red' : undefined}
 				/>
+				<FieldErrorText message={fieldErrors.Disclaimer} />
 			</label>
 		</BaseModalForm>
 	);
@@ -1,18 +1,22 @@
 import { getAuthToken } from '@dynamic-labs/sdk-react-core';
-import { VenueEditableFields } from '@platform/types';
+import { getFieldErrors, VenueEditableFields } from '@platform/types';
 import { TextField, Text } from '@radix-ui/themes';
 import { useState } from 'react';
 import { useNavigate } from 'react-router-dom';
-import { BaseModalForm } from '@platform/ui';
+import { BaseModalForm, FieldErrorText } from '@platform/ui';
 
 type EditVenueModalProps = {
 	onClose: () => void;
 	onSuccess: () => void;
 	pk: number;
+	// Version the record was loaded at, so edits made meanwhile aren't
+	// overwritten
+	version: number;
 };
 
 export default function EditVenueModal({
 	pk,
+	version,
 	onClose,
 	onSuccess
 }: EditVenueModalProps) {
@@ -20,15 +24,16 @@ export default function EditVenueModal({
 	const [isSubmitting, setIsSubmitting] = useState<boolean>(false);
 	const [shouldShowError, setShouldShowError] = useState<boolean>(false);
 	const [errorMessage, setErrorMessage] = useState<string>('');
+	const [fieldErrors, setFieldErrors] = useState<Record<string, string>>(
+		{}
+	);
 	const [formData, setFormData] = useState<VenueEditableFields>({
 		Name: '',
 		StreetAddress: '',
 		Zip: '',
 		City: '',
 		StateCode: '',
-		StateName: '',
-		CountryCode: '',
-		CountryName: ''
+		CountryCode: ''
 	});
 
 	const handleChange = (e: React.ChangeEvent<HTMLInputElement>) => {
@@ -52,16 +57,11 @@ export default function EditVenueModal({
 
 	const validate = (venue: { [key: string]: any }) => {
 		for (const key of Object.keys(venue)) {
-			if (key === 'Zip' && !/^\d{5}$/.test(venue[key].toString())) {
-				setErrorMessage('Zip code must be 5 digits');
-				setShouldShowError(true);
-				return false;
-			}
 			if (
 				key === 'StateCode' &&
-				!/^[A-Z]{2}-[A-Z]{2}$/.test(venue[key].toString())
+				!/^[A-Z]{2}-[A-Z0-9]{1,3}$/.test(venue[key].toString())
 			) {
-				setErrorMessage('State code must be in the format XX-XX');
+				setErrorMessage('State code must be in the format XX-XXX');
 				setShouldShowError(true);
 				return false;
 			}
@@ -90,6 +90,7 @@ export default function EditVenueModal({
 
 		body = { ...body, Pk: pk };
 		setShouldShowError(false);
+		setFieldErrors({});
 		setIsSubmitting(true);
 		try {
 			const authToken = getAuthToken();
@@ -99,13 +100,15 @@ export default function EditVenueModal({
 					method: 'PATCH',
 					headers: {
 						'Content-Type': 'application/json',
-						Authorization: `Bearer ${authToken}`
+						Authorization: `Bearer ${authToken}`,
+						...(version ? { 'If-Match': `"${version}"` } : {})
 					},
 					body: JSON.stringify(body)
 				}
 			);
 			const data = await res.json();
 			if (!res.ok) {
+				setFieldErrors(getFieldErrors(data));
 				setErrorMessage(res.status + ': ' + data.message);
 				setShouldShowError(true);
 				setIsSubmitting(false);
@@ -143,7 +146,9 @@ export default function EditVenueModal({
 					placeholder="My Venue"
 					value={formData.Name}
 					onChange={handleChange}
+					color={fieldErrors.Name ? 'red' : undefined}
 				/>
+				<FieldErrorText message={fieldErrors.Name} />
 			</label>
 			<label>
 				<Text as="div" size="2" mb="1" weight="bold">
@@ -154,7 +159,9 @@ export default function EditVenueModal({
 					placeholder="123 Main Street"
 					value={formData.StreetAddress}
 					onChange={handleChange}
+					color={fieldErrors.StreetAddress ? 'red' : undefined}
 				/>
+				<FieldErrorText message={fieldErrors.StreetAddress} />
 			</label>
 			<label>
 				<Text as="div" size="2" mb="1" weight="bold">
@@ -165,7 +172,9 @@ export default function EditVenueModal({
 					placeholder="12345"
 					value={formData.Zip}
 					onChange={handleChange}
+					color={fieldErrors.Zip ? 'red' : undefined}
 				/>
+				<FieldErrorText message={fieldErrors.Zip} />
 			</label>
 			<label>
 				<Text as="div" size="2" mb="1" weight="bold">
@@ -176,7 +185,9 @@ export default function EditVenueModal({
 					placeholder="Knoxville"
 					value={formData.City}
 					onChange={handleChange}
+					color={fieldErrors.City ? 'red' : undefined}
 				/>
+				<FieldErrorText message={fieldErrors.City} />
 			</label>
 			<label>
 				<Text as="div" size="2" mb="1" weight="bold">
@@ -184,22 +195,13 @@ export default function EditVenueModal({
 				</Text>
 				<TextField.Root
 					name="StateCode"
-					placeholder="TN-US"
+					placeholder="US-TN"
 					value={formData.StateCode}
 					onChange={handleChange}
-					pattern="^[A-Z]{2}-[A-Z]{2}$"
-				/>
-			</label>
-			<label>
-				<Text as="div" size="2" mb="1" weight="bold">
-					State Name
-				</Text>
-				<TextField.Root
-					name="StateName"
-					placeholder="Tennessee"
-					value={formData.StateName}
-					onChange={handleChange}
+					pattern="^[A-Z]{2}-[A-Z0-9]{1,3}$"
+					color={fieldErrors.StateCode ? 'red' : undefined}
 				/>
+				<FieldErrorText message={fieldErrors.StateCode} />
 			</label>
 			<label>
 				<Text as="div" size="2" mb="1" weight="bold">
@@ -211,18 +213,9 @@ export default function EditVenueModal({
 					value={formData.CountryCode}
 					onChange={handleChange}
 					pattern="^[A-Z]{2}$"
+					color={fieldErrors.CountryCode ? 'red' : undefined}
 				/>
-			</label>
-			<label>
-				<Text as="div" size="2" mb="1" weight="bold">
-					Country Name
-				</Text>
-				<TextField.Root
-					name="CountryName"
-					placeholder="United States"
-					value={formData.CountryName}
-					onChange={handleChange}
-				/>
+				<FieldErrorText message={fieldErrors.CountryCode} />
 			</label>
 		</BaseModalForm>
 	);
@@ -25,6 +25,10 @@ export default function VendorTable({ rowData, tableType }: tableData) {
 			case 'Vendor':
 			case 'Venue':
 			case 'Photo':
+			case 'PhotoThumbnail':
+			case 'PhotoCard':
+			case 'Version':
+			case 'UpdatedAt':
 				continue;
 			default:
 				header.push(
@@ -49,6 +53,10 @@ export default function VendorTable({ rowData, tableType }: tableData) {
 				case 'Vendor':
 				case 'Venue':
 				case 'Photo':
+				case 'PhotoThumbnail':
+				case 'PhotoCard':
+				case 'Version':
+				case 'UpdatedAt':
 					continue;
 				case 'TransactionHash': {
 					const val = row[label as keyof typeof row];
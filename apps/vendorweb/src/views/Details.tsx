@@ -1,7 +1,7 @@
 import { isEthereumWallet } from '@dynamic-labs/ethereum';
 import { getAuthToken, useDynamicContext } from '@dynamic-labs/sdk-react-core';
 import { Group } from '@mantine/core';
-import { Dropzone, FileWithPath, IMAGE_MIME_TYPE } from '@mantine/dropzone';
+import { Dropzone, FileWithPath, MIME_TYPES } from '@mantine/dropzone';
 import {
 	ContractABI,
 	ContractAddress,
@@ -29,6 +29,15 @@ import EditVenueModal from '../components/EditVenueModal';
 import ListOfNFTsForEvent from '../components/ListOfNFTsForEvent';
 import MintTicketsModal from '../components/MintTicketsModal';
 
+// The photo uploads the API accepts
+const PHOTO_MIME_TYPES = [
+	MIME_TYPES.jpeg,
+	MIME_TYPES.png,
+	MIME_TYPES.gif,
+	MIME_TYPES.webp
+];
+const MAX_PHOTO_SIZE = 10 * 1024 ** 2;
+
 //70/30 left right column split
 const LeftColumn = styled.div`
 	width: 50%;
@@ -94,7 +103,7 @@ export default function Details({ typestring }: DetailsProps) {
 			const token = getAuthToken();
 			const res = await fetch(
 				process.env.NX_PUBLIC_API_BASEURL +
-					`/vendor/${typestring}s/?ID=${id}`,
+					`/vendor/${typestring}s/${id}`,
 				{
 					method: 'GET',
 					headers: {
@@ -129,7 +138,9 @@ export default function Details({ typestring }: DetailsProps) {
 					},
 					body: JSON.stringify({
 						ID: id,
-						Filename: file.name
+						Filename: file.name,
+						ContentType: file.type,
+						Size: file.size
 					})
 				}
 			);
@@ -147,7 +158,8 @@ export default function Details({ typestring }: DetailsProps) {
 			const res2 = await fetch(signedUrl, {
 				method: 'PUT',
 				headers: {
-					'Content-Type': file.type || 'application/octet-stream'
+					// Must match the type the upload URL was signed for
+					'Content-Type': file.type
 				},
 				body: file
 			});
@@ -217,7 +229,9 @@ export default function Details({ typestring }: DetailsProps) {
 					method: 'POST',
 					headers: {
 						Authorization: `Bearer ${token}`,
-						'Content-Type': 'application/json'
+						'Content-Type': 'application/json',
+						// A range of tickets is only ever minted once
+						'Idempotency-Key': `${id}:${min}-${max}`
 					},
 					body: JSON.stringify({
 						Event: id,
@@ -341,76 +355,86 @@ export default function Details({ typestring }: DetailsProps) {
 					<Box width="100%">
 						<Flex direction="column" gap="3">
 							<Card>
-								<Heading size={'4'}>Photo</Heading>
+								<Heading size={'4'}>Photos</Heading>
 								<Flex direction="column" gap="3" align="center">
-									{data &&
-										(data?.Photo ? (
-											<img
-												src={data.Photo}
-												alt="Venue"
-												style={{ maxWidth: '80%' }}
-											/>
-										) : (
-											<Box
-												style={{
-													width: '90%'
+									{data?.Photo && (
+										<img
+											src={data.Photo}
+											alt="Cover"
+											style={{ maxWidth: '80%' }}
+										/>
+									)}
+									{/* Each upload is added to the gallery, the first becomes the cover */}
+									{data && (
+										<Box
+											style={{
+												width: '90%'
+											}}
+										>
+											<Dropzone
+												onDrop={(files) =>
+													handleFileUpload(files)
+												}
+												onReject={() => {
+													setErrorMessage(
+														'Photos must be a JPEG, PNG, GIF or WebP of at most 10 MB'
+													);
+													setShouldShowError(true);
 												}}
+												maxSize={MAX_PHOTO_SIZE}
+												accept={PHOTO_MIME_TYPES}
+												loading={isImageUploading}
 											>
-												<Dropzone
-													onDrop={(files) =>
-														handleFileUpload(files)
-													}
-													onReject={(files) =>
-														console.log(
-															'rejected files',
-															files
-														)
-													}
-													maxSize={5 * 1024 ** 2}
-													accept={IMAGE_MIME_TYPE}
-													loading={isImageUploading}
+												<Group
+													justify="center"
+													gap="xl"
+													mih={220}
+													style={{
+														pointerEvents:
+															'none'
+													}}
 												>
-													<Group
-														justify="center"
-														gap="xl"
-														mih={220}
-														style={{
-															pointerEvents:
-																'none'
-														}}
-													>
-														<Dropzone.Accept>
-															<IconUpload
-																size={52}
-																color="#00c7b7"
-																stroke={1.5}
-															/>
-														</Dropzone.Accept>
-														<Dropzone.Reject>
-															<IconX
-																size={52}
-																color="#ff5252"
-																stroke={1.5}
-															/>
-														</Dropzone.Reject>
-														<Dropzone.Idle>
-															<IconPhoto
-																size={52}
-																color="#666"
-																stroke={1.5}
-															/>
-														</Dropzone.Idle>
+													<Dropzone.Accept>
+														<IconUpload
+															size={52}
+															color="#00c7b7"
+															stroke={1.5}
+														/>
+													</Dropzone.Accept>
+													<Dropzone.Reject>
+														<IconX
+															size={52}
+															color="#ff5252"
+															stroke={1.5}
+														/>
+													</Dropzone.Reject>
+													<Dropzone.Idle>
+														<IconPhoto
+															size={52}
+															color="#666"
+															stroke={1.5}
+														/>
+													</Dropzone.Idle>
 
-														<div>
-															<Text>
-																Drag an image
-																here or click.
-															</Text>
-														</div>
-													</Group>
-												</Dropzone>
-											</Box>
-										))}
+													<div>
+														<Text>
+															Drag an image
+															here or click.
+														</Text>
+														<Text
+															size="2"
+															color="gray"
+															as="p"
+														>
+															Photos are shown
+															once they have
+															been reviewed.
+														</Text>
+													</div>
+												</Group>
+											</Dropzone>
+										</Box>
+									)}
 								</Flex>
 							</Card>
 							<Card>
@@ -474,12 +498,14 @@ export default function Details({ typestring }: DetailsProps) {
 				(typestring === 'event' ? (
 					<EditEventModal
 						pk={data?.Pk ?? 0}
+						version={data?.Version ?? 0}
 						onClose={() => setShouldShowEditModal(false)}
 						onSuccess={() => setWasUpdateSuccessful(true)}
 					/>
 				) : (
 					<EditVenueModal
 						pk={data?.Pk ?? 0}
+						version={data?.Version ?? 0}
 						onClose={() => setShouldShowEditModal(false)}
 						onSuccess={() => setWasUpdateSuccessful(true)}
 					/>
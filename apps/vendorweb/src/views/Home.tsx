@@ -1,5 +1,5 @@
 import { getAuthToken } from '@dynamic-labs/sdk-react-core';
-import { Venue, Event } from '@platform/types';
+import { Venue, Event, PaginatedResponse } from '@platform/types';
 import {
 	Tabs,
 	Box,
@@ -33,6 +33,12 @@ export default function Home() {
 	const [eventsHistoryPage, setEventsHistoryPage] = useState<number>(1);
 	const [filter, setFilter] = useState<string>('');
 	const tempFilter = useRef<string>('');
+	// cursors.current[tab][n] is the cursor used to fetch page n of that tab
+	const cursors = useRef<Record<string, string[]>>({
+		venues: ['', ''],
+		events: ['', ''],
+		historical_events: ['', '']
+	});
 
 	const [activeTab, setActiveTab] = useSessionStorage(
 		'VendorActiveHomeTab',
@@ -107,14 +113,17 @@ export default function Home() {
 		const authToken = getAuthToken();
 		console.log(_key, page);
 		return await fetch(
-			`${process.env.NX_PUBLIC_API_BASEURL}/vendor/venues?Page=${page}&Filter=${filter}`,
+			`${process.env.NX_PUBLIC_API_BASEURL}/vendor/venues?Cursor=${cursors.current.venues[page] ?? ''}&Filter=${filter}`,
 			{
 				method: 'GET',
 				headers: { Authorization: `Bearer ${authToken}` }
 			}
 		)
 			.then((resp) => resp.json())
-			.then((data) => data)
+			.then((data: PaginatedResponse<Venue>) => {
+				cursors.current.venues[page + 1] = data.next_cursor;
+				return data.items;
+			})
 			.catch((error) => error);
 	}
 
@@ -127,14 +136,17 @@ export default function Home() {
 		const authToken = getAuthToken();
 		console.log(_key, page);
 		return await fetch(
-			`${process.env.NX_PUBLIC_API_BASEURL}/vendor/events?Page=${page}&EventDatetime=${new Date().toISOString()}&Filter=${filter}`,
+			`${process.env.NX_PUBLIC_API_BASEURL}/vendor/events?Cursor=${cursors.current.events[page] ?? ''}&EventDatetime=${new Date().toISOString()}&Filter=${filter}`,
 			{
 				method: 'GET',
 				headers: { Authorization: `Bearer ${authToken}` }
 			}
 		)
 			.then((resp) => resp.json())
-			.then((data) => data)
+			.then((data: PaginatedResponse<Event>) => {
+				cursors.current.events[page + 1] = data.next_cursor;
+				return data.items;
+			})
 			.catch((error) => error);
 	}
 
@@ -147,41 +159,54 @@ export default function Home() {
 		const authToken = getAuthToken();
 		console.log(_key, page);
 		return await fetch(
-			`${process.env.NX_PUBLIC_API_BASEURL}/vendor/events?Page=${page}&Filter=${filter}`,
+			`${process.env.NX_PUBLIC_API_BASEURL}/vendor/events?Cursor=${cursors.current.historical_events[page] ?? ''}&Filter=${filter}`,
 			{
 				method: 'GET',
 				headers: { Authorization: `Bearer ${authToken}` }
 			}
 		)
 			.then((resp) => resp.json())
-			.then((data) => data)
+			.then((data: PaginatedResponse<Event>) => {
+				cursors.current.historical_events[page + 1] =
+					data.next_cursor;
+				return data.items;
+			})
 			.catch((error) => error);
 	}
 
 	function updatePage(add: boolean) {
+		const hasNext = (page: number) =>
+			Boolean(cursors.current[activeTab]?.[page + 1]);
 		switch (activeTab) {
 			case 'events':
-				if (add) setEventsPage(eventsPage + 1);
-				else
+				if (add) {
+					if (hasNext(eventsPage)) setEventsPage(eventsPage + 1);
+				} else {
 					setEventsPage(
 						eventsPage !== 1 ? eventsPage - 1 : eventsPage
 					);
+				}
 				break;
 			case 'historical_events':
-				if (add) setEventsHistoryPage(eventsHistoryPage + 1);
-				else
+				if (add) {
+					if (hasNext(eventsHistoryPage))
+						setEventsHistoryPage(eventsHistoryPage + 1);
+				} else {
 					setEventsHistoryPage(
 						eventsHistoryPage !== 1
 							? eventsHistoryPage - 1
 							: eventsHistoryPage
 					);
+				}
 				break;
 			case 'venues':
-				if (add) setVenuesPage(venuesPage + 1);
-				else
+				if (add) {
+					if (hasNext(venuesPage)) setVenuesPage(venuesPage + 1);
+				} else {
 					setVenuesPage(
 						venuesPage !== 1 ? venuesPage - 1 : venuesPage
 					);
+				}
 				break;
 			default:
 				console.error('That is not an available tab.');
@@ -215,6 +240,18 @@ export default function Home() {
 		}
 	}, [wasAddSuccessful]);
 
+	// Cursors are only valid for the filter they were issued with
+	useEffect(() => {
+		cursors.current = {
+			venues: ['', ''],
+			events: ['', ''],
+			historical_events: ['', '']
+		};
+		setVenuesPage(1);
+		setEventsPage(1);
+		setEventsHistoryPage(1);
+	}, [filter]);
+
 	// Doing this to handle ReactQuery requerying on state change but wanting to save filter value across page loads without requerying every time a key is pressed.
 	useEffect(() => {
 		tempFilter.current = filter;
@@ -8,9 +8,13 @@ export const dbSecretArn =
 export const jwksURL =
 	'https://app.dynamic.xyz/api/v0/sdk/e332e4a7-4ed1-41ed-8ae9-7d7c462bf453/.well-known/jwks';
 export const photoBucket = 'dev-openticket-images';
+export const exportBucket = 'dev-openticket-exports';
 export const photoUploadTopicArn =
 	'arn:aws:sns:us-east-1:390403894969:S3ImageUploaded';
 export const ticketsMintedTopicArn =
 	'arn:aws:sns:us-east-1:390403894969:BlockchainTicketsMinted';
 export const oklinkSecretArn =
 	'arn:aws:secretsmanager:us-east-1:390403894969:secret:OKLink/APIKey-kYcvaB';
+export const smtpHost = 'email-smtp.us-east-1.amazonaws.com';
+export const smtpFrom = 'notifications@opentix.co';
+export const smtpSecretName = 'SES/SMTPCredentials';
@@ -30,6 +30,7 @@ import {
 	dbInternalName,
 	dbSecretArn,
 	jwksURL,
+	exportBucket,
 	photoBucket,
 	ticketsMintedTopicArn,
 	oklinkSecretArn
@@ -158,6 +159,29 @@ export class APIStack extends cdk.Stack {
 			)
 		);
 
+		// Only signs download links, the export job writes the exports
+		const ExportBucketRole = new Role(this, 'ExportBucketRole', {
+			assumedBy: new ServicePrincipal('lambda.amazonaws.com')
+		});
+		ExportBucketRole.addToPolicy(
+			new PolicyStatement({
+				effect: Effect.ALLOW,
+				actions: [
+					's3:GetObject',
+					'logs:CreateLogGroup',
+					'logs:CreateLogStream',
+					'logs:PutLogEvents'
+				],
+				resources: [`arn:aws:s3:::${exportBucket}/*`]
+			})
+		);
+		dbSecret.grantRead(ExportBucketRole);
+		ExportBucketRole.addManagedPolicy(
+			ManagedPolicy.fromAwsManagedPolicyName(
+				'service-role/AWSLambdaVPCAccessExecutionRole'
+			)
+		);
+
 		const TicketsMintedTopicRole = new Role(
 			this,
 			'TicketsMintedTopicRole',
@@ -222,14 +246,24 @@ export class APIStack extends cdk.Stack {
 			role: LambdaLogRole
 		});
 
+		const OpenAPILambda = new GoFunction(this, 'OpenAPILambda', {
+			entry: `${basePath}/openapi.go`,
+			role: LambdaLogRole
+		});
+
 		const UserEventsLambda = new GoFunction(this, 'UserEventsLambda', {
 			entry: `${basePath}/user_events.go`,
 			...LambdaDBAccessProps
 		});
 
-		const UserZipsLambda = new GoFunction(this, 'UserZipsLambda', {
-			entry: `${basePath}/user_zips.go`
-		});
+		const UserNotificationsLambda = new GoFunction(
+			this,
+			'UserNotificationsLambda',
+			{
+				entry: `${basePath}/user_notifications.go`,
+				...LambdaDBAccessProps
+			}
+		);
 
 		const VendorVenuesLambda = new GoFunction(this, 'VendorVenuesLambda', {
 			entry: `${basePath}/vendor_venues.go`,
@@ -241,6 +275,13 @@ export class APIStack extends cdk.Stack {
 			...LambdaDBAccessProps
 		});
 
+		const VendorImportLambda = new GoFunction(this, 'VendorImportLambda', {
+			entry: `${basePath}/vendor_import.go`,
+			// As long as API Gateway waits, imports check hundreds of rows
+			timeout: cdk.Duration.seconds(29),
+			...LambdaDBAccessProps
+		});
+
 		const VendorTicketsLambda = new GoFunction(
 			this,
 			'VendorTicketsLambda',
@@ -268,6 +309,15 @@ export class APIStack extends cdk.Stack {
 			}
 		);
 
+		const VendorAnalyticsLambda = new GoFunction(
+			this,
+			'VendorAnalyticsLambda',
+			{
+				entry: `${basePath}/vendor_analytics.go`,
+				...LambdaDBAccessProps
+			}
+		);
+
 		const VendorPhotosLambda = new GoFunction(this, 'VendorPhotosLambda', {
 			entry: `${basePath}/vendor_photos.go`,
 			role: PhotoBucketRole,
@@ -282,6 +332,30 @@ export class APIStack extends cdk.Stack {
 			}
 		});
 
+		// Signs download links of exports written to the export bucket
+		const VendorTicketExportsLambda = new GoFunction(
+			this,
+			'VendorTicketExportsLambda',
+			{
+				entry: `${basePath}/vendor_ticket_exports.go`,
+				role: ExportBucketRole,
+				vpc: vpc,
+				securityGroups: [dbSecurityGroup],
+				environment: {
+					DB_ADDRESS: dbAddress,
+					DB_PORT: dbPort,
+					DB_NAME: dbInternalName,
+					DB_SECRET_ARN: dbSecretArn,
+					EXPORT_BUCKET: exportBucket
+				}
+			}
+		);
+
+		const AdminPhotosLambda = new GoFunction(this, 'AdminPhotosLambda', {
+			entry: `${basePath}/admin_photos.go`,
+			...LambdaDBAccessProps
+		});
+
 		const OKLinkLambda = new GoFunction(this, 'OKLinkLambda', {
 			entry: `${basePath}/oklink.go`,
 			role: LambdaOKLinkAccessRole,
@@ -350,143 +424,485 @@ export class APIStack extends cdk.Stack {
 		});
 		addDynamicOptions(testDbResource);
 
-		const oklinkResource = api.root.addResource('oklink');
-		oklinkResource.addMethod('GET', new LambdaIntegration(OKLinkLambda), {
-			authorizer: auth
-		});
-		addDynamicOptions(oklinkResource);
+		const openapiResource = api.root.addResource('openapi');
+		openapiResource.addMethod('GET', new LambdaIntegration(OpenAPILambda));
+		addDynamicOptions(openapiResource);
 
-		const vendorResource = api.root.addResource('vendor');
-		const vendorIdResource = vendorResource.addResource('id');
-		vendorIdResource.addMethod(
-			'ANY',
-			new LambdaIntegration(VendorIDLambda),
-			{
+		// Every route is served both unprefixed and under /v1, the lambdas pick the
+		// response version from the prefix or the API-Version header
+		const addRoutes = (root: cdk.aws_apigateway.IResource) => {
+			const oklinkResource = root.addResource('oklink');
+			oklinkResource.addMethod('GET', new LambdaIntegration(OKLinkLambda), {
 				authorizer: auth
-			}
-		);
-		addDynamicOptions(vendorIdResource);
+			});
+			addDynamicOptions(oklinkResource);
+
+			const vendorResource = root.addResource('vendor');
+			const vendorIdResource = vendorResource.addResource('id');
+			vendorIdResource.addMethod(
+				'ANY',
+				new LambdaIntegration(VendorIDLambda),
+				{
+					authorizer: auth
+				}
+			);
+			addDynamicOptions(vendorIdResource);
 
-		const vendorVenuesResource = vendorResource.addResource('venues');
-		vendorVenuesResource.addMethod(
-			'GET',
-			new LambdaIntegration(VendorVenuesLambda),
-			{
-				authorizer: auth
-			}
-		);
-		vendorVenuesResource.addMethod(
-			'POST',
-			new LambdaIntegration(VendorVenuesLambda),
-			{
-				authorizer: auth
-			}
-		);
-		vendorVenuesResource.addMethod(
-			'PATCH',
-			new LambdaIntegration(VendorVenuesLambda),
-			{
-				authorizer: auth
-			}
-		);
-		addDynamicOptions(vendorVenuesResource);
+			const vendorIdHistoryResource =
+				vendorIdResource.addResource('history');
+			vendorIdHistoryResource.addMethod(
+				'GET',
+				new LambdaIntegration(VendorIDLambda),
+				{
+					authorizer: auth
+				}
+			);
+			addDynamicOptions(vendorIdHistoryResource);
 
-		const vendorVenuesPhotosResource =
-			vendorVenuesResource.addResource('photos');
-		vendorVenuesPhotosResource.addMethod(
-			'POST',
-			new LambdaIntegration(VendorPhotosLambda),
-			{
-				authorizer: auth
-			}
-		);
-		vendorVenuesPhotosResource.addMethod(
-			'DELETE',
-			new LambdaIntegration(VendorPhotosLambda),
-			{
-				authorizer: auth
-			}
-		);
-		addDynamicOptions(vendorVenuesPhotosResource);
+			const vendorVenuesResource = vendorResource.addResource('venues');
+			vendorVenuesResource.addMethod(
+				'GET',
+				new LambdaIntegration(VendorVenuesLambda),
+				{
+					authorizer: auth
+				}
+			);
+			vendorVenuesResource.addMethod(
+				'POST',
+				new LambdaIntegration(VendorVenuesLambda),
+				{
+					authorizer: auth
+				}
+			);
+			vendorVenuesResource.addMethod(
+				'PATCH',
+				new LambdaIntegration(VendorVenuesLambda),
+				{
+					authorizer: auth
+				}
+			);
+			addDynamicOptions(vendorVenuesResource);
 
-		const vendorEventsResource = vendorResource.addResource('events');
-		vendorEventsResource.addMethod(
-			'GET',
-			new LambdaIntegration(VendorEventsLambda),
-			{
-				authorizer: auth
-			}
-		);
-		vendorEventsResource.addMethod(
-			'POST',
-			new LambdaIntegration(VendorEventsLambda),
-			{
-				authorizer: auth
-			}
-		);
-		vendorEventsResource.addMethod(
-			'PATCH',
-			new LambdaIntegration(VendorEventsLambda),
-			{
-				authorizer: auth
-			}
-		);
-		addDynamicOptions(vendorEventsResource);
+			const vendorVenuesAllResource =
+				vendorVenuesResource.addResource('all');
+			vendorVenuesAllResource.addMethod(
+				'GET',
+				new LambdaIntegration(VendorVenuesLambda),
+				{
+					authorizer: auth
+				}
+			);
+			addDynamicOptions(vendorVenuesAllResource);
 
-		const vendorEventsPhotosResource =
-			vendorEventsResource.addResource('photos');
-		vendorEventsPhotosResource.addMethod(
-			'POST',
-			new LambdaIntegration(VendorPhotosLambda),
-			{
-				authorizer: auth
-			}
-		);
-		vendorEventsPhotosResource.addMethod(
-			'DELETE',
-			new LambdaIntegration(VendorPhotosLambda),
-			{
-				authorizer: auth
-			}
-		);
-		addDynamicOptions(vendorEventsPhotosResource);
+			const vendorVenuesImportResource =
+				vendorVenuesResource.addResource('import');
+			vendorVenuesImportResource.addMethod(
+				'POST',
+				new LambdaIntegration(VendorImportLambda),
+				{
+					authorizer: auth
+				}
+			);
+			addDynamicOptions(vendorVenuesImportResource);
 
-		const vendorEventsTicketsResource =
-			vendorEventsResource.addResource('tickets');
-		vendorEventsTicketsResource.addMethod(
-			'PATCH',
-			new LambdaIntegration(VendorTicketsLambda),
-			{
-				authorizer: auth
-			}
-		);
-		addDynamicOptions(vendorEventsTicketsResource);
+			const vendorVenuesIdResource =
+				vendorVenuesResource.addResource('{id}');
+			vendorVenuesIdResource.addMethod(
+				'GET',
+				new LambdaIntegration(VendorVenuesLambda),
+				{
+					authorizer: auth
+				}
+			);
+			addDynamicOptions(vendorVenuesIdResource);
 
-		const vendorEventsTicketsCreationResource =
-			vendorEventsTicketsResource.addResource('create');
-		vendorEventsTicketsCreationResource.addMethod(
-			'POST',
-			new LambdaIntegration(VendorTicketsCreationLambda),
-			{
-				authorizer: auth
-			}
-		);
-		addDynamicOptions(vendorEventsTicketsCreationResource);
+			const vendorVenuesIdHistoryResource =
+				vendorVenuesIdResource.addResource('history');
+			vendorVenuesIdHistoryResource.addMethod(
+				'GET',
+				new LambdaIntegration(VendorVenuesLambda),
+				{
+					authorizer: auth
+				}
+			);
+			addDynamicOptions(vendorVenuesIdHistoryResource);
 
-		const userResource = api.root.addResource('user');
-		const userEventsResource = userResource.addResource('events');
-		userEventsResource.addMethod(
-			'GET',
-			new LambdaIntegration(UserEventsLambda)
-		);
-		addDynamicOptions(userEventsResource);
+			const vendorVenuesIdCalendarResource =
+				vendorVenuesIdResource.addResource('calendar');
+			vendorVenuesIdCalendarResource.addMethod(
+				'GET',
+				new LambdaIntegration(VendorVenuesLambda),
+				{
+					authorizer: auth
+				}
+			);
+			addDynamicOptions(vendorVenuesIdCalendarResource);
 
-		const userZipsResource = userResource.addResource('zips');
-		userZipsResource.addMethod(
-			'GET',
-			new LambdaIntegration(UserZipsLambda)
-		);
-		addDynamicOptions(userZipsResource);
+			const vendorVenuesIdCapacityResource =
+				vendorVenuesIdResource.addResource('capacity');
+			vendorVenuesIdCapacityResource.addMethod(
+				'PATCH',
+				new LambdaIntegration(VendorVenuesLambda),
+				{
+					authorizer: auth
+				}
+			);
+			addDynamicOptions(vendorVenuesIdCapacityResource);
+
+			const vendorVenuesPhotosResource =
+				vendorVenuesResource.addResource('photos');
+			vendorVenuesPhotosResource.addMethod(
+				'POST',
+				new LambdaIntegration(VendorPhotosLambda),
+				{
+					authorizer: auth
+				}
+			);
+			vendorVenuesPhotosResource.addMethod(
+				'DELETE',
+				new LambdaIntegration(VendorPhotosLambda),
+				{
+					authorizer: auth
+				}
+			);
+			addDynamicOptions(vendorVenuesPhotosResource);
+
+			const vendorVenuesIdPhotosResource =
+				vendorVenuesIdResource.addResource('photos');
+			vendorVenuesIdPhotosResource.addMethod(
+				'GET',
+				new LambdaIntegration(VendorPhotosLambda),
+				{
+					authorizer: auth
+				}
+			);
+			addDynamicOptions(vendorVenuesIdPhotosResource);
+
+			const vendorVenuesIdPhotosOrderResource =
+				vendorVenuesIdPhotosResource.addResource('order');
+			vendorVenuesIdPhotosOrderResource.addMethod(
+				'PUT',
+				new LambdaIntegration(VendorPhotosLambda),
+				{
+					authorizer: auth
+				}
+			);
+			addDynamicOptions(vendorVenuesIdPhotosOrderResource);
+
+			const vendorVenuesIdPhotosCoverResource =
+				vendorVenuesIdPhotosResource.addResource('cover');
+			vendorVenuesIdPhotosCoverResource.addMethod(
+				'PUT',
+				new LambdaIntegration(VendorPhotosLambda),
+				{
+					authorizer: auth
+				}
+			);
+			addDynamicOptions(vendorVenuesIdPhotosCoverResource);
+
+			const vendorVenuesIdPhotosIdResource =
+				vendorVenuesIdPhotosResource.addResource('{photoId}');
+			vendorVenuesIdPhotosIdResource.addMethod(
+				'PATCH',
+				new LambdaIntegration(VendorPhotosLambda),
+				{
+					authorizer: auth
+				}
+			);
+			vendorVenuesIdPhotosIdResource.addMethod(
+				'DELETE',
+				new LambdaIntegration(VendorPhotosLambda),
+				{
+					authorizer: auth
+				}
+			);
+			addDynamicOptions(vendorVenuesIdPhotosIdResource);
+
+			const vendorEventsResource = vendorResource.addResource('events');
+			vendorEventsResource.addMethod(
+				'GET',
+				new LambdaIntegration(VendorEventsLambda),
+				{
+					authorizer: auth
+				}
+			);
+			vendorEventsResource.addMethod(
+				'POST',
+				new LambdaIntegration(VendorEventsLambda),
+				{
+					authorizer: auth
+				}
+			);
+			vendorEventsResource.addMethod(
+				'PATCH',
+				new LambdaIntegration(VendorEventsLambda),
+				{
+					authorizer: auth
+				}
+			);
+			addDynamicOptions(vendorEventsResource);
+
+			const vendorEventsImportResource =
+				vendorEventsResource.addResource('import');
+			vendorEventsImportResource.addMethod(
+				'POST',
+				new LambdaIntegration(VendorImportLambda),
+				{
+					authorizer: auth
+				}
+			);
+			addDynamicOptions(vendorEventsImportResource);
+
+			const vendorEventsIdResource =
+				vendorEventsResource.addResource('{id}');
+			vendorEventsIdResource.addMethod(
+				'GET',
+				new LambdaIntegration(VendorEventsLambda),
+				{
+					authorizer: auth
+				}
+			);
+			addDynamicOptions(vendorEventsIdResource);
+
+			const vendorEventsIdHistoryResource =
+				vendorEventsIdResource.addResource('history');
+			vendorEventsIdHistoryResource.addMethod(
+				'GET',
+				new LambdaIntegration(VendorEventsLambda),
+				{
+					authorizer: auth
+				}
+			);
+			addDynamicOptions(vendorEventsIdHistoryResource);
+
+			const vendorEventsIdCancelResource =
+				vendorEventsIdResource.addResource('cancel');
+			vendorEventsIdCancelResource.addMethod(
+				'POST',
+				new LambdaIntegration(VendorEventsLambda),
+				{
+					authorizer: auth
+				}
+			);
+			addDynamicOptions(vendorEventsIdCancelResource);
+
+			const vendorEventsIdCapacityResource =
+				vendorEventsIdResource.addResource('capacity');
+			vendorEventsIdCapacityResource.addMethod(
+				'PATCH',
+				new LambdaIntegration(VendorTicketsCreationLambda),
+				{
+					authorizer: auth
+				}
+			);
+			addDynamicOptions(vendorEventsIdCapacityResource);
+
+			const vendorEventsIdTicketsExportResource = vendorEventsIdResource
+				.addResource('tickets')
+				.addResource('export');
+			vendorEventsIdTicketsExportResource.addMethod(
+				'GET',
+				new LambdaIntegration(VendorTicketExportsLambda),
+				{
+					authorizer: auth
+				}
+			);
+			addDynamicOptions(vendorEventsIdTicketsExportResource);
+
+			const vendorExportsIdResource = vendorResource
+				.addResource('exports')
+				.addResource('{id}');
+			vendorExportsIdResource.addMethod(
+				'GET',
+				new LambdaIntegration(VendorTicketExportsLambda),
+				{
+					authorizer: auth
+				}
+			);
+			addDynamicOptions(vendorExportsIdResource);
+
+			const vendorEventsPhotosResource =
+				vendorEventsResource.addResource('photos');
+			vendorEventsPhotosResource.addMethod(
+				'POST',
+				new LambdaIntegration(VendorPhotosLambda),
+				{
+					authorizer: auth
+				}
+			);
+			vendorEventsPhotosResource.addMethod(
+				'DELETE',
+				new LambdaIntegration(VendorPhotosLambda),
+				{
+					authorizer: auth
+				}
+			);
+			addDynamicOptions(vendorEventsPhotosResource);
+
+			const vendorEventsIdPhotosResource =
+				vendorEventsIdResource.addResource('photos');
+			vendorEventsIdPhotosResource.addMethod(
+				'GET',
+				new LambdaIntegration(VendorPhotosLambda),
+				{
+					authorizer: auth
+				}
+			);
+			addDynamicOptions(vendorEventsIdPhotosResource);
+
+			const vendorEventsIdPhotosOrderResource =
+				vendorEventsIdPhotosResource.addResource('order');
+			vendorEventsIdPhotosOrderResource.addMethod(
+				'PUT',
+				new LambdaIntegration(VendorPhotosLambda),
+				{
+					authorizer: auth
+				}
+			);
+			addDynamicOptions(vendorEventsIdPhotosOrderResource);
+
+			const vendorEventsIdPhotosCoverResource =
+				vendorEventsIdPhotosResource.addResource('cover');
+			vendorEventsIdPhotosCoverResource.addMethod(
+				'PUT',
+				new LambdaIntegration(VendorPhotosLambda),
+				{
+					authorizer: auth
+				}
+			);
+			addDynamicOptions(vendorEventsIdPhotosCoverResource);
+
+			const vendorEventsIdPhotosIdResource =
+				vendorEventsIdPhotosResource.addResource('{photoId}');
+			vendorEventsIdPhotosIdResource.addMethod(
+				'PATCH',
+				new LambdaIntegration(VendorPhotosLambda),
+				{
+					authorizer: auth
+				}
+			);
+			vendorEventsIdPhotosIdResource.addMethod(
+				'DELETE',
+				new LambdaIntegration(VendorPhotosLambda),
+				{
+					authorizer: auth
+				}
+			);
+			addDynamicOptions(vendorEventsIdPhotosIdResource);
+
+			const vendorEventsTicketsResource =
+				vendorEventsResource.addResource('tickets');
+			vendorEventsTicketsResource.addMethod(
+				'PATCH',
+				new LambdaIntegration(VendorTicketsLambda),
+				{
+					authorizer: auth
+				}
+			);
+			addDynamicOptions(vendorEventsTicketsResource);
+
+			const vendorEventsTicketsCreationResource =
+				vendorEventsTicketsResource.addResource('create');
+			vendorEventsTicketsCreationResource.addMethod(
+				'POST',
+				new LambdaIntegration(VendorTicketsCreationLambda),
+				{
+					authorizer: auth
+				}
+			);
+			addDynamicOptions(vendorEventsTicketsCreationResource);
+
+			const vendorAnalyticsResource =
+				vendorResource.addResource('analytics');
+			vendorAnalyticsResource.addMethod(
+				'GET',
+				new LambdaIntegration(VendorAnalyticsLambda),
+				{
+					authorizer: auth
+				}
+			);
+			addDynamicOptions(vendorAnalyticsResource);
+
+			const adminResource = root.addResource('admin');
+			const adminPhotosResource = adminResource.addResource('photos');
+			adminPhotosResource.addMethod(
+				'GET',
+				new LambdaIntegration(AdminPhotosLambda),
+				{
+					authorizer: auth
+				}
+			);
+			addDynamicOptions(adminPhotosResource);
+
+			const adminPhotosReviewResource = adminPhotosResource
+				.addResource('{id}')
+				.addResource('review');
+			adminPhotosReviewResource.addMethod(
+				'PUT',
+				new LambdaIntegration(AdminPhotosLambda),
+				{
+					authorizer: auth
+				}
+			);
+			addDynamicOptions(adminPhotosReviewResource);
+
+			const userResource = root.addResource('user');
+			const userEventsResource = userResource.addResource('events');
+			userEventsResource.addMethod(
+				'GET',
+				new LambdaIntegration(UserEventsLambda)
+			);
+			addDynamicOptions(userEventsResource);
+
+			const userEventsIdResource =
+				userEventsResource.addResource('{id}');
+			userEventsIdResource.addMethod(
+				'GET',
+				new LambdaIntegration(UserEventsLambda)
+			);
+			addDynamicOptions(userEventsIdResource);
+
+			const userNotificationsResource =
+				userResource.addResource('notifications');
+			const userNotificationsPreferencesResource =
+				userNotificationsResource.addResource('preferences');
+			userNotificationsPreferencesResource.addMethod(
+				'GET',
+				new LambdaIntegration(UserNotificationsLambda),
+				{
+					authorizer: auth
+				}
+			);
+			userNotificationsPreferencesResource.addMethod(
+				'PUT',
+				new LambdaIntegration(UserNotificationsLambda),
+				{
+					authorizer: auth
+				}
+			);
+			addDynamicOptions(userNotificationsPreferencesResource);
+
+			const userNotificationsPushTokensResource =
+				userNotificationsResource.addResource('push-tokens');
+			userNotificationsPushTokensResource.addMethod(
+				'POST',
+				new LambdaIntegration(UserNotificationsLambda),
+				{
+					authorizer: auth
+				}
+			);
+			userNotificationsPushTokensResource.addMethod(
+				'DELETE',
+				new LambdaIntegration(UserNotificationsLambda),
+				{
+					authorizer: auth
+				}
+			);
+			addDynamicOptions(userNotificationsPushTokensResource);
+		};
+		addRoutes(api.root);
+		const v1Resource = api.root.addResource('v1');
+		addRoutes(v1Resource);
 
 		new cdk.CfnOutput(this, 'ApiUrl', {
 			value: api.url
@@ -19,8 +19,12 @@ import {
 	dbPort,
 	dbInternalName,
 	dbSecretArn,
+	exportBucket,
 	photoBucket,
 	photoUploadTopicArn,
+	smtpFrom,
+	smtpHost,
+	smtpSecretName,
 	ticketsMintedTopicArn
 } from './Constants';
 
@@ -68,6 +72,14 @@ export class EventHandlerStack extends cdk.Stack {
 				resources: [`arn:aws:s3:::${photoBucket}/*`]
 			})
 		);
+		// Reconciling the bucket lists it
+		PhotoBucketRole.addToPolicy(
+			new PolicyStatement({
+				effect: Effect.ALLOW,
+				actions: ['s3:ListBucket'],
+				resources: [`arn:aws:s3:::${photoBucket}`]
+			})
+		);
 		dbSecret.grantRead(PhotoBucketRole);
 		PhotoBucketRole.addManagedPolicy(
 			ManagedPolicy.fromAwsManagedPolicyName(
@@ -81,12 +93,25 @@ export class EventHandlerStack extends cdk.Stack {
 			'PhotoUploadTopic',
 			photoUploadTopicArn
 		);
+		// Uploads that keep failing to attach end up here instead of being dropped
+		const photoUploadDeadLetterQueue = new cdk.aws_sqs.Queue(
+			this,
+			'PhotoUploadDeadLetterQueue',
+			{
+				retentionPeriod: cdk.Duration.days(14)
+			}
+		);
+
 		//create sqs queue for topic
 		const photoUploadQueue = new cdk.aws_sqs.Queue(
 			this,
 			'PhotoUploadQueue',
 			{
-				visibilityTimeout: cdk.Duration.seconds(300)
+				visibilityTimeout: cdk.Duration.seconds(300),
+				deadLetterQueue: {
+					queue: photoUploadDeadLetterQueue,
+					maxReceiveCount: 5
+				}
 			}
 		);
 
@@ -102,6 +127,10 @@ export class EventHandlerStack extends cdk.Stack {
 			{
 				entry: `${basePath}/PhotoUploadEvent.go`,
 				role: PhotoBucketRole,
+				// Decoding and resizing full size uploads needs the memory, and
+				// the CPU that comes with it
+				memorySize: 2048,
+				timeout: cdk.Duration.seconds(60),
 				vpc: vpc,
 				securityGroups: [dbSecurityGroup],
 				environment: {
@@ -119,9 +148,162 @@ export class EventHandlerStack extends cdk.Stack {
 
 		//create event source mapping
 		PhotoUploadEventLambda.addEventSource(
-			new cdk.aws_lambda_event_sources.SqsEventSource(photoUploadQueue)
+			new cdk.aws_lambda_event_sources.SqsEventSource(photoUploadQueue, {
+				reportBatchItemFailures: true
+			})
 		);
 
+		// Deletes photos removed from events, venues and galleries from the
+		// bucket, and once a day anything in it that nothing references
+		const PhotoCleanupEventLambda = new GoFunction(
+			this,
+			'PhotoCleanupEventLambda',
+			{
+				entry: `${basePath}/PhotoCleanupEvent.go`,
+				role: PhotoBucketRole,
+				timeout: cdk.Duration.minutes(10),
+				vpc: vpc,
+				securityGroups: [dbSecurityGroup],
+				environment: {
+					DB_ADDRESS: dbAddress,
+					DB_PORT: dbPort,
+					DB_NAME: dbInternalName,
+					DB_SECRET_ARN: dbSecretArn,
+					PHOTO_BUCKET: photoBucket
+				}
+			}
+		);
+		new cdk.aws_events.Rule(this, 'PhotoCleanupRule', {
+			schedule: cdk.aws_events.Schedule.rate(cdk.Duration.hours(1)),
+			targets: [
+				new cdk.aws_events_targets.LambdaFunction(PhotoCleanupEventLambda, {
+					event: cdk.aws_events.RuleTargetInput.fromObject({
+						Reconcile: false
+					})
+				})
+			]
+		});
+		new cdk.aws_events.Rule(this, 'PhotoReconcileRule', {
+			schedule: cdk.aws_events.Schedule.rate(cdk.Duration.days(1)),
+			targets: [
+				new cdk.aws_events_targets.LambdaFunction(PhotoCleanupEventLambda, {
+					event: cdk.aws_events.RuleTargetInput.fromObject({
+						Reconcile: true
+					})
+				})
+			]
+		});
+
+		// Ticket exports list attendees' wallets, so unlike photos they are kept
+		// in a bucket of their own that nothing can read without a signed link.
+		// The export job deletes them once they expire; the lifecycle rule only
+		// catches the files of exports whose event was deleted first.
+		new cdk.aws_s3.Bucket(this, 'ExportBucket', {
+			bucketName: exportBucket,
+			blockPublicAccess: cdk.aws_s3.BlockPublicAccess.BLOCK_ALL,
+			enforceSSL: true,
+			lifecycleRules: [{ expiration: cdk.Duration.days(8) }]
+		});
+
+		const ExportBucketRole = new Role(this, 'ExportBucketRole', {
+			assumedBy: new ServicePrincipal('lambda.amazonaws.com')
+		});
+		ExportBucketRole.addToPolicy(
+			new PolicyStatement({
+				effect: Effect.ALLOW,
+				actions: [
+					's3:PutObject',
+					's3:DeleteObject',
+					'logs:CreateLogGroup',
+					'logs:CreateLogStream',
+					'logs:PutLogEvents'
+				],
+				resources: [`arn:aws:s3:::${exportBucket}/*`]
+			})
+		);
+		dbSecret.grantRead(ExportBucketRole);
+		ExportBucketRole.addManagedPolicy(
+			ManagedPolicy.fromAwsManagedPolicyName(
+				'service-role/AWSLambdaVPCAccessExecutionRole'
+			)
+		);
+
+		// Writes the ticket exports vendors ask for in the background to the
+		// export bucket. The API queues them in the database, so this polls.
+		const TicketExportEventLambda = new GoFunction(
+			this,
+			'TicketExportEventLambda',
+			{
+				entry: `${basePath}/TicketExportEvent.go`,
+				role: ExportBucketRole,
+				memorySize: 1024,
+				timeout: cdk.Duration.minutes(5),
+				vpc: vpc,
+				securityGroups: [dbSecurityGroup],
+				environment: {
+					DB_ADDRESS: dbAddress,
+					DB_PORT: dbPort,
+					DB_NAME: dbInternalName,
+					DB_SECRET_ARN: dbSecretArn,
+					EXPORT_BUCKET: exportBucket
+				}
+			}
+		);
+		new cdk.aws_events.Rule(this, 'TicketExportRule', {
+			schedule: cdk.aws_events.Schedule.rate(cdk.Duration.minutes(1)),
+			targets: [
+				new cdk.aws_events_targets.LambdaFunction(TicketExportEventLambda)
+			]
+		});
+
+		// Sends the notifications the API leaves in the outbox when events change,
+		// by email through SES's SMTP endpoint and by push through Expo
+		const smtpSecret = Secret.fromSecretNameV2(
+			this,
+			'SMTPSecret',
+			smtpSecretName
+		);
+		const NotificationEventLambdaRole = new Role(
+			this,
+			'NotificationEventLambdaRole',
+			{
+				assumedBy: new ServicePrincipal('lambda.amazonaws.com')
+			}
+		);
+		dbSecret.grantRead(NotificationEventLambdaRole);
+		smtpSecret.grantRead(NotificationEventLambdaRole);
+		NotificationEventLambdaRole.addManagedPolicy(
+			ManagedPolicy.fromAwsManagedPolicyName(
+				'service-role/AWSLambdaVPCAccessExecutionRole'
+			)
+		);
+		const NotificationEventLambda = new GoFunction(
+			this,
+			'NotificationEventLambda',
+			{
+				entry: `${basePath}/NotificationEvent.go`,
+				role: NotificationEventLambdaRole,
+				timeout: cdk.Duration.minutes(5),
+				vpc: vpc,
+				securityGroups: [dbSecurityGroup],
+				environment: {
+					DB_ADDRESS: dbAddress,
+					DB_PORT: dbPort,
+					DB_NAME: dbInternalName,
+					DB_SECRET_ARN: dbSecretArn,
+					SMTP_HOST: smtpHost,
+					SMTP_FROM: smtpFrom,
+					SMTP_SECRET_ARN: smtpSecret.secretArn
+				}
+			}
+		);
+		new cdk.aws_events.Rule(this, 'NotificationRule', {
+			schedule: cdk.aws_events.Schedule.rate(cdk.Duration.minutes(1)),
+			targets: [
+				new cdk.aws_events_targets.LambdaFunction(NotificationEventLambda)
+			]
+		});
+
 		// Ticket Creation
 
 		const TicketCreationEventLambdaRole = new Role(
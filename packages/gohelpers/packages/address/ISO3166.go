@@ -0,0 +1,66 @@
+package address
+
+import (
+	_ "embed"
+	"encoding/csv"
+	"log"
+	"strings"
+	"sync"
+)
+
+//go:generate go run ./gen -out data
+
+// ISO 3166-1 alpha-2 country codes and ISO 3166-2 subdivision codes with their
+// English short names, taken from the Debian iso-codes project.
+//
+//go:embed data/iso3166_1.csv
+var countriesCSV string
+
+//go:embed data/iso3166_2.csv
+var subdivisionsCSV string
+
+var (
+	countryNames     map[string]string
+	subdivisionNames map[string]string
+	iso3166Once      sync.Once
+)
+
+func parseCodeNameCSV(data string) map[string]string {
+	names := make(map[string]string)
+
+	reader := csv.NewReader(strings.NewReader(data))
+	records, err := reader.ReadAll()
+	if err != nil || len(records) == 0 {
+		log.Printf("Error parsing ISO 3166 dataset: %v", err)
+		return names
+	}
+
+	// Skip the header row
+	for _, record := range records[1:] {
+		if len(record) != 2 {
+			continue
+		}
+		names[record[0]] = record[1]
+	}
+	return names
+}
+
+func loadISO3166() {
+	countryNames = parseCodeNameCSV(countriesCSV)
+	subdivisionNames = parseCodeNameCSV(subdivisionsCSV)
+}
+
+// CountryName returns the ISO 3166-1 short name for an alpha-2 country code.
+func CountryName(countryCode string) (string, bool) {
+	iso3166Once.Do(loadISO3166)
+	name, ok := countryNames[strings.ToUpper(countryCode)]
+	return name, ok
+}
+
+// SubdivisionName returns the ISO 3166-2 name for a subdivision code such as
+// "US-CA".
+func SubdivisionName(subdivisionCode string) (string, bool) {
+	iso3166Once.Do(loadISO3166)
+	name, ok := subdivisionNames[strings.ToUpper(subdivisionCode)]
+	return name, ok
+}
@@ -0,0 +1,81 @@
+package address
+
+import (
+	"strings"
+)
+
+// Postal address as stored on a venue. The state and country names are always
+// derived from their codes, whatever the caller passed in.
+type Address struct {
+	StreetAddress string
+	Zip           string
+	City          string
+	StateCode     string
+	StateName     string
+	CountryCode   string
+	CountryName   string
+}
+
+// A problem with one field of an address. Field uses the same names as the API
+// request bodies so clients can point at the offending input.
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// CollapseWhitespace trims s and replaces every run of whitespace inside it with
+// a single space.
+func CollapseWhitespace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// Normalize cleans up an address and checks it against the ISO 3166 tables and
+// the country's postal code format. The returned address is only meaningful
+// when no errors are returned.
+func Normalize(addr Address) (Address, []FieldError) {
+	var errs []FieldError
+
+	addr.StreetAddress = CollapseWhitespace(addr.StreetAddress)
+	if addr.StreetAddress == "" {
+		errs = append(errs, FieldError{"StreetAddress", "required", "Street address is required"})
+	}
+
+	addr.City = CollapseWhitespace(addr.City)
+	if addr.City == "" {
+		errs = append(errs, FieldError{"City", "required", "City is required"})
+	}
+
+	addr.CountryCode = strings.ToUpper(strings.TrimSpace(addr.CountryCode))
+	countryName, countryOk := CountryName(addr.CountryCode)
+	if addr.CountryCode == "" {
+		errs = append(errs, FieldError{"CountryCode", "required", "Country code is required"})
+	} else if !countryOk {
+		errs = append(errs, FieldError{"CountryCode", "unknown", "Country code is not an ISO 3166-1 alpha-2 code"})
+	} else {
+		addr.CountryName = countryName
+	}
+
+	addr.StateCode = strings.ToUpper(strings.TrimSpace(addr.StateCode))
+	stateName, stateOk := SubdivisionName(addr.StateCode)
+	if addr.StateCode == "" {
+		errs = append(errs, FieldError{"StateCode", "required", "State code is required"})
+	} else if !stateOk {
+		errs = append(errs, FieldError{"StateCode", "unknown", "State code is not an ISO 3166-2 subdivision code"})
+	} else if countryOk && !strings.HasPrefix(addr.StateCode, addr.CountryCode+"-") {
+		errs = append(errs, FieldError{"StateCode", "mismatch", "State code does not belong to the given country"})
+	} else {
+		addr.StateName = stateName
+	}
+
+	// The postal code format depends on the country, so only check it once we
+	// know which country we're in
+	if countryOk {
+		addr.Zip = NormalizePostalCode(addr.CountryCode, addr.Zip)
+		if !ValidPostalCode(addr.CountryCode, addr.Zip) {
+			errs = append(errs, FieldError{"Zip", "invalid", "Postal code is not valid for " + countryName})
+		}
+	}
+
+	return addr, errs
+}
@@ -0,0 +1,73 @@
+package address
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Postal code formats for the countries we have venues in or expect to soon.
+// Countries missing from this table only get a basic sanity check.
+var postalCodePatterns = map[string]*regexp.Regexp{
+	"AT": regexp.MustCompile(`^\d{4}$`),
+	"AU": regexp.MustCompile(`^\d{4}$`),
+	"BE": regexp.MustCompile(`^\d{4}$`),
+	"BR": regexp.MustCompile(`^\d{5}-\d{3}$`),
+	"CA": regexp.MustCompile(`^[ABCEGHJ-NPRSTVXY]\d[ABCEGHJ-NPRSTV-Z] \d[ABCEGHJ-NPRSTV-Z]\d$`),
+	"CH": regexp.MustCompile(`^\d{4}$`),
+	"DE": regexp.MustCompile(`^\d{5}$`),
+	"DK": regexp.MustCompile(`^\d{4}$`),
+	"ES": regexp.MustCompile(`^\d{5}$`),
+	"FR": regexp.MustCompile(`^\d{5}$`),
+	"GB": regexp.MustCompile(`^([A-Z]{1,2}\d[A-Z\d]? \d[A-Z]{2}|GIR 0AA)$`),
+	"IE": regexp.MustCompile(`^([AC-FHKNPRTV-Y]\d{2}|D6W) [0-9AC-FHKNPRTV-Y]{4}$`),
+	"IN": regexp.MustCompile(`^\d{6}$`),
+	"IT": regexp.MustCompile(`^\d{5}$`),
+	"JP": regexp.MustCompile(`^\d{3}-\d{4}$`),
+	"MX": regexp.MustCompile(`^\d{5}$`),
+	"NL": regexp.MustCompile(`^\d{4} [A-Z]{2}$`),
+	"NO": regexp.MustCompile(`^\d{4}$`),
+	"NZ": regexp.MustCompile(`^\d{4}$`),
+	"PL": regexp.MustCompile(`^\d{2}-\d{3}$`),
+	"PR": regexp.MustCompile(`^00[679]\d{2}(-\d{4})?$`),
+	"PT": regexp.MustCompile(`^\d{4}-\d{3}$`),
+	"SE": regexp.MustCompile(`^\d{3} \d{2}$`),
+	"US": regexp.MustCompile(`^\d{5}(-\d{4})?$`),
+}
+
+// Countries that don't use postal codes, an empty zip is accepted for these.
+var noPostalCode = map[string]bool{
+	"AE": true, "AG": true, "AO": true, "BS": true, "BZ": true, "FJ": true,
+	"GH": true, "HK": true, "JM": true, "KE": true, "QA": true, "TT": true,
+}
+
+// Spaced formats where users commonly leave the space out, keyed by the
+// position the space belongs at counting from the end.
+var postalCodeSpaceFromEnd = map[string]int{
+	"CA": 3,
+	"GB": 3,
+	"IE": 4,
+	"NL": 2,
+	"SE": 2,
+}
+
+// NormalizePostalCode uppercases a postal code, collapses whitespace and puts the
+// separator space where the country's format expects it.
+func NormalizePostalCode(countryCode string, postalCode string) string {
+	postalCode = strings.ToUpper(strings.Join(strings.Fields(postalCode), ""))
+	if pos, ok := postalCodeSpaceFromEnd[countryCode]; ok && len(postalCode) > pos {
+		postalCode = postalCode[:len(postalCode)-pos] + " " + postalCode[len(postalCode)-pos:]
+	}
+	return postalCode
+}
+
+// ValidPostalCode reports whether an already normalized postal code matches the
+// format used in the given country.
+func ValidPostalCode(countryCode string, postalCode string) bool {
+	if postalCode == "" {
+		return noPostalCode[countryCode]
+	}
+	if pattern, ok := postalCodePatterns[countryCode]; ok {
+		return pattern.MatchString(postalCode)
+	}
+	return len(postalCode) <= 10
+}
@@ -0,0 +1,82 @@
+package address
+
+import "testing"
+
+func TestNormalizePostalCode(t *testing.T) {
+	tests := []struct {
+		country string
+		input   string
+		want    string
+	}{
+		{"US", "10001", "10001"},
+		{"US", " 10001-1234 ", "10001-1234"},
+		{"CA", "k1a0b1", "K1A 0B1"},
+		{"CA", "K1A  0B1", "K1A 0B1"},
+		{"GB", "sw1a1aa", "SW1A 1AA"},
+		{"GB", "M1 1AE", "M1 1AE"},
+		{"IE", "d02x285", "D02 X285"},
+		{"NL", "1012jS", "1012 JS"},
+		{"SE", "11455", "114 55"},
+		// Too short to hold the space, left for validation to refuse
+		{"GB", "1AA", "1AA"},
+		{"DE", "10 115", "10115"},
+		{"ZZ", " ab 12 ", "AB12"},
+		{"US", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.country+" "+tt.input, func(t *testing.T) {
+			if got := NormalizePostalCode(tt.country, tt.input); got != tt.want {
+				t.Errorf("NormalizePostalCode(%q, %q) = %q, want %q", tt.country, tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidPostalCode(t *testing.T) {
+	tests := []struct {
+		country string
+		code    string
+		want    bool
+	}{
+		{"US", "10001", true},
+		{"US", "10001-1234", true},
+		{"US", "1000", false},
+		{"US", "10001-12", false},
+		{"US", "", false},
+		{"PR", "00907", true},
+		{"PR", "10001", false},
+		{"CA", "K1A 0B1", true},
+		// D, F, I, O, Q and U are never used in Canadian codes
+		{"CA", "D1A 0B1", false},
+		{"CA", "K1A0B1", false},
+		{"GB", "SW1A 1AA", true},
+		{"GB", "GIR 0AA", true},
+		{"GB", "SW1A1AA", false},
+		{"IE", "D02 X285", true},
+		{"IE", "D6W 1234", true},
+		{"IE", "B02 X285", false},
+		{"NL", "1012 JS", true},
+		{"NL", "1012JS", false},
+		{"SE", "114 55", true},
+		{"JP", "100-0001", true},
+		{"JP", "1000001", false},
+		{"BR", "01310-100", true},
+		{"PL", "00-950", true},
+		{"DE", "10115", true},
+		{"DE", "1011", false},
+		// Countries without postal codes only accept an empty one
+		{"HK", "", true},
+		{"AE", "", true},
+		// Countries without a known format get a length check
+		{"ZZ", "", false},
+		{"ZZ", "ABC-123", true},
+		{"ZZ", "12345678901", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.country+" "+tt.code, func(t *testing.T) {
+			if got := ValidPostalCode(tt.country, tt.code); got != tt.want {
+				t.Errorf("ValidPostalCode(%q, %q) = %v, want %v", tt.country, tt.code, got, tt.want)
+			}
+		})
+	}
+}
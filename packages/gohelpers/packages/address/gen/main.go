@@ -0,0 +1,70 @@
+// Regenerates data/iso3166_1.csv and data/iso3166_2.csv from the Debian
+// iso-codes JSON files.
+//
+//	go generate ./packages/address
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+type isoEntry struct {
+	Alpha2 string `json:"alpha_2"`
+	Code   string `json:"code"`
+	Name   string `json:"name"`
+}
+
+func readEntries(path string, key string) []isoEntry {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		log.Fatalf("Error reading %v: %v", path, err)
+	}
+	var doc map[string][]isoEntry
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		log.Fatalf("Error parsing %v: %v", path, err)
+	}
+	return doc[key]
+}
+
+func writeRows(path string, header []string, rows [][]string) {
+	sort.Slice(rows, func(i, j int) bool { return rows[i][0] < rows[j][0] })
+
+	f, err := os.Create(path)
+	if err != nil {
+		log.Fatalf("Error creating %v: %v", path, err)
+	}
+	defer f.Close()
+
+	writer := csv.NewWriter(f)
+	writer.Write(header)
+	writer.WriteAll(rows)
+	if err := writer.Error(); err != nil {
+		log.Fatalf("Error writing %v: %v", path, err)
+	}
+	fmt.Printf("Wrote %d rows to %v\n", len(rows), path)
+}
+
+func main() {
+	source := flag.String("src", "/usr/share/iso-codes/json", "directory containing the iso-codes json files")
+	out := flag.String("out", "data", "output directory")
+	flag.Parse()
+
+	var countries [][]string
+	for _, entry := range readEntries(filepath.Join(*source, "iso_3166-1.json"), "3166-1") {
+		countries = append(countries, []string{entry.Alpha2, entry.Name})
+	}
+	writeRows(filepath.Join(*out, "iso3166_1.csv"), []string{"code", "name"}, countries)
+
+	var subdivisions [][]string
+	for _, entry := range readEntries(filepath.Join(*source, "iso_3166-2.json"), "3166-2") {
+		subdivisions = append(subdivisions, []string{entry.Code, entry.Name})
+	}
+	writeRows(filepath.Join(*out, "iso3166_2.csv"), []string{"code", "name"}, subdivisions)
+}
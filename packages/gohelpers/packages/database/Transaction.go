@@ -0,0 +1,24 @@
+package database
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/opentix/platform/packages/gohelpers/packages/query"
+)
+
+// Runs fn in a transaction on conn, committing if it returns nil and rolling
+// back otherwise. The queries given to fn run inside the transaction.
+func InTransaction(ctx context.Context, conn *pgx.Conn, fn func(queries *query.Queries) error) error {
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := fn(query.New(conn).WithTx(tx)); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,117 @@
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/opentix/platform/packages/gohelpers/packages/query"
+)
+
+// Where exports too large for a response are written in the export store. The
+// photo cleanup job leaves keys under it alone, as local storage keeps exports
+// with the photos.
+const Prefix = "exports/"
+
+// The file format of an export, as stored in app.ticket_export.format
+type Format string
+
+const (
+	CSV Format = "csv"
+	// One JSON object per line
+	NDJSON Format = "ndjson"
+)
+
+var Formats = []Format{CSV, NDJSON}
+
+func (f Format) ContentType() string {
+	if f == NDJSON {
+		return "application/x-ndjson"
+	}
+	return "text/csv"
+}
+
+// Key returns where the export job writes the export with the given id
+func Key(id uuid.UUID, format Format) string {
+	return Prefix + "tickets/" + id.String() + "." + string(format)
+}
+
+// The name an event's ticket export is downloaded as
+func Filename(event uuid.UUID, format Format) string {
+	return "tickets-" + event.String() + "." + string(format)
+}
+
+// A line of an NDJSON export. CSV exports have the same columns.
+type Ticket struct {
+	TicketID    int32      `json:"ticket_id"`
+	Contract    string     `json:"contract"`
+	Tier        string     `json:"tier"`
+	Owner       *string    `json:"owner"`
+	CheckedIn   bool       `json:"checked_in"`
+	CheckedInAt *time.Time `json:"checked_in_at"`
+}
+
+var csvHeader = []string{"ticket_id", "contract", "tier", "owner", "checked_in", "checked_in_at"}
+
+func newTicket(row query.GetTicketExportRowsRow) Ticket {
+	ticket := Ticket{
+		TicketID:  row.TicketID,
+		Contract:  row.Contract,
+		Tier:      row.Tier,
+		CheckedIn: row.CheckedIn,
+	}
+	if row.Buyer.Valid {
+		ticket.Owner = &row.Buyer.String
+	}
+	if row.CheckedInAt.Valid {
+		t := row.CheckedInAt.Time.UTC()
+		ticket.CheckedInAt = &t
+	}
+	return ticket
+}
+
+// WriteTickets writes rows to w in format. Owner is the wallet that recorded
+// buying the ticket, transfers made on chain since aren't known.
+func WriteTickets(w io.Writer, format Format, rows []query.GetTicketExportRowsRow) error {
+	if format == NDJSON {
+		encoder := json.NewEncoder(w)
+		for _, row := range rows {
+			if err := encoder.Encode(newTicket(row)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		ticket := newTicket(row)
+		var owner, checkedInAt string
+		if ticket.Owner != nil {
+			owner = *ticket.Owner
+		}
+		if ticket.CheckedInAt != nil {
+			checkedInAt = ticket.CheckedInAt.Format(time.RFC3339)
+		}
+		err := writer.Write([]string{
+			strconv.Itoa(int(ticket.TicketID)),
+			ticket.Contract,
+			ticket.Tier,
+			owner,
+			strconv.FormatBool(ticket.CheckedIn),
+			checkedInAt,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
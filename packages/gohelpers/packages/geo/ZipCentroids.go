@@ -0,0 +1,87 @@
+package geo
+
+import (
+	_ "embed"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+//go:generate go run ./gen -out data/zip_centroids.csv
+
+// US ZIP Code Tabulation Area centroids from the Census Gazetteer files.
+// Bundled so venues can be geocoded without calling out to a third party.
+//
+//go:embed data/zip_centroids.csv
+var zipCentroidsCSV string
+
+var (
+	zipCentroids     map[string]Point
+	zipCentroidsErr  error
+	zipCentroidsOnce sync.Once
+)
+
+type Point struct {
+	Latitude  float64
+	Longitude float64
+}
+
+func loadZipCentroids() {
+	zipCentroids = make(map[string]Point)
+
+	reader := csv.NewReader(strings.NewReader(zipCentroidsCSV))
+	records, err := reader.ReadAll()
+	if err != nil {
+		zipCentroidsErr = fmt.Errorf("error parsing zip centroid dataset: %w", err)
+		return
+	}
+
+	// Skip the header row
+	for _, record := range records[min(1, len(records)):] {
+		if len(record) != 3 {
+			continue
+		}
+		lat, err := strconv.ParseFloat(record[1], 64)
+		if err != nil {
+			continue
+		}
+		lng, err := strconv.ParseFloat(record[2], 64)
+		if err != nil {
+			continue
+		}
+		zipCentroids[record[0]] = Point{Latitude: lat, Longitude: lng}
+	}
+	if len(zipCentroids) == 0 {
+		zipCentroidsErr = errors.New("zip centroid dataset is empty, run go generate ./packages/geo")
+	}
+}
+
+// Load parses the bundled dataset, reporting an error when it is missing or
+// empty. Handlers that geocode call it at startup to log that every lookup
+// will miss and venues will be saved without coordinates.
+func Load() error {
+	zipCentroidsOnce.Do(loadZipCentroids)
+	return zipCentroidsErr
+}
+
+// LookupZip returns the centroid of a postal code. Only US ZIP codes are in the
+// bundled dataset, every other country reports not found.
+func LookupZip(countryCode string, zip string) (Point, bool) {
+	if countryCode != "US" {
+		return Point{}, false
+	}
+	if Load() != nil {
+		return Point{}, false
+	}
+
+	// ZIP+4 codes share the centroid of their five digit prefix
+	zip = strings.TrimSpace(zip)
+	if len(zip) > 5 {
+		zip = zip[:5]
+	}
+	point, ok := zipCentroids[zip]
+	return point, ok
+}
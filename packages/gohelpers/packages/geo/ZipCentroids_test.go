@@ -0,0 +1,86 @@
+package geo
+
+import (
+	"sync"
+	"testing"
+)
+
+// Swaps the bundled dataset for data until the test ends, so the tests don't
+// depend on what go generate last wrote
+func useDataset(t *testing.T, data string) {
+	t.Helper()
+	bundled := zipCentroidsCSV
+	reset := func(data string) {
+		zipCentroidsCSV = data
+		zipCentroids = nil
+		zipCentroidsErr = nil
+		zipCentroidsOnce = sync.Once{}
+	}
+	reset(data)
+	t.Cleanup(func() { reset(bundled) })
+}
+
+const testDataset = `zip,latitude,longitude
+10001,40.750636,-73.997177
+02134,42.358431,-71.128314
+99999,not a number,-1
+`
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr bool
+	}{
+		{"missing", "", true},
+		{"header only", "zip,latitude,longitude\n", true},
+		{"only malformed rows", "zip,latitude,longitude\n99999,north,west\n", true},
+		{"malformed csv", "zip,latitude,longitude\n\"10001,40.7,-73.9\n", true},
+		{"valid", testDataset, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useDataset(t, tt.data)
+			if err := Load(); (err != nil) != tt.wantErr {
+				t.Errorf("Load() = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLookupZip(t *testing.T) {
+	useDataset(t, testDataset)
+
+	tests := []struct {
+		name    string
+		country string
+		zip     string
+		want    Point
+		wantOK  bool
+	}{
+		{"found", "US", "10001", Point{40.750636, -73.997177}, true},
+		{"leading zero", "US", "02134", Point{42.358431, -71.128314}, true},
+		{"zip+4", "US", "10001-1234", Point{40.750636, -73.997177}, true},
+		{"surrounding whitespace", "US", " 10001 ", Point{40.750636, -73.997177}, true},
+		{"unknown zip", "US", "12345", Point{}, false},
+		{"malformed row", "US", "99999", Point{}, false},
+		{"empty zip", "US", "", Point{}, false},
+		{"other country", "CA", "10001", Point{}, false},
+		{"lowercase country", "us", "10001", Point{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := LookupZip(tt.country, tt.zip)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("LookupZip(%q, %q) = %v, %v, want %v, %v", tt.country, tt.zip, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestLookupZipWithoutDataset(t *testing.T) {
+	useDataset(t, "zip,latitude,longitude\n")
+	if got, ok := LookupZip("US", "10001"); ok {
+		t.Errorf("LookupZip() = %v, true without a dataset, want not found", got)
+	}
+}
@@ -0,0 +1,83 @@
+// Regenerates data/zip_centroids.csv from the Census Gazetteer ZCTA file.
+//
+//	go generate ./packages/geo
+package main
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+)
+
+const gazetteerURL = "https://www2.census.gov/geo/docs/maps-data/data/gazetteer/2023_Gazetteer/2023_Gaz_zcta_national.zip"
+
+func main() {
+	out := flag.String("out", "data/zip_centroids.csv", "output csv path")
+	source := flag.String("url", gazetteerURL, "gazetteer zip archive url")
+	flag.Parse()
+
+	res, err := http.Get(*source)
+	if err != nil {
+		log.Fatalf("Error downloading gazetteer: %v", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		log.Fatalf("Error downloading gazetteer: status %v", res.Status)
+	}
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		log.Fatalf("Error reading gazetteer: %v", err)
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+	if err != nil {
+		log.Fatalf("Error opening gazetteer archive: %v", err)
+	}
+	if len(archive.File) == 0 {
+		log.Fatalf("Gazetteer archive is empty")
+	}
+	file, err := archive.File[0].Open()
+	if err != nil {
+		log.Fatalf("Error opening gazetteer file: %v", err)
+	}
+	defer file.Close()
+
+	// Tab separated: GEOID ALAND AWATER ALAND_SQMI AWATER_SQMI INTPTLAT INTPTLONG
+	var rows [][]string
+	scanner := bufio.NewScanner(file)
+	scanner.Scan()
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 7 {
+			continue
+		}
+		rows = append(rows, []string{fields[0], fields[5], fields[6]})
+	}
+	if err := scanner.Err(); err != nil {
+		log.Fatalf("Error reading gazetteer file: %v", err)
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i][0] < rows[j][0] })
+
+	f, err := os.Create(*out)
+	if err != nil {
+		log.Fatalf("Error creating %v: %v", *out, err)
+	}
+	defer f.Close()
+
+	writer := csv.NewWriter(f)
+	writer.Write([]string{"zip", "latitude", "longitude"})
+	writer.WriteAll(rows)
+	if err := writer.Error(); err != nil {
+		log.Fatalf("Error writing %v: %v", *out, err)
+	}
+	fmt.Printf("Wrote %d zip centroids to %v\n", len(rows), *out)
+}
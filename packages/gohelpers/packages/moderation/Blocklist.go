@@ -0,0 +1,33 @@
+package moderation
+
+import (
+	"context"
+	"image"
+
+	"github.com/opentix/platform/packages/gohelpers/packages/photo"
+)
+
+// How many bits of photo.Hash a photo may differ from a blocked one by and
+// still be rejected. Small enough that unrelated photos don't collide.
+const DefaultBlocklistDistance = 6
+
+// Rejects photos whose photo.Hash is close to one admins have blocked, so a
+// rejected photo can't simply be uploaded again
+type Blocklist struct {
+	Hashes      []uint64
+	MaxDistance int
+}
+
+func NewBlocklist(hashes []uint64) Blocklist {
+	return Blocklist{Hashes: hashes, MaxDistance: DefaultBlocklistDistance}
+}
+
+func (b Blocklist) Classify(ctx context.Context, img image.Image) (Result, error) {
+	hash := photo.Hash(img)
+	for _, blocked := range b.Hashes {
+		if photo.Distance(hash, blocked) <= b.MaxDistance {
+			return Result{Status: Rejected, Reason: "Photo matches a blocked photo"}, nil
+		}
+	}
+	return Result{Status: Approved}, nil
+}
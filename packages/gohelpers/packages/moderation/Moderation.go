@@ -0,0 +1,58 @@
+package moderation
+
+import (
+	"context"
+	"image"
+)
+
+// The moderation status of a gallery photo, as stored in app.media.status
+type Status string
+
+const (
+	// Waiting for an admin to review it
+	Pending  Status = "pending"
+	Approved Status = "approved"
+	Rejected Status = "rejected"
+)
+
+// What a Classifier decided about a photo. Reason is shown to the vendor and to
+// admins, and is empty for approved photos.
+type Result struct {
+	Status Status
+	Reason string
+}
+
+// Decides whether an uploaded photo can be shown to users, needs an admin to
+// look at it first, or is rejected outright
+type Classifier interface {
+	Classify(ctx context.Context, img image.Image) (Result, error)
+}
+
+// Runs each classifier in turn. A rejection ends the chain, otherwise the
+// first photo that isn't approved is left pending. An empty chain approves
+// everything.
+type Chain []Classifier
+
+func (c Chain) Classify(ctx context.Context, img image.Image) (Result, error) {
+	result := Result{Status: Approved}
+	for _, classifier := range c {
+		r, err := classifier.Classify(ctx, img)
+		if err != nil {
+			return Result{}, err
+		}
+		if r.Status == Rejected {
+			return r, nil
+		}
+		if r.Status == Pending && result.Status == Approved {
+			result = r
+		}
+	}
+	return result, nil
+}
+
+// Leaves every photo for an admin to review
+type RequireReview struct{}
+
+func (RequireReview) Classify(ctx context.Context, img image.Image) (Result, error) {
+	return Result{Status: Pending, Reason: "Awaiting review"}, nil
+}
@@ -0,0 +1,30 @@
+package moderation
+
+import (
+	"context"
+	"image"
+)
+
+// Checks that need nothing but the pixels. Photos too small to be of use are
+// rejected, and unusual shapes, which are more often banners or screenshots
+// than photos of an event, are left for review.
+type Rules struct {
+	// Smallest width and height accepted
+	MinSize int
+	// Longest side over shortest side above which a photo is reviewed
+	MaxAspectRatio float64
+}
+
+var DefaultRules = Rules{MinSize: 64, MaxAspectRatio: 4}
+
+func (r Rules) Classify(ctx context.Context, img image.Image) (Result, error) {
+	width, height := img.Bounds().Dx(), img.Bounds().Dy()
+	if width < r.MinSize || height < r.MinSize {
+		return Result{Status: Rejected, Reason: "Photo is too small"}, nil
+	}
+	long, short := max(width, height), min(width, height)
+	if float64(long)/float64(short) > r.MaxAspectRatio {
+		return Result{Status: Pending, Reason: "Photo has an unusual shape"}, nil
+	}
+	return Result{Status: Approved}, nil
+}
@@ -0,0 +1,77 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+)
+
+const defaultSMTPPort = "587"
+
+type smtpSecret struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// Returns the channels configured in the environment. Email is sent through
+// SMTP_HOST and SMTP_PORT as SMTP_FROM, with the credentials in SMTP_USERNAME
+// and SMTP_PASSWORD or the username and password of the secret
+// SMTP_SECRET_ARN, and is turned off when SMTP_HOST is unset. Push is always
+// on, EXPO_PUSH_URL and EXPO_ACCESS_TOKEN only need setting for tests and
+// secured projects.
+func FromEnv(ctx context.Context) ([]Channel, error) {
+	var channels []Channel
+
+	if host := os.Getenv("SMTP_HOST"); host != "" {
+		port := os.Getenv("SMTP_PORT")
+		if port == "" {
+			port = defaultSMTPPort
+		}
+		from := os.Getenv("SMTP_FROM")
+		if from == "" {
+			return nil, errors.New("SMTP_FROM must be set")
+		}
+		username, password := os.Getenv("SMTP_USERNAME"), os.Getenv("SMTP_PASSWORD")
+		if secretArn := os.Getenv("SMTP_SECRET_ARN"); secretArn != "" {
+			secret, err := getSMTPSecret(ctx, secretArn)
+			if err != nil {
+				return nil, err
+			}
+			username, password = secret.Username, secret.Password
+		}
+		channels = append(channels, &SMTPChannel{
+			Addr:     net.JoinHostPort(host, port),
+			Username: username,
+			Password: password,
+			From:     from,
+		})
+	}
+
+	channels = append(channels, &ExpoChannel{
+		URL:         os.Getenv("EXPO_PUSH_URL"),
+		AccessToken: os.Getenv("EXPO_ACCESS_TOKEN"),
+	})
+	return channels, nil
+}
+
+func getSMTPSecret(ctx context.Context, secretArn string) (smtpSecret, error) {
+	var secret smtpSecret
+	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion("us-east-1"))
+	if err != nil {
+		return secret, err
+	}
+	result, err := secretsmanager.NewFromConfig(cfg).GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{
+		SecretId: aws.String(secretArn),
+	})
+	if err != nil {
+		return secret, err
+	}
+	err = json.Unmarshal([]byte(aws.ToString(result.SecretString)), &secret)
+	return secret, err
+}
@@ -0,0 +1,139 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const expoPushURL = "https://exp.host/--/api/v2/push/send"
+
+// Sends notifications to the mobile apps through Expo's push service. Expo
+// takes at most 100 messages a request, far more tokens than anyone has.
+type ExpoChannel struct {
+	// Defaults to Expo's push API
+	URL string
+	// Only needed when push security is turned on for the Expo project
+	AccessToken string
+	Client      *http.Client
+}
+
+// Returned by ExpoChannel.Send when Expo reports tokens that no longer belong
+// to an installed app. They should be deleted, the other tokens may have been
+// sent to.
+type StaleTokensError struct {
+	Tokens []string
+}
+
+func (e *StaleTokensError) Error() string {
+	return fmt.Sprintf("%d push tokens are no longer registered", len(e.Tokens))
+}
+
+type expoMessage struct {
+	To    string            `json:"to"`
+	Title string            `json:"title"`
+	Body  string            `json:"body"`
+	Sound string            `json:"sound"`
+	Data  map[string]string `json:"data"`
+}
+
+// Expo answers with a ticket per message, in the order they were sent
+type expoResponse struct {
+	Data []struct {
+		Status  string `json:"status"`
+		Message string `json:"message"`
+		Details struct {
+			Error string `json:"error"`
+		} `json:"details"`
+	} `json:"data"`
+	Errors []struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+func (c *ExpoChannel) Name() string {
+	return "push"
+}
+
+func (c *ExpoChannel) Reaches(recipient Recipient) bool {
+	return len(recipient.PushTokens) > 0
+}
+
+func (c *ExpoChannel) Send(ctx context.Context, recipient Recipient, message Message) error {
+	messages := make([]expoMessage, 0, len(recipient.PushTokens))
+	for _, token := range recipient.PushTokens {
+		messages = append(messages, expoMessage{
+			To:    token,
+			Title: message.PushTitle,
+			Body:  message.PushBody,
+			Sound: "default",
+			// The apps open the event when the notification is tapped
+			Data: map[string]string{"eventId": message.EventID.String()},
+		})
+	}
+	body, err := json.Marshal(messages)
+	if err != nil {
+		return err
+	}
+
+	url := c.URL
+	if url == "" {
+		url = expoPushURL
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	if c.AccessToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.AccessToken)
+	}
+	client := c.Client
+	if client == nil {
+		client = &http.Client{Timeout: 15 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+
+	var result expoResponse
+	if err := json.Unmarshal(raw, &result); err != nil || resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("expo push responded %d: %s", resp.StatusCode, raw)
+	}
+	if len(result.Errors) > 0 {
+		return fmt.Errorf("expo push failed: %s: %s", result.Errors[0].Code, result.Errors[0].Message)
+	}
+
+	var stale []string
+	var failures []string
+	for i, ticket := range result.Data {
+		if ticket.Status == "ok" || i >= len(recipient.PushTokens) {
+			continue
+		}
+		if ticket.Details.Error == "DeviceNotRegistered" {
+			stale = append(stale, recipient.PushTokens[i])
+			continue
+		}
+		failures = append(failures, ticket.Message)
+	}
+	if len(failures) > 0 {
+		return fmt.Errorf("expo push failed: %s", strings.Join(failures, "; "))
+	}
+	if len(stale) > 0 {
+		return &StaleTokensError{Tokens: stale}
+	}
+	return nil
+}
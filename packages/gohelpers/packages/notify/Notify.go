@@ -0,0 +1,115 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/opentix/platform/packages/gohelpers/packages/query"
+)
+
+// What a notification is about, as stored in app.notification_outbox.kind
+type Kind string
+
+const (
+	EventUpdated   Kind = "event_updated"
+	EventCancelled Kind = "event_cancelled"
+)
+
+// What a notification's templates are rendered with. It is stored in the
+// outbox as JSON when the change is made, so the notification describes the
+// change even if the event changes again before it is sent.
+type Data struct {
+	EventID       uuid.UUID `json:"event_id"`
+	Name          string    `json:"name"`
+	EventDatetime time.Time `json:"event_datetime"`
+	EndDatetime   time.Time `json:"end_datetime"`
+	// The values an update replaced, only for the fields it changed
+	Previous *Previous `json:"previous,omitempty"`
+}
+
+type Previous struct {
+	Name               *string    `json:"name,omitempty"`
+	EventDatetime      *time.Time `json:"event_datetime,omitempty"`
+	EndDatetime        *time.Time `json:"end_datetime,omitempty"`
+	DescriptionChanged bool       `json:"description_changed,omitempty"`
+}
+
+func newData(event query.AppEvent) Data {
+	return Data{
+		EventID:       event.ID,
+		Name:          event.Name,
+		EventDatetime: event.EventDatetime.Time.UTC(),
+		EndDatetime:   event.EndDatetime.Time.UTC(),
+	}
+}
+
+// NewEventUpdated describes the change from before to after, reporting false
+// when nothing attendees are told about changed, as for a new photo.
+func NewEventUpdated(before query.AppEvent, after query.AppEvent) (Data, bool) {
+	data := newData(after)
+	previous := Previous{DescriptionChanged: before.Description != after.Description}
+	changed := previous.DescriptionChanged
+	if before.Name != after.Name {
+		previous.Name = &before.Name
+		changed = true
+	}
+	if !before.EventDatetime.Time.Equal(after.EventDatetime.Time) {
+		t := before.EventDatetime.Time.UTC()
+		previous.EventDatetime = &t
+		changed = true
+	}
+	if !before.EndDatetime.Time.Equal(after.EndDatetime.Time) {
+		t := before.EndDatetime.Time.UTC()
+		previous.EndDatetime = &t
+		changed = true
+	}
+	data.Previous = &previous
+	return data, changed
+}
+
+func NewEventCancelled(event query.AppEvent) Data {
+	return newData(event)
+}
+
+// Writes a notification to the outbox. queries should be in the transaction
+// that makes the change, so the two are saved together.
+func Enqueue(ctx context.Context, queries *query.Queries, kind Kind, event int32, data Data) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return queries.CreateNotification(ctx, query.CreateNotificationParams{
+		Kind:    string(kind),
+		Event:   event,
+		Payload: payload,
+	})
+}
+
+// Someone to notify, with the addresses they can be reached at on each channel
+type Recipient struct {
+	Wallet     string
+	Email      string
+	PushTokens []string
+}
+
+// A notification rendered for sending. Email gets the subject and body, push
+// the title and the shorter push body.
+type Message struct {
+	Subject   string
+	Body      string
+	PushTitle string
+	PushBody  string
+	EventID   uuid.UUID
+}
+
+// Somewhere notifications are sent, like email. Name is what deliveries on the
+// channel are recorded as in app.notification_delivery.
+type Channel interface {
+	Name() string
+	// Reports false when the recipient has no address on the channel
+	Reaches(recipient Recipient) bool
+	Send(ctx context.Context, recipient Recipient, message Message) error
+}
@@ -0,0 +1,78 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Sends notifications as plain text email through an SMTP server, like SES's
+// SMTP endpoint or the local Sink. Username can be empty for servers that
+// don't ask to authenticate.
+type SMTPChannel struct {
+	// host:port
+	Addr     string
+	Username string
+	Password string
+	From     string
+}
+
+func (c *SMTPChannel) Name() string {
+	return "email"
+}
+
+func (c *SMTPChannel) Reaches(recipient Recipient) bool {
+	return recipient.Email != ""
+}
+
+func (c *SMTPChannel) Send(ctx context.Context, recipient Recipient, message Message) error {
+	var auth smtp.Auth
+	if c.Username != "" {
+		host, _, err := net.SplitHostPort(c.Addr)
+		if err != nil {
+			return err
+		}
+		auth = smtp.PlainAuth("", c.Username, c.Password, host)
+	}
+	body, err := c.compose(recipient.Email, message)
+	if err != nil {
+		return err
+	}
+	return smtp.SendMail(c.Addr, auth, c.From, []string{recipient.Email}, body)
+}
+
+// The message with its headers, encoded quoted-printable since templates can
+// render any text
+func (c *SMTPChannel) compose(to string, message Message) ([]byte, error) {
+	var b bytes.Buffer
+	headers := []struct{ name, value string }{
+		{"From", c.From},
+		{"To", to},
+		{"Subject", mime.QEncoding.Encode("utf-8", message.Subject)},
+		{"Date", time.Now().UTC().Format(time.RFC1123Z)},
+		{"Message-ID", fmt.Sprintf("<%s@opentix>", uuid.New())},
+		{"MIME-Version", "1.0"},
+		{"Content-Type", "text/plain; charset=utf-8"},
+		{"Content-Transfer-Encoding", "quoted-printable"},
+	}
+	for _, header := range headers {
+		fmt.Fprintf(&b, "%s: %s\r\n", header.name, header.value)
+	}
+	b.WriteString("\r\n")
+
+	w := quotedprintable.NewWriter(&b)
+	if _, err := w.Write([]byte(message.Body)); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
@@ -0,0 +1,105 @@
+package notify
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// An SMTP server for trying notifications without sending email. It accepts
+// every message, without authentication, and writes each one to Dir as an .eml
+// file that mail clients can open.
+type Sink struct {
+	Dir string
+}
+
+// ListenAndServe accepts SMTP connections on addr until the listener fails
+func (s *Sink) ListenAndServe(addr string) error {
+	if err := os.MkdirAll(s.Dir, 0o755); err != nil {
+		return err
+	}
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	defer listener.Close()
+	log.Printf("SMTP sink listening on %s, writing mail to %s", addr, s.Dir)
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return err
+		}
+		go s.serve(conn)
+	}
+}
+
+func (s *Sink) serve(c net.Conn) {
+	defer c.Close()
+	conn := textproto.NewConn(c)
+	reply := func(code int, message string) bool {
+		return conn.PrintfLine("%d %s", code, message) == nil
+	}
+
+	var from string
+	var to []string
+	if !reply(220, "opentix SMTP sink") {
+		return
+	}
+	for {
+		c.SetDeadline(time.Now().Add(5 * time.Minute))
+		line, err := conn.ReadLine()
+		if err != nil {
+			return
+		}
+		verb, arg, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(verb) {
+		case "HELO", "EHLO":
+			reply(250, "Hello")
+		case "MAIL":
+			from, to = strings.TrimPrefix(arg, "FROM:"), nil
+			reply(250, "OK")
+		case "RCPT":
+			to = append(to, strings.TrimPrefix(arg, "TO:"))
+			reply(250, "OK")
+		case "DATA":
+			if len(to) == 0 {
+				reply(503, "RCPT first")
+				continue
+			}
+			reply(354, "End data with <CR><LF>.<CR><LF>")
+			data, err := io.ReadAll(conn.DotReader())
+			if err != nil {
+				return
+			}
+			if err := s.write(from, to, data); err != nil {
+				log.Printf("SMTP sink failed to write mail: %v", err)
+				reply(451, "Failed to store message")
+				continue
+			}
+			reply(250, "OK")
+		case "RSET":
+			from, to = "", nil
+			reply(250, "OK")
+		case "NOOP":
+			reply(250, "OK")
+		case "QUIT":
+			reply(221, "Bye")
+			return
+		default:
+			reply(502, "Command not implemented")
+		}
+	}
+}
+
+func (s *Sink) write(from string, to []string, data []byte) error {
+	name := fmt.Sprintf("%s-%d.eml", time.Now().UTC().Format("20060102T150405.000000000"), os.Getpid())
+	path := filepath.Join(s.Dir, name)
+	log.Printf("SMTP sink received mail from %s to %s: %s", from, strings.Join(to, ", "), path)
+	return os.WriteFile(path, data, 0o644)
+}
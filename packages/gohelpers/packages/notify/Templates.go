@@ -0,0 +1,51 @@
+package notify
+
+import (
+	"embed"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Each kind has a template file defining its subject, body, push_title and
+// push_body
+//
+//go:embed templates/*.tmpl
+var templateFiles embed.FS
+
+var templates = map[Kind]*template.Template{}
+
+var templateFuncs = template.FuncMap{
+	"datetime": func(t time.Time) string {
+		return t.UTC().Format("Mon, Jan 2 2006 at 15:04 UTC")
+	},
+}
+
+func init() {
+	for _, kind := range []Kind{EventUpdated, EventCancelled} {
+		templates[kind] = template.Must(template.New(string(kind)).Funcs(templateFuncs).ParseFS(templateFiles, "templates/"+string(kind)+".tmpl"))
+	}
+}
+
+// Render fills in the templates of kind with data
+func Render(kind Kind, data Data) (Message, error) {
+	t, ok := templates[kind]
+	if !ok {
+		return Message{}, fmt.Errorf("no templates for notifications of kind %q", kind)
+	}
+	message := Message{EventID: data.EventID}
+	for name, dst := range map[string]*string{
+		"subject":    &message.Subject,
+		"body":       &message.Body,
+		"push_title": &message.PushTitle,
+		"push_body":  &message.PushBody,
+	} {
+		var b strings.Builder
+		if err := t.ExecuteTemplate(&b, name, data); err != nil {
+			return Message{}, err
+		}
+		*dst = strings.TrimSpace(b.String())
+	}
+	return message, nil
+}
@@ -0,0 +1,141 @@
+package notify
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestRender(t *testing.T) {
+	id := uuid.MustParse("0f8fad5b-d9cb-469f-a165-70867728950e")
+	start := time.Date(2026, 10, 24, 20, 0, 0, 0, time.UTC)
+	end := start.Add(3 * time.Hour)
+	oldName := "Jazz Night"
+	oldStart := time.Date(2026, 10, 23, 19, 30, 0, 0, time.UTC)
+	oldEnd := oldStart.Add(3 * time.Hour)
+	data := func(previous *Previous) Data {
+		return Data{EventID: id, Name: "Jazz & Blues Night", EventDatetime: start, EndDatetime: end, Previous: previous}
+	}
+
+	tests := []struct {
+		name          string
+		kind          Kind
+		data          Data
+		wantSubject   string
+		wantPushTitle string
+		wantPushBody  string
+		// Lines the body must and mustn't have
+		wantBody    []string
+		notWantBody []string
+	}{
+		{
+			name:          "cancelled",
+			kind:          EventCancelled,
+			data:          data(nil),
+			wantSubject:   "Jazz & Blues Night has been cancelled",
+			wantPushTitle: "Jazz & Blues Night has been cancelled",
+			wantPushBody:  "The event on Sat, Oct 24 2026 at 20:00 UTC will not take place.",
+			wantBody:      []string{"Jazz & Blues Night, which was to start Sat, Oct 24 2026 at 20:00 UTC, has been cancelled"},
+		},
+		{
+			name:          "renamed",
+			kind:          EventUpdated,
+			data:          data(&Previous{Name: &oldName}),
+			wantSubject:   "Jazz & Blues Night has changed",
+			wantPushTitle: "Jazz & Blues Night has changed",
+			wantPushBody:  "Previously Jazz Night.",
+			wantBody:      []string{"The event is now called Jazz & Blues Night, it was previously Jazz Night."},
+			notWantBody:   []string{"It now starts", "It now ends", "description"},
+		},
+		{
+			name:         "rescheduled",
+			kind:         EventUpdated,
+			data:         data(&Previous{EventDatetime: &oldStart, EndDatetime: &oldEnd}),
+			wantSubject:  "Jazz & Blues Night has changed",
+			wantPushBody: "Now Sat, Oct 24 2026 at 20:00 UTC.",
+			wantBody: []string{
+				"It now starts Sat, Oct 24 2026 at 20:00 UTC instead of Fri, Oct 23 2026 at 19:30 UTC.",
+				"It now ends Sat, Oct 24 2026 at 23:00 UTC instead of Fri, Oct 23 2026 at 22:30 UTC.",
+			},
+			notWantBody: []string{"now called"},
+		},
+		{
+			name:         "end moved",
+			kind:         EventUpdated,
+			data:         data(&Previous{EndDatetime: &oldEnd}),
+			wantSubject:  "Jazz & Blues Night has changed",
+			wantPushBody: "Now Sat, Oct 24 2026 at 20:00 UTC.",
+			wantBody:     []string{"It now ends Sat, Oct 24 2026 at 23:00 UTC instead of Fri, Oct 23 2026 at 22:30 UTC."},
+			notWantBody:  []string{"It now starts"},
+		},
+		{
+			name:         "description changed",
+			kind:         EventUpdated,
+			data:         data(&Previous{DescriptionChanged: true}),
+			wantSubject:  "Jazz & Blues Night has changed",
+			wantPushBody: "The event details were updated.",
+			wantBody: []string{
+				"The description of the event was updated",
+				"Your tickets are still valid for Jazz & Blues Night, starting Sat, Oct 24 2026 at 20:00 UTC.",
+			},
+			notWantBody: []string{"now called", "It now starts"},
+		},
+		{
+			name:         "everything changed",
+			kind:         EventUpdated,
+			data:         data(&Previous{Name: &oldName, EventDatetime: &oldStart, DescriptionChanged: true}),
+			wantSubject:  "Jazz & Blues Night has changed",
+			wantPushBody: "Now Sat, Oct 24 2026 at 20:00 UTC.",
+			wantBody:     []string{"now called", "It now starts", "description"},
+		},
+		{
+			name:         "times are shown in UTC",
+			kind:         EventCancelled,
+			data:         Data{Name: "Jazz", EventDatetime: start.In(time.FixedZone("EDT", -4*60*60))},
+			wantSubject:  "Jazz has been cancelled",
+			wantPushBody: "The event on Sat, Oct 24 2026 at 20:00 UTC will not take place.",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message, err := Render(tt.kind, tt.data)
+			if err != nil {
+				t.Fatalf("Render() = %v", err)
+			}
+			if message.EventID != tt.data.EventID {
+				t.Errorf("EventID = %v, want %v", message.EventID, tt.data.EventID)
+			}
+			if message.Subject != tt.wantSubject {
+				t.Errorf("Subject = %q, want %q", message.Subject, tt.wantSubject)
+			}
+			if tt.wantPushTitle != "" && message.PushTitle != tt.wantPushTitle {
+				t.Errorf("PushTitle = %q, want %q", message.PushTitle, tt.wantPushTitle)
+			}
+			if message.PushBody != tt.wantPushBody {
+				t.Errorf("PushBody = %q, want %q", message.PushBody, tt.wantPushBody)
+			}
+			// Plain text, so nothing is escaped the way html/template would
+			if !strings.HasPrefix(message.Body, "Hello,") || strings.Contains(message.Body, "&amp;") {
+				t.Errorf("Body = %q, want plain text starting with the greeting", message.Body)
+			}
+			for _, want := range tt.wantBody {
+				if !strings.Contains(message.Body, want) {
+					t.Errorf("Body = %q, want it to contain %q", message.Body, want)
+				}
+			}
+			for _, notWant := range tt.notWantBody {
+				if strings.Contains(message.Body, notWant) {
+					t.Errorf("Body = %q, want it without %q", message.Body, notWant)
+				}
+			}
+		})
+	}
+}
+
+func TestRenderUnknownKind(t *testing.T) {
+	if _, err := Render(Kind("event_postponed"), Data{Name: "Jazz"}); err == nil {
+		t.Error("Render() of an unknown kind succeeded, want an error")
+	}
+}
@@ -0,0 +1,233 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Types that marshal to something other than their Go structure. The pgtype
+// wrappers marshal to their value, or null when not Valid.
+var knownTypes = map[reflect.Type]Schema{
+	reflect.TypeOf(time.Time{}):          {Type: "string", Format: "date-time"},
+	reflect.TypeOf(uuid.UUID{}):          {Type: "string", Format: "uuid"},
+	reflect.TypeOf(json.RawMessage{}):    {},
+	reflect.TypeOf(pgtype.Text{}):        {Type: "string", Nullable: true},
+	reflect.TypeOf(pgtype.Timestamptz{}): {Type: "string", Format: "date-time", Nullable: true},
+	reflect.TypeOf(pgtype.Float8{}):      {Type: "number", Format: "double", Nullable: true},
+	reflect.TypeOf(pgtype.Int4{}):        {Type: "integer", Format: "int32", Nullable: true},
+	reflect.TypeOf(pgtype.Int8{}):        {Type: "integer", Format: "int64", Nullable: true},
+	reflect.TypeOf(pgtype.Bool{}):        {Type: "boolean", Nullable: true},
+	reflect.TypeOf(pgtype.UUID{}):        {Type: "string", Format: "uuid", Nullable: true},
+}
+
+var versionedPackage = regexp.MustCompile(`^v[0-9]+$`)
+
+// Builds schemas from Go types, collecting every named struct it meets as a
+// component so the document can reference it by name.
+//
+// Field names and omission follow encoding/json. Structs whose fields carry
+// `validate` tags (see shared.DecodeAndValidate in apps/api) are request
+// bodies: their required fields and constraints come from the tags. Any other
+// struct is a response, and every field it doesn't mark omitempty is required
+// since it is always sent.
+type Generator struct {
+	Schemas  map[string]*Schema
+	names    map[reflect.Type]string
+	notNull  map[reflect.Type][]string
+	nullable map[reflect.Type][]string
+}
+
+func NewGenerator() *Generator {
+	return &Generator{
+		Schemas:  map[string]*Schema{},
+		names:    map[reflect.Type]string{},
+		notNull:  map[reflect.Type][]string{},
+		nullable: map[reflect.Type][]string{},
+	}
+}
+
+// Marks fields of v's type as never null. sqlc types NOT NULL timestamp columns
+// as pgtype.Timestamptz all the same, which would otherwise be nullable.
+func (g *Generator) NotNull(v interface{}, fields ...string) {
+	t := reflect.TypeOf(v)
+	g.notNull[t] = append(g.notNull[t], fields...)
+}
+
+// Marks fields of v's type as accepting null. Request bodies decode null into
+// the zero value of a plain field, so a handler that treats it as clearing the
+// field (see shared.NullFields in apps/api) has to say so.
+func (g *Generator) Nullable(v interface{}, fields ...string) {
+	t := reflect.TypeOf(v)
+	g.nullable[t] = append(g.nullable[t], fields...)
+}
+
+// Returns the schema for the type of v, a reference for named structs.
+func (g *Generator) SchemaOf(v interface{}) *Schema {
+	return g.schema(reflect.TypeOf(v))
+}
+
+// Registers a hand written schema as a component and returns a reference to it.
+func (g *Generator) Define(name string, schema *Schema) *Schema {
+	g.Schemas[name] = schema
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+func (g *Generator) schema(t reflect.Type) *Schema {
+	if known, ok := knownTypes[t]; ok {
+		return &known
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		s := g.schema(t.Elem())
+		if s.Ref != "" {
+			return s
+		}
+		s.Nullable = true
+		return s
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int32, reflect.Int16, reflect.Int8, reflect.Uint16, reflect.Uint8:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: g.schema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t)
+		}
+		return g.component(t)
+	}
+	// interface{} and anything else that can hold any JSON value
+	return &Schema{}
+}
+
+func (g *Generator) component(t reflect.Type) *Schema {
+	name, ok := g.names[t]
+	if !ok {
+		name = t.Name()
+		pkg := t.PkgPath()[strings.LastIndex(t.PkgPath(), "/")+1:]
+		// Types of a versioned package like v1 are always prefixed, since they
+		// mostly share their names with the types they replace
+		if _, taken := g.Schemas[name]; taken || versionedPackage.MatchString(pkg) {
+			name = strings.ToUpper(pkg[:1]) + pkg[1:] + name
+		}
+		g.names[t] = name
+		// Reserve the name before descending so recursive types terminate
+		g.Schemas[name] = &Schema{}
+		*g.Schemas[name] = *g.structSchema(t)
+	}
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+func (g *Generator) structSchema(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	isRequest := hasValidateTags(t)
+	g.addFields(s, t, isRequest)
+	return s
+}
+
+func (g *Generator) addFields(s *Schema, t reflect.Type, isRequest bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" && opts == "" {
+			continue
+		}
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			g.addFields(s, field.Type, isRequest)
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		prop := g.schema(field.Type)
+		if slices.Contains(g.notNull[t], field.Name) {
+			prop.Nullable = false
+		}
+		if slices.Contains(g.nullable[t], field.Name) {
+			prop.Nullable = true
+		}
+		rules := field.Tag.Get("validate")
+		applyRules(prop, rules)
+
+		s.Properties[name] = prop
+		s.order = append(s.order, name)
+		if isRequest && hasRule(rules, "required") || !isRequest && !strings.Contains(opts, "omitempty") {
+			s.Required = append(s.Required, name)
+		}
+	}
+}
+
+func hasValidateTags(t reflect.Type) bool {
+	for i := 0; i < t.NumField(); i++ {
+		if _, ok := t.Field(i).Tag.Lookup("validate"); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func hasRule(rules string, rule string) bool {
+	return slices.Contains(strings.Split(rules, ","), rule)
+}
+
+// Translates validate tag rules into schema constraints.
+func applyRules(s *Schema, rules string) {
+	if rules == "" || s.Ref != "" {
+		return
+	}
+	for _, rule := range strings.Split(rules, ",") {
+		key, arg, _ := strings.Cut(rule, "=")
+		switch key {
+		case "min", "max":
+			n, err := strconv.ParseFloat(arg, 64)
+			if err != nil {
+				continue
+			}
+			if s.Type == "string" {
+				length := int(n)
+				if key == "min" {
+					s.MinLength = &length
+				} else {
+					s.MaxLength = &length
+				}
+			} else if key == "min" {
+				s.Minimum = &n
+			} else {
+				s.Maximum = &n
+			}
+		case "oneof":
+			s.Enum = strings.Split(arg, "|")
+		case "uuid":
+			s.Format = "uuid"
+		case "datetime":
+			s.Format = "date-time"
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package openapi
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Renders the document's component schemas as TypeScript type declarations,
+// one exported type per component, in the style of packages/types.
+func TypeScript(doc *Document, header string) string {
+	var b strings.Builder
+	b.WriteString(header)
+
+	names := make([]string, 0, len(doc.Components.Schemas))
+	for name := range doc.Components.Schemas {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		schema := doc.Components.Schemas[name]
+		if schema.Description != "" {
+			for _, line := range strings.Split(schema.Description, "\n") {
+				fmt.Fprintf(&b, "\n// %v", line)
+			}
+		}
+		fmt.Fprintf(&b, "\nexport type %v = %v;\n", name, tsType(schema, 0))
+	}
+	return b.String()
+}
+
+func tsType(s *Schema, depth int) string {
+	t := tsBaseType(s, depth)
+	if s.Nullable {
+		return t + " | null"
+	}
+	return t
+}
+
+func tsBaseType(s *Schema, depth int) string {
+	if s.Ref != "" {
+		return s.Ref[strings.LastIndex(s.Ref, "/")+1:]
+	}
+	if len(s.Enum) > 0 {
+		literals := make([]string, len(s.Enum))
+		for i, e := range s.Enum {
+			literals[i] = "'" + strings.ReplaceAll(e, "'", "\\'") + "'"
+		}
+		return strings.Join(literals, " | ")
+	}
+
+	switch s.Type {
+	case "string":
+		return "string"
+	case "integer", "number":
+		return "number"
+	case "boolean":
+		return "boolean"
+	case "array":
+		item := tsType(s.Items, depth)
+		if strings.Contains(item, " ") {
+			return "(" + item + ")[]"
+		}
+		return item + "[]"
+	case "object":
+		if s.Properties == nil {
+			if s.AdditionalProperties != nil {
+				return "Record<string, " + tsType(s.AdditionalProperties, depth) + ">"
+			}
+			return "Record<string, unknown>"
+		}
+		return tsObject(s, depth)
+	}
+	return "unknown"
+}
+
+func tsObject(s *Schema, depth int) string {
+	order := s.order
+	if len(order) != len(s.Properties) {
+		order = order[:0:0]
+		for name := range s.Properties {
+			order = append(order, name)
+		}
+		slices.Sort(order)
+	}
+
+	indent := strings.Repeat("\t", depth+1)
+	var b strings.Builder
+	b.WriteString("{\n")
+	for _, name := range order {
+		optional := "?"
+		if slices.Contains(s.Required, name) {
+			optional = ""
+		}
+		fmt.Fprintf(&b, "%v%v%v: %v;\n", indent, name, optional, tsType(s.Properties[name], depth+1))
+	}
+	b.WriteString(strings.Repeat("\t", depth) + "}")
+	return b.String()
+}
@@ -0,0 +1,33 @@
+package photo
+
+import (
+	"image"
+	"math/bits"
+
+	"golang.org/x/image/draw"
+)
+
+// Hash returns a perceptual hash of img. The same photo resized, re-encoded or
+// slightly edited hashes to values a small Distance apart, unlike a hash of the
+// file's bytes.
+func Hash(img image.Image) uint64 {
+	// Each bit compares the brightness of a pixel with its right neighbour
+	small := image.NewGray(image.Rect(0, 0, 9, 8))
+	draw.BiLinear.Scale(small, small.Bounds(), img, img.Bounds(), draw.Src, nil)
+
+	var hash uint64
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			hash <<= 1
+			if small.GrayAt(x, y).Y > small.GrayAt(x+1, y).Y {
+				hash |= 1
+			}
+		}
+	}
+	return hash
+}
+
+// Distance is the number of bits two hashes differ in
+func Distance(a uint64, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
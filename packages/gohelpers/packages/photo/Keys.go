@@ -0,0 +1,81 @@
+package photo
+
+import (
+	"errors"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// Where vendors' uploads are written, as {UploadPrefix}{type}/{uuid}/{media}.{ext}
+const UploadPrefix = "uploads/"
+
+// Where the resized copies of uploads are written, as
+// {VariantPrefix}{type}/{uuid}/{media}/{variant}.{ext}
+const VariantPrefix = "variants/"
+
+var ErrInvalidKey = errors.New("not a photo upload key")
+
+// The record types photos can be uploaded for
+var RecordTypes = []string{"event", "venue"}
+
+var extensions = map[string]string{
+	"image/jpeg": "jpg",
+	"image/png":  "png",
+	"image/gif":  "gif",
+	"image/webp": "webp",
+}
+
+// An upload to the gallery of an event or venue
+type Upload struct {
+	// "event" or "venue"
+	RecordType string
+	RecordID   uuid.UUID
+	// The gallery item created for the upload
+	MediaID uuid.UUID
+}
+
+// Key returns the object key the upload is written to, for a file of
+// contentType.
+func (u Upload) Key(contentType string) string {
+	key := UploadPrefix + u.RecordType + "/" + u.RecordID.String() + "/" + u.MediaID.String()
+	if ext, ok := extensions[contentType]; ok {
+		key += "." + ext
+	}
+	return key
+}
+
+// ParseUploadKey returns the upload an object key made by Upload.Key is for.
+func ParseUploadKey(key string) (Upload, error) {
+	rest, ok := strings.CutPrefix(key, UploadPrefix)
+	if !ok {
+		return Upload{}, ErrInvalidKey
+	}
+	parts := strings.Split(rest, "/")
+	if len(parts) != 3 {
+		return Upload{}, ErrInvalidKey
+	}
+
+	recordType := parts[0]
+	if !slices.Contains(RecordTypes, recordType) {
+		return Upload{}, ErrInvalidKey
+	}
+	recordID, err := uuid.Parse(parts[1])
+	if err != nil {
+		return Upload{}, ErrInvalidKey
+	}
+	name, _, _ := strings.Cut(parts[2], ".")
+	mediaID, err := uuid.Parse(name)
+	if err != nil {
+		return Upload{}, ErrInvalidKey
+	}
+	return Upload{RecordType: recordType, RecordID: recordID, MediaID: mediaID}, nil
+}
+
+// VariantDir returns the directory the upload's variants are written to. Every
+// upload has its own, so a replaced photo never has the same URL as one cached
+// before it.
+func (u Upload) VariantDir() string {
+	return VariantPrefix + u.RecordType + "/" + u.RecordID.String() + "/" + u.MediaID.String() + "/"
+}
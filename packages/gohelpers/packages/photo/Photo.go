@@ -0,0 +1,139 @@
+package photo
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"net/http"
+
+	_ "image/gif"
+	_ "image/png"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+// Largest upload accepted, in bytes. Presigned uploads are bound to the size
+// the client declares, which must not exceed this.
+const MaxUploadSize = 10 << 20
+
+// Uploads with more pixels than this are rejected before being decoded, so a
+// small file can't expand into more memory than the processing lambda has.
+const maxPixels = 40_000_000
+
+// Quality the variants are encoded at
+const jpegQuality = 85
+
+// The image formats vendors may upload
+var AllowedContentTypes = []string{"image/jpeg", "image/png", "image/gif", "image/webp"}
+
+var ErrNotAnImage = errors.New("file is not a supported image")
+var ErrTooLarge = errors.New("image is too large")
+
+// IsAllowedContentType reports whether contentType is one of AllowedContentTypes.
+func IsAllowedContentType(contentType string) bool {
+	for _, allowed := range AllowedContentTypes {
+		if contentType == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// A resized copy of an uploaded photo. Width and Height bound the result; when
+// Crop is set the photo is cropped to exactly that size around its centre,
+// otherwise it is scaled to Width keeping its aspect ratio and Height is ignored.
+type Variant struct {
+	Name   string
+	Width  int
+	Height int
+	Crop   bool
+}
+
+var Thumbnail = Variant{Name: "thumbnail", Width: 256, Height: 256, Crop: true}
+var Card = Variant{Name: "card", Width: 640}
+var Hero = Variant{Name: "hero", Width: 1600}
+
+// Every variant made for an upload
+var Variants = []Variant{Thumbnail, Card, Hero}
+
+// Decode checks data is an image in one of AllowedContentTypes and decodes it,
+// applying any EXIF orientation so the result is upright. The content is
+// sniffed rather than trusting the type given at upload.
+func Decode(data []byte) (image.Image, error) {
+	if len(data) > MaxUploadSize {
+		return nil, ErrTooLarge
+	}
+	if !IsAllowedContentType(http.DetectContentType(data)) {
+		return nil, ErrNotAnImage
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNotAnImage, err)
+	}
+	if config.Width <= 0 || config.Height <= 0 {
+		return nil, ErrNotAnImage
+	}
+	if config.Width*config.Height > maxPixels {
+		return nil, ErrTooLarge
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNotAnImage, err)
+	}
+	return orient(img, exifOrientation(data)), nil
+}
+
+// Resize scales img down to fit v. Images are never scaled up, so a small upload
+// gives variants no larger than itself. Transparency is flattened onto white.
+func Resize(img image.Image, v Variant) image.Image {
+	src := img.Bounds()
+	width, height := src.Dx(), src.Dy()
+
+	if v.Crop {
+		// Take the largest centred region with the variant's aspect ratio
+		if width*v.Height > height*v.Width {
+			cropWidth := height * v.Width / v.Height
+			src.Min.X += (width - cropWidth) / 2
+			src.Max.X = src.Min.X + cropWidth
+		} else {
+			cropHeight := width * v.Height / v.Width
+			src.Min.Y += (height - cropHeight) / 2
+			src.Max.Y = src.Min.Y + cropHeight
+		}
+		width, height = v.Width, v.Height
+		if src.Dx() < width {
+			width, height = src.Dx(), src.Dy()
+		}
+	} else if width > v.Width {
+		height = height * v.Width / width
+		width = v.Width
+	}
+	width, height = max(width, 1), max(height, 1)
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(dst, dst.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, src, draw.Over, nil)
+	return dst
+}
+
+// Encode writes img as a JPEG. Only the pixels are written, so metadata from
+// the upload such as EXIF location data never reaches the stored variants.
+func Encode(img image.Image) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: jpegQuality}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Content type of the data returned by Encode
+const EncodedContentType = "image/jpeg"
+
+// File extension of the data returned by Encode
+const EncodedExtension = "jpg"
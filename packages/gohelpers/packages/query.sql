@@ -23,10 +23,26 @@ where venue.vendor = (
     select pk from app.vendor vendor
     where vendor.wallet = $2
 )
-and ($3::text = '' or $3::text like LOWER(venue.name) or $3::text like LOWER(venue.zip) or $3::text like LOWER(venue.city))
-order by venue.name
-limit 25
-offset (($1::int - 1) * 25);
+and ($3::text = '' or exists (
+    select 1 from app.venue_search venue_search
+    where venue_search.venue = venue.pk
+    and venue_search.document @@ to_tsquery('english', $4::text)
+) or word_similarity($3::text, venue.name) >= 0.4)
+and ($5::boolean = false or (venue.name, venue.pk) > ($6::text, $7::int))
+order by venue.name, venue.pk
+limit $1::int;
+
+-- name: VendorCountVenues :one
+select count(*) from app.venue venue
+where venue.vendor = (
+    select pk from app.vendor vendor
+    where vendor.wallet = $1
+)
+and ($2::text = '' or exists (
+    select 1 from app.venue_search venue_search
+    where venue_search.venue = venue.pk
+    and venue_search.document @@ to_tsquery('english', $3::text)
+) or word_similarity($2::text, venue.name) >= 0.4);
 
 -- name: VendorGetVenueByPk :one
 select * from app.venue 
@@ -37,6 +53,15 @@ and venue.vendor = (
 )
 limit 1;
 
+-- name: VendorGetVenueByPkForUpdate :one
+select * from app.venue
+where venue.pk = $1
+and venue.vendor = (
+    select pk from app.vendor
+    where wallet = $2
+)
+for update;
+
 -- name: VendorGetVenueByUuid :one
 select * from app.venue 
 where venue.id = $1 
@@ -54,6 +79,18 @@ where venue.vendor = (
 )
 order by venue.name;
 
+-- name: VendorFindDuplicateVenue :one
+select * from app.venue
+where venue.vendor = (
+    select pk from app.vendor
+    where wallet = $1
+)
+and lower(venue.street_address) = lower($2::text)
+and venue.zip = $3
+and venue.country_code = $4
+and venue.pk <> $5::int
+limit 1;
+
 -- name: VendorGetEventsPaginated :many
 select * from app.event event
 where event.vendor = (
@@ -62,10 +99,29 @@ where event.vendor = (
 )
 and ($3::int = -1 or $3::int = event.venue)
 and ($4::timestamptz <= event.event_datetime)
-and ($5::text = '' or $5::text like LOWER(event.name) or $5::text like LOWER(event.type))
-order by event.event_datetime, event.name
-limit 25
-offset (($1::int - 1) * 25);
+and ($5::text = '' or exists (
+    select 1 from app.event_search event_search
+    where event_search.event = event.pk
+    and event_search.document @@ to_tsquery('english', $6::text)
+) or word_similarity($5::text, event.name) >= 0.4)
+and ($7::boolean = false
+    or (event.event_datetime, event.name, event.pk) > ($8::timestamptz, $9::text, $10::int))
+order by event.event_datetime, event.name, event.pk
+limit $1::int;
+
+-- name: VendorCountEvents :one
+select count(*) from app.event event
+where event.vendor = (
+    select pk from app.vendor vendor
+    where vendor.wallet = $1
+)
+and ($2::int = -1 or $2::int = event.venue)
+and ($3::timestamptz <= event.event_datetime)
+and ($4::text = '' or exists (
+    select 1 from app.event_search event_search
+    where event_search.event = event.pk
+    and event_search.document @@ to_tsquery('english', $5::text)
+) or word_similarity($4::text, event.name) >= 0.4);
 
 -- name: VendorGetEventByPk :one
 select * from app.event event
@@ -76,6 +132,15 @@ and event.vendor = (
 )
 limit 1;
 
+-- name: VendorGetEventByPkForUpdate :one
+select * from app.event event
+where event.pk = $1
+and event.vendor = (
+    select pk from app.vendor
+    where wallet = $2
+)
+for update;
+
 -- name: GetEventByUuid :one
 select * from app.event event
 where event.id = $1
@@ -106,17 +171,75 @@ set
   name = coalesce(nullif($3::text, ''), name),
   type = coalesce(nullif($4::text, ''), type),
   event_datetime = coalesce($5::timestamptz, event_datetime),
+  end_datetime = coalesce($13::timestamptz, end_datetime),
   description = coalesce(nullif($6::text, ''), description),
-  disclaimer = coalesce(nullif($7::text, ''), disclaimer),
-  photo = coalesce(nullif($8::text, ''), photo),
-  transaction_hash = coalesce(nullif($9::text, ''), transaction_hash)
+  disclaimer = case when $9::bool then null else coalesce(nullif($7::text, ''), disclaimer) end,
+  photo = case when $10::bool then null else photo end,
+  photo_thumbnail = case when $10::bool then null else photo_thumbnail end,
+  photo_card = case when $10::bool then null else photo_card end,
+  transaction_hash = case when $11::bool then null else coalesce(nullif($8::text, ''), transaction_hash) end
 where event.pk = $1
   and event.vendor = (
     select pk from app.vendor
     where wallet = $2
   )
+  and ($12::int = 0 or event.version = $12::int)
+returning *;
+
+-- name: VendorUpdateEventCapacity :one
+update app.event
+set
+  num_unique = $3,
+  num_ga = $4
+where event.pk = $1
+  and event.vendor = (
+    select pk from app.vendor
+    where wallet = $2
+  )
+  and ($5::int = 0 or event.version = $5::int)
+returning *;
+
+-- name: VendorUpdateVenueCapacity :one
+update app.venue
+set
+  num_unique = $3,
+  num_ga = $4
+where venue.pk = $1
+  and venue.vendor = (
+    select pk from app.vendor
+    where wallet = $2
+  )
+  and ($5::int = 0 or venue.version = $5::int)
 returning *;
 
+-- name: VendorGetVenueCapacityConflicts :many
+select event.pk, event.id, event.name, event.event_datetime, event.num_unique, event.num_ga
+from app.event event
+where event.venue = $1
+  and event.end_datetime > now()
+  and event.cancelled_at is null
+  and (event.num_unique > $2::int or event.num_ga > $3::int)
+order by event.event_datetime, event.pk;
+
+-- name: VendorGetOverlappingEvents :many
+select event.pk, event.id, event.name, event.event_datetime, event.end_datetime
+from app.event event
+where event.venue = $1
+  and event.event_datetime < $3::timestamptz
+  and event.end_datetime > $2::timestamptz
+  and event.pk <> $4::int
+  and event.cancelled_at is null
+order by event.event_datetime, event.pk;
+
+-- name: VendorGetVenueCalendar :many
+select event.pk, event.id, event.name, event.event_datetime, event.end_datetime
+from app.event event
+where event.venue = $1
+  and event.event_datetime < $3::timestamptz
+  and event.end_datetime > $2::timestamptz
+  and event.cancelled_at is null
+order by event.event_datetime, event.pk;
+
 -- name: VendorPatchVenue :one
 update app.venue
 set
@@ -128,12 +251,17 @@ set
   state_name = coalesce(nullif($8::text, ''), state_name),
   country_code = coalesce(nullif($9::text, ''), country_code),
   country_name = coalesce(nullif($10::text, ''), country_name),
-  photo = coalesce(nullif($11::text, ''), photo)
+  photo = case when $11::bool then null else photo end,
+  photo_thumbnail = case when $11::bool then null else photo_thumbnail end,
+  photo_card = case when $11::bool then null else photo_card end,
+  latitude = case when $13::bool then case when $14::bool then $15::double precision end else latitude end,
+  longitude = case when $13::bool then case when $14::bool then $16::double precision end else longitude end
 where venue.pk = $1
   and venue.vendor = (
     select pk from app.vendor
     where wallet = $2
   )
+  and ($12::int = 0 or venue.version = $12::int)
 returning *;
 
 -- name: CreateEvent :one
@@ -143,23 +271,15 @@ insert into app.event (
     name,
     type,
     event_datetime,
+    end_datetime,
     description,
     disclaimer,
     basecost,
     num_unique,
     num_ga
 ) values (
-    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
-) returning (
-    name,
-    type,
-    event_datetime,
-    description,
-    disclaimer,
-    basecost,
-    num_unique,
-    num_ga
-);
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
+) returning *;
 
 -- name: CreateVenue :one
 insert into app.venue (
@@ -173,40 +293,77 @@ insert into app.venue (
     country_name,
     num_unique,
     num_ga,
-    vendor
+    vendor,
+    latitude,
+    longitude
 ) values (
-    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
-) returning (
-    name,
-    street_address,
-    zip,
-    city,
-    state_code,
-    country_code,
-    country_name,
-    num_unique,
-    num_ga
-);
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13
+) returning *;
 
 -- name: UserGetEventsPaginated :many
-select event.name, event.type, event.event_datetime,
-venue.name Venuename, venue.state_code, venue.country_code, event.photo,
-event.id
-from app.event event, app.venue venue
-where event.venue = venue.pk
-and (cardinality($2::text[]) = 0 or venue.zip = ANY($2::text[]))
-and ($3::text = '' or $3::text = event.name)
+select results.pk, results.name, results.type, results.event_datetime,
+results.Venuename, results.state_code, results.country_code, results.photo,
+results.id, results.Rank, results.Snippet, results.Distance
+from (
+    select event.pk, event.name, event.type, event.event_datetime,
+    venue.name Venuename, venue.state_code, venue.country_code,
+    coalesce(event.photo_card, event.photo) photo,
+    event.id,
+    greatest(
+        ts_rank(event_search.document, to_tsquery('english', $4::text)),
+        word_similarity($3::text, event.name)
+    )::double precision Rank,
+    (case when $3::text = '' then ''
+    else ts_headline('english', event.description, to_tsquery('english', $4::text),
+        'StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MinWords=5, MaxWords=20')
+    end)::text Snippet,
+    (case when $13::boolean
+    then app.distance_miles($14::double precision, $15::double precision, venue.latitude, venue.longitude)
+    else 0 end)::double precision Distance
+    from app.event event
+    join app.venue venue on event.venue = venue.pk
+    join app.event_search event_search on event_search.event = event.pk
+    where (cardinality($2::text[]) = 0 or venue.zip = ANY($2::text[]))
+    and ($3::text = ''
+        or event_search.document @@ to_tsquery('english', $4::text)
+        or word_similarity($3::text, event.name) >= 0.4
+        or word_similarity($3::text, venue.name) >= 0.4)
+    and ($5::text = '' or $5::text = event.type)
+    and ($6::double precision >= event.basecost)
+    and ($7::timestamptz <= event.event_datetime)
+    and event.cancelled_at is null
+) results
+where ($13::boolean = false or results.Distance <= $16::double precision)
+and ($8::boolean = false
+    or (case when $13::boolean then results.Distance else -results.Rank end,
+        results.event_datetime, results.name, results.pk)
+        > ($9::double precision, $10::timestamptz, $11::text, $12::int))
+order by case when $13::boolean then results.Distance else -results.Rank end,
+    results.event_datetime, results.name, results.pk
+limit $1::int;
+
+-- name: UserCountEvents :one
+select count(*)
+from app.event event
+join app.venue venue on event.venue = venue.pk
+join app.event_search event_search on event_search.event = event.pk
+where (cardinality($1::text[]) = 0 or venue.zip = ANY($1::text[]))
+and ($2::text = ''
+    or event_search.document @@ to_tsquery('english', $3::text)
+    or word_similarity($2::text, event.name) >= 0.4
+    or word_similarity($2::text, venue.name) >= 0.4)
 and ($4::text = '' or $4::text = event.type)
 and ($5::double precision >= event.basecost)
 and ($6::timestamptz <= event.event_datetime)
-order by event.event_datetime, event.name
-limit 5
-offset (($1::int - 1) * 5);
+and event.cancelled_at is null
+and ($7::boolean = false
+    or app.distance_miles($8::double precision, $9::double precision, venue.latitude, venue.longitude)
+        <= $10::double precision);
 
 -- name: UserGetEventByUuid :one
 select event.name Eventname, event.type, event.event_datetime,
-event.id, event.description, event.disclaimer,
-event.basecost, event.num_unique, event.num_ga,
+event.end_datetime, event.id, event.description, event.disclaimer,
+event.basecost, event.num_unique, event.num_ga, event.cancelled_at,
 event.photo Eventphoto, venue.name Venuename, venue.street_address, venue.zip, venue.city,
 venue.state_code, venue.country_code, venue.country_name,
 venue.photo Venuephoto, vendor.name Vendorname
@@ -219,31 +376,31 @@ limit 1;
 
 -- name: InsecureUpdateVenuePhoto :one
 update app.venue
-set photo = $2
+set photo = $2, photo_thumbnail = $3, photo_card = $4
 where venue.id = $1
 returning *;
 
 -- name: InsecureUpdateEventPhoto :one
 update app.event
-set photo = $2
+set photo = $2, photo_thumbnail = $3, photo_card = $4
 where event.id = $1
 returning *;
 
 -- name: InsecureRemoveVenuePhoto :one
 update app.venue
-set photo = null
+set photo = null, photo_thumbnail = null, photo_card = null
 where venue.id = $1
 returning *;
 
 -- name: InsecureRemoveEventPhoto :one
 update app.event
-set photo = null
+set photo = null, photo_thumbnail = null, photo_card = null
 where event.id = $1
 returning *;
 
 -- name: VendorRemoveVenuePhoto :one
 update app.venue
-set photo = null
+set photo = null, photo_thumbnail = null, photo_card = null
 where venue.id = $1
 and venue.vendor = (
     select pk from app.vendor
@@ -253,7 +410,7 @@ returning *;
 
 -- name: VendorRemoveEventPhoto :one
 update app.event
-set photo = null
+set photo = null, photo_thumbnail = null, photo_card = null
 where event.id = $1
 and event.vendor = (
     select pk from app.vendor
@@ -261,6 +418,214 @@ and event.vendor = (
 )
 returning *;
 
+-- name: CreateMedia :one
+insert into app.media (
+    vendor,
+    event,
+    venue,
+    position,
+    caption,
+    alt_text
+) values (
+    $1,
+    $2,
+    $3,
+    (
+        select coalesce(max(position) + 1, 0) from app.media
+        where media.event = $2 or media.venue = $3
+    ),
+    $4,
+    $5
+)
+returning *;
+
+-- name: GetMedia :many
+select * from app.media
+where media.event = $1 or media.venue = $2
+order by position, pk;
+
+-- name: VendorGetMediaByUuid :one
+select * from app.media
+where media.id = $1
+and media.vendor = (
+    select pk from app.vendor
+    where wallet = $2
+);
+
+-- name: UserGetEventMedia :many
+select media.* from app.media media
+join app.event event on media.event = event.pk
+where event.id = $1
+and media.status = 'approved'
+and media.photo is not null
+order by media.position, media.pk;
+
+-- name: UpdateMediaPosition :exec
+update app.media
+set position = $2
+where media.pk = $1;
+
+-- name: UpdateMediaDetails :one
+update app.media
+set
+  caption = case when $4::bool then null else coalesce(nullif($2::text, ''), caption) end,
+  alt_text = case when $5::bool then null else coalesce(nullif($3::text, ''), alt_text) end
+where media.pk = $1
+returning *;
+
+-- name: ClearMediaCover :exec
+update app.media
+set is_cover = false
+where is_cover
+and (media.event = $1 or media.venue = $2);
+
+-- name: SetMediaCover :one
+update app.media
+set is_cover = true
+where media.pk = $1
+returning *;
+
+-- name: DeleteMedia :one
+delete from app.media
+where media.pk = $1
+returning *;
+
+-- name: InsecureGetMediaByUuid :one
+select * from app.media
+where media.id = $1;
+
+-- name: InsecureAttachMedia :one
+update app.media
+set photo = $2, photo_thumbnail = $3, photo_card = $4,
+    status = $5, moderation_reason = $6, photo_hash = $7
+where media.id = $1
+and media.status = 'pending'
+and media.photo is null
+returning *;
+
+-- name: InsecureRejectMedia :one
+update app.media
+set status = 'rejected', moderation_reason = $2, photo_hash = $3
+where media.id = $1
+and media.status = 'pending'
+and media.photo is null
+returning *;
+
+-- name: InsecureDeleteMedia :exec
+delete from app.media
+where media.id = $1;
+
+-- name: InsecureExpireStaleUploads :execrows
+delete from app.media
+where media.status = 'pending'
+and media.photo is null
+and media.created_at < $1::timestamptz;
+
+-- name: InsecureClaimMediaCover :one
+update app.media
+set is_cover = true
+where media.pk = $1
+and not exists (
+    select 1 from app.media cover
+    where cover.is_cover
+    and (cover.event = media.event or cover.venue = media.venue)
+)
+returning *;
+
+-- name: InsecureGetPhotoBlocklist :many
+select hash from app.photo_blocklist;
+
+-- name: InsecureSyncEventCover :exec
+update app.event
+set (photo, photo_thumbnail, photo_card) = (
+    select media.photo, media.photo_thumbnail, media.photo_card from app.media media
+    where media.event = event.pk
+    and media.is_cover
+)
+where event.pk = $1;
+
+-- name: InsecureSyncVenueCover :exec
+update app.venue
+set (photo, photo_thumbnail, photo_card) = (
+    select media.photo, media.photo_thumbnail, media.photo_card from app.media media
+    where media.venue = venue.pk
+    and media.is_cover
+)
+where venue.pk = $1;
+
+-- name: IsAdmin :one
+select exists (
+    select 1 from app.admin
+    where wallet = $1
+);
+
+-- name: AdminGetMediaByStatus :many
+select * from app.media
+where media.status = $1
+and ($2::boolean = false or media.pk > $3::int)
+order by media.pk
+limit $4::int;
+
+-- name: AdminCountMediaByStatus :one
+select count(*) from app.media
+where media.status = $1;
+
+-- name: AdminGetMediaByUuid :one
+select * from app.media
+where media.id = $1;
+
+-- name: AdminReviewMedia :one
+update app.media
+set status = $2,
+    moderation_reason = $3,
+    reviewed_by = $4,
+    reviewed_at = now(),
+    is_cover = is_cover and $2 = 'approved',
+    photo = case when $2 = 'rejected' then null else photo end,
+    photo_thumbnail = case when $2 = 'rejected' then null else photo_thumbnail end,
+    photo_card = case when $2 = 'rejected' then null else photo_card end
+where media.pk = $1
+returning *;
+
+-- name: AdminBlockPhotoHash :exec
+insert into app.photo_blocklist (hash, reason)
+values ($1, $2)
+on conflict (hash) do nothing;
+
+-- name: InsecureGetDeletedPhotos :many
+select deleted.pk, deleted.url, (
+    exists (
+        select 1 from app.media media
+        where deleted.url in (media.photo, media.photo_thumbnail, media.photo_card)
+    )
+    or exists (
+        select 1 from app.event event
+        where deleted.url in (event.photo, event.photo_thumbnail, event.photo_card)
+    )
+    or exists (
+        select 1 from app.venue venue
+        where deleted.url in (venue.photo, venue.photo_thumbnail, venue.photo_card)
+    )
+)::boolean referenced
+from app.deleted_photo deleted
+order by deleted.pk
+limit $1;
+
+-- name: InsecureDeleteDeletedPhotos :exec
+delete from app.deleted_photo
+where deleted_photo.pk = any($1::int[]);
+
+-- name: InsecureGetPhotoUrls :many
+select media.photo::text from app.media media where media.photo is not null
+union select media.photo_thumbnail::text from app.media media where media.photo_thumbnail is not null
+union select media.photo_card::text from app.media media where media.photo_card is not null
+union select event.photo::text from app.event event where event.photo is not null
+union select event.photo_thumbnail::text from app.event event where event.photo_thumbnail is not null
+union select event.photo_card::text from app.event event where event.photo_card is not null
+union select venue.photo::text from app.venue venue where venue.photo is not null
+union select venue.photo_thumbnail::text from app.venue venue where venue.photo_thumbnail is not null
+union select venue.photo_card::text from app.venue venue where venue.photo_card is not null;
+
 -- name: AddTicket :one
 insert into app.ticket (
     event,
@@ -276,5 +641,311 @@ select * from app.ticket where event = $1 and ticket_id = $2 limit 1;
 -- name: GetTicketsByEvent :many
 select * from app.ticket where event = $1;
 
+-- name: GetEventTicketSummary :one
+select
+    count(*) as minted,
+    count(*) filter (where ticket.sold_at is not null or ticket.checked_in) as sold,
+    coalesce(max(ticket.ticket_id), 0)::int as max_ticket_id,
+    coalesce(max(ticket.contract), '')::text as contract
+from app.ticket ticket
+where ticket.event = $1;
+
+-- name: ShareLockEvent :exec
+select pk from app.event event
+where event.pk = $1
+for share;
+
 -- name: UpdateCheckin :one
-update app.ticket set checked_in = $2 where pk = $1 returning *;
\ No newline at end of file
+update app.ticket set
+    checked_in = $2,
+    checked_in_at = case when $2 then now() end,
+    sold_at = coalesce(sold_at, case when $2 then now() end)
+where pk = $1 returning *;
+
+-- name: GetTicketExportRows :many
+select
+    ticket.ticket_id,
+    ticket.contract,
+    (case
+        when row_number() over (order by ticket.ticket_id) <= event.num_unique then 'unique'
+        else 'ga'
+    end)::text as tier,
+    ticket.buyer,
+    ticket.checked_in,
+    ticket.checked_in_at
+from app.ticket ticket
+join app.event event on event.pk = ticket.event
+where ticket.event = $1
+order by ticket.ticket_id;
+
+-- name: VendorCreateTicketExport :one
+insert into app.ticket_export (
+    event,
+    wallet,
+    format
+) values (
+    $1, $2, $3
+) returning *;
+
+-- name: VendorGetTicketExport :one
+select * from app.ticket_export
+where ticket_export.id = $1
+and ticket_export.wallet = $2
+limit 1;
+
+-- name: InsecureClaimTicketExports :many
+update app.ticket_export set status = 'running', claimed_at = now()
+where ticket_export.pk in (
+    select pending.pk from app.ticket_export pending
+    where pending.status = 'pending'
+    or (pending.status = 'running' and pending.claimed_at < now() - interval '15 minutes')
+    order by pending.pk
+    limit $1
+    for update skip locked
+)
+returning *;
+
+-- name: InsecureCompleteTicketExport :exec
+update app.ticket_export set status = 'done', object_key = $2, completed_at = now()
+where ticket_export.pk = $1;
+
+-- name: InsecureFailTicketExport :exec
+update app.ticket_export set status = 'failed', error = $2, completed_at = now()
+where ticket_export.pk = $1;
+
+-- name: InsecureGetExpiredTicketExports :many
+select * from app.ticket_export
+where ticket_export.status = 'done'
+and ticket_export.completed_at < $1::timestamptz
+order by ticket_export.pk;
+
+-- name: InsecureExpireTicketExport :exec
+update app.ticket_export set status = 'expired', object_key = null
+where ticket_export.pk = $1;
+
+-- name: VendorGetEventAnalytics :many
+select
+    event.id,
+    venue.id as venue_id,
+    venue.name as venue_name,
+    event.name,
+    event.event_datetime,
+    event.end_datetime,
+    event.basecost,
+    count(ticket.pk) as minted,
+    count(ticket.pk) filter (where ticket.sold_at is not null or ticket.checked_in) as sold,
+    count(ticket.pk) filter (where ticket.checked_in) as checked_in
+from app.event event
+join app.venue venue on venue.pk = event.venue
+left join app.ticket ticket on ticket.event = event.pk
+where event.vendor = (
+    select pk from app.vendor vendor
+    where vendor.wallet = $1
+)
+and ($2::timestamptz is null or event.event_datetime >= $2::timestamptz)
+and ($3::timestamptz is null or event.event_datetime < $3::timestamptz)
+group by event.pk, venue.pk
+order by event.event_datetime, event.pk;
+
+-- name: VendorGetTicketActivityByHour :many
+with ticket as (
+    select ticket.sold_at, ticket.checked_in_at
+    from app.ticket ticket
+    join app.event event on event.pk = ticket.event
+    where event.vendor = (
+        select pk from app.vendor vendor
+        where vendor.wallet = $1
+    )
+    and ($2::timestamptz is null or event.event_datetime >= $2::timestamptz)
+    and ($3::timestamptz is null or event.event_datetime < $3::timestamptz)
+), activity as (
+    select date_trunc('hour', ticket.sold_at) as hour, 'sold' as kind
+    from ticket where ticket.sold_at is not null
+    union all
+    select date_trunc('hour', ticket.checked_in_at) as hour, 'checked_in' as kind
+    from ticket where ticket.checked_in_at is not null
+)
+select
+    activity.hour::timestamptz as hour,
+    count(*) filter (where activity.kind = 'sold') as sold,
+    count(*) filter (where activity.kind = 'checked_in') as checked_in
+from activity
+group by activity.hour
+order by activity.hour;
+
+-- name: ClaimIdempotencyKey :one
+insert into app.idempotency_key (
+    wallet,
+    key,
+    fingerprint
+) values (
+    $1, $2, $3
+)
+on conflict (wallet, key) do update set
+    fingerprint = excluded.fingerprint,
+    status_code = null,
+    response_body = null,
+    created_at = now()
+where idempotency_key.created_at < now() - interval '24 hours'
+returning *;
+
+-- name: GetIdempotencyKey :one
+select * from app.idempotency_key where wallet = $1 and key = $2;
+
+-- name: CompleteIdempotencyKey :exec
+update app.idempotency_key set status_code = $3, response_body = $4 where wallet = $1 and key = $2;
+
+-- name: ReleaseIdempotencyKey :exec
+delete from app.idempotency_key where wallet = $1 and key = $2;
+
+-- name: SetAuditActor :exec
+select set_config('app.actor', $1::text, false);
+
+-- name: VendorGetRecordHistory :many
+select * from app.audit_log audit
+where audit.vendor = (
+    select pk from app.vendor vendor
+    where vendor.wallet = $2
+)
+and (
+    (audit.table_name = $3::text and audit.record_pk = $4::int)
+    or ($3::text = 'event' and audit.table_name = 'ticket' and audit.record_pk in (
+        select ticket.pk from app.ticket ticket
+        where ticket.event = $4::int
+    ))
+)
+and ($5::boolean = false or audit.pk < $6::int)
+order by audit.pk desc
+limit $1::int;
+
+-- name: VendorCountRecordHistory :one
+select count(*) from app.audit_log audit
+where audit.vendor = (
+    select pk from app.vendor vendor
+    where vendor.wallet = $1
+)
+and (
+    (audit.table_name = $2::text and audit.record_pk = $3::int)
+    or ($2::text = 'event' and audit.table_name = 'ticket' and audit.record_pk in (
+        select ticket.pk from app.ticket ticket
+        where ticket.event = $3::int
+    ))
+);
+
+-- name: VendorCancelEvent :one
+update app.event set cancelled_at = now()
+where event.pk = $1
+and event.vendor = (
+    select pk from app.vendor
+    where wallet = $2
+)
+and event.cancelled_at is null
+returning *;
+
+-- name: CreateNotification :exec
+insert into app.notification_outbox (
+    kind,
+    event,
+    payload
+) values (
+    $1, $2, $3
+);
+
+-- name: InsecureClaimNotifications :many
+update app.notification_outbox
+set status = 'running', claimed_at = now(), attempts = notification_outbox.attempts + 1
+where notification_outbox.pk in (
+    select pending.pk from app.notification_outbox pending
+    where pending.status = 'pending'
+    or (pending.status = 'running' and pending.claimed_at < now() - interval '15 minutes')
+    order by pending.pk
+    limit $1
+    for update skip locked
+)
+returning *;
+
+-- name: InsecureCompleteNotification :exec
+update app.notification_outbox set status = 'done', error = null, completed_at = now()
+where notification_outbox.pk = $1;
+
+-- name: InsecureFailNotification :exec
+update app.notification_outbox
+set status = case when notification_outbox.attempts >= $3::int then 'failed' else 'pending' end,
+    error = $2,
+    completed_at = case when notification_outbox.attempts >= $3::int then now() end
+where notification_outbox.pk = $1;
+
+-- name: GetNotificationRecipients :many
+select
+    buyer.wallet::text as wallet,
+    preference.email,
+    coalesce(preference.email_enabled, true)::boolean as email_enabled,
+    coalesce(preference.push_enabled, true)::boolean as push_enabled,
+    array(
+        select push_token.token from app.push_token push_token
+        where push_token.wallet = buyer.wallet
+        order by push_token.token
+    )::text[] as push_tokens
+from (
+    select distinct ticket.buyer as wallet from app.ticket ticket
+    where ticket.event = $1
+    and ticket.buyer is not null
+) buyer
+left join app.notification_preference preference on preference.wallet = buyer.wallet
+order by buyer.wallet;
+
+-- name: InsecureClaimNotificationDelivery :one
+insert into app.notification_delivery (
+    notification,
+    wallet,
+    channel
+) values (
+    $1, $2, $3
+)
+on conflict (notification, wallet, channel) do update set
+    status = 'sending',
+    error = null,
+    updated_at = now()
+where notification_delivery.status = 'failed'
+or (notification_delivery.status = 'sending' and notification_delivery.updated_at < now() - interval '15 minutes')
+returning pk;
+
+-- name: InsecureFinishNotificationDelivery :exec
+update app.notification_delivery set status = $2, error = $3, updated_at = now()
+where notification_delivery.pk = $1;
+
+-- name: InsecureDeletePushToken :exec
+delete from app.push_token where token = $1;
+
+-- name: UserGetNotificationPreference :one
+select * from app.notification_preference where wallet = $1;
+
+-- name: UserUpsertNotificationPreference :one
+insert into app.notification_preference (
+    wallet,
+    email,
+    email_enabled,
+    push_enabled
+) values (
+    $1, $2, $3, $4
+)
+on conflict (wallet) do update set
+    email = excluded.email,
+    email_enabled = excluded.email_enabled,
+    push_enabled = excluded.push_enabled,
+    updated_at = now()
+returning *;
+
+-- name: UserRegisterPushToken :exec
+insert into app.push_token (
+    token,
+    wallet
+) values (
+    $1, $2
+)
+on conflict (token) do update set
+    wallet = excluded.wallet,
+    created_at = now();
+
+-- name: UserDeletePushToken :exec
+delete from app.push_token where token = $1 and wallet = $2;
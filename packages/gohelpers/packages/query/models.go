@@ -9,6 +9,28 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type AppAdmin struct {
+	Wallet string
+}
+
+type AppAuditLog struct {
+	Pk        int32
+	TableName string
+	RecordPk  int32
+	Vendor    pgtype.Int4
+	Action    string
+	Actor     pgtype.Text
+	Before    []byte
+	After     []byte
+	ChangedAt pgtype.Timestamptz
+}
+
+type AppDeletedPhoto struct {
+	Pk        int32
+	Url       string
+	DeletedAt pgtype.Timestamptz
+}
+
 type AppEvent struct {
 	Pk              int32
 	ID              uuid.UUID
@@ -17,21 +39,125 @@ type AppEvent struct {
 	Name            string
 	Type            string
 	EventDatetime   pgtype.Timestamptz
+	EndDatetime     pgtype.Timestamptz
 	Description     string
 	Disclaimer      pgtype.Text
 	Basecost        float64
 	NumUnique       int32
 	NumGa           int32
 	Photo           pgtype.Text
+	PhotoThumbnail  pgtype.Text
+	PhotoCard       pgtype.Text
 	TransactionHash pgtype.Text
+	Version         int32
+	UpdatedAt       pgtype.Timestamptz
+	CancelledAt     pgtype.Timestamptz
+}
+
+type AppEventSearch struct {
+	Event    int32
+	Document interface{}
+}
+
+type AppIdempotencyKey struct {
+	Wallet       string
+	Key          string
+	Fingerprint  string
+	StatusCode   pgtype.Int4
+	ResponseBody pgtype.Text
+	CreatedAt    pgtype.Timestamptz
+}
+
+type AppMedia struct {
+	Pk               int32
+	ID               uuid.UUID
+	Vendor           int32
+	Event            pgtype.Int4
+	Venue            pgtype.Int4
+	Position         int32
+	Caption          pgtype.Text
+	AltText          pgtype.Text
+	Photo            pgtype.Text
+	PhotoThumbnail   pgtype.Text
+	PhotoCard        pgtype.Text
+	IsCover          bool
+	CreatedAt        pgtype.Timestamptz
+	Status           string
+	ModerationReason pgtype.Text
+	PhotoHash        pgtype.Int8
+	ReviewedBy       pgtype.Text
+	ReviewedAt       pgtype.Timestamptz
+}
+
+type AppNotificationDelivery struct {
+	Pk           int32
+	Notification int32
+	Wallet       string
+	Channel      string
+	Status       string
+	Error        pgtype.Text
+	CreatedAt    pgtype.Timestamptz
+	UpdatedAt    pgtype.Timestamptz
+}
+
+type AppNotificationOutbox struct {
+	Pk          int32
+	Kind        string
+	Event       int32
+	Payload     []byte
+	Status      string
+	Attempts    int32
+	Error       pgtype.Text
+	CreatedAt   pgtype.Timestamptz
+	ClaimedAt   pgtype.Timestamptz
+	CompletedAt pgtype.Timestamptz
+}
+
+type AppNotificationPreference struct {
+	Wallet       string
+	Email        pgtype.Text
+	EmailEnabled bool
+	PushEnabled  bool
+	UpdatedAt    pgtype.Timestamptz
+}
+
+type AppPhotoBlocklist struct {
+	Hash      int64
+	Reason    pgtype.Text
+	CreatedAt pgtype.Timestamptz
+}
+
+type AppPushToken struct {
+	Token     string
+	Wallet    string
+	CreatedAt pgtype.Timestamptz
 }
 
 type AppTicket struct {
-	Pk        int32
-	Contract  string
-	TicketID  int32
-	CheckedIn bool
-	Event     int32
+	Pk          int32
+	Contract    string
+	TicketID    int32
+	CheckedIn   bool
+	Event       int32
+	CreatedAt   pgtype.Timestamptz
+	SoldAt      pgtype.Timestamptz
+	Buyer       pgtype.Text
+	PurchaseTx  pgtype.Text
+	CheckedInAt pgtype.Timestamptz
+}
+
+type AppTicketExport struct {
+	Pk          int32
+	ID          uuid.UUID
+	Event       int32
+	Wallet      string
+	Format      string
+	Status      string
+	ObjectKey   pgtype.Text
+	Error       pgtype.Text
+	CreatedAt   pgtype.Timestamptz
+	ClaimedAt   pgtype.Timestamptz
+	CompletedAt pgtype.Timestamptz
 }
 
 type AppUser struct {
@@ -48,18 +174,29 @@ type AppVendor struct {
 }
 
 type AppVenue struct {
-	Pk            int32
-	ID            uuid.UUID
-	Vendor        int32
-	Name          string
-	StreetAddress string
-	Zip           string
-	City          string
-	StateCode     string
-	StateName     string
-	CountryCode   string
-	CountryName   string
-	NumUnique     int32
-	NumGa         int32
-	Photo         pgtype.Text
+	Pk             int32
+	ID             uuid.UUID
+	Vendor         int32
+	Name           string
+	StreetAddress  string
+	Zip            string
+	City           string
+	StateCode      string
+	StateName      string
+	CountryCode    string
+	CountryName    string
+	NumUnique      int32
+	NumGa          int32
+	Photo          pgtype.Text
+	PhotoThumbnail pgtype.Text
+	PhotoCard      pgtype.Text
+	Latitude       pgtype.Float8
+	Longitude      pgtype.Float8
+	Version        int32
+	UpdatedAt      pgtype.Timestamptz
+}
+
+type AppVenueSearch struct {
+	Venue    int32
+	Document interface{}
 }
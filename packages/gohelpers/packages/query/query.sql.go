@@ -19,7 +19,7 @@ insert into app.ticket (
     ticket_id
 ) values (
     $1, $2, $3
-) returning pk, contract, ticket_id, checked_in, event
+) returning pk, contract, ticket_id, checked_in, event, created_at, sold_at, buyer, purchase_tx, checked_in_at
 `
 
 type AddTicketParams struct {
@@ -37,6 +37,181 @@ func (q *Queries) AddTicket(ctx context.Context, arg AddTicketParams) (AppTicket
 		&i.TicketID,
 		&i.CheckedIn,
 		&i.Event,
+		&i.CreatedAt,
+		&i.SoldAt,
+		&i.Buyer,
+		&i.PurchaseTx,
+		&i.CheckedInAt,
+	)
+	return i, err
+}
+
+const adminBlockPhotoHash = `-- name: AdminBlockPhotoHash :exec
+insert into app.photo_blocklist (hash, reason)
+values ($1, $2)
+on conflict (hash) do nothing
+`
+
+type AdminBlockPhotoHashParams struct {
+	Hash   int64
+	Reason pgtype.Text
+}
+
+func (q *Queries) AdminBlockPhotoHash(ctx context.Context, arg AdminBlockPhotoHashParams) error {
+	_, err := q.db.Exec(ctx, adminBlockPhotoHash, arg.Hash, arg.Reason)
+	return err
+}
+
+const adminCountMediaByStatus = `-- name: AdminCountMediaByStatus :one
+select count(*) from app.media
+where media.status = $1
+`
+
+func (q *Queries) AdminCountMediaByStatus(ctx context.Context, status string) (int64, error) {
+	row := q.db.QueryRow(ctx, adminCountMediaByStatus, status)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const adminGetMediaByStatus = `-- name: AdminGetMediaByStatus :many
+select pk, id, vendor, event, venue, position, caption, alt_text, photo, photo_thumbnail, photo_card, is_cover, created_at, status, moderation_reason, photo_hash, reviewed_by, reviewed_at from app.media
+where media.status = $1
+and ($2::boolean = false or media.pk > $3::int)
+order by media.pk
+limit $4::int
+`
+
+type AdminGetMediaByStatusParams struct {
+	Status  string
+	Column2 bool
+	Column3 int32
+	Column4 int32
+}
+
+func (q *Queries) AdminGetMediaByStatus(ctx context.Context, arg AdminGetMediaByStatusParams) ([]AppMedia, error) {
+	rows, err := q.db.Query(ctx, adminGetMediaByStatus,
+		arg.Status,
+		arg.Column2,
+		arg.Column3,
+		arg.Column4,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []AppMedia
+	for rows.Next() {
+		var i AppMedia
+		if err := rows.Scan(
+			&i.Pk,
+			&i.ID,
+			&i.Vendor,
+			&i.Event,
+			&i.Venue,
+			&i.Position,
+			&i.Caption,
+			&i.AltText,
+			&i.Photo,
+			&i.PhotoThumbnail,
+			&i.PhotoCard,
+			&i.IsCover,
+			&i.CreatedAt,
+			&i.Status,
+			&i.ModerationReason,
+			&i.PhotoHash,
+			&i.ReviewedBy,
+			&i.ReviewedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const adminGetMediaByUuid = `-- name: AdminGetMediaByUuid :one
+select pk, id, vendor, event, venue, position, caption, alt_text, photo, photo_thumbnail, photo_card, is_cover, created_at, status, moderation_reason, photo_hash, reviewed_by, reviewed_at from app.media
+where media.id = $1
+`
+
+func (q *Queries) AdminGetMediaByUuid(ctx context.Context, id uuid.UUID) (AppMedia, error) {
+	row := q.db.QueryRow(ctx, adminGetMediaByUuid, id)
+	var i AppMedia
+	err := row.Scan(
+		&i.Pk,
+		&i.ID,
+		&i.Vendor,
+		&i.Event,
+		&i.Venue,
+		&i.Position,
+		&i.Caption,
+		&i.AltText,
+		&i.Photo,
+		&i.PhotoThumbnail,
+		&i.PhotoCard,
+		&i.IsCover,
+		&i.CreatedAt,
+		&i.Status,
+		&i.ModerationReason,
+		&i.PhotoHash,
+		&i.ReviewedBy,
+		&i.ReviewedAt,
+	)
+	return i, err
+}
+
+const adminReviewMedia = `-- name: AdminReviewMedia :one
+update app.media
+set status = $2,
+    moderation_reason = $3,
+    reviewed_by = $4,
+    reviewed_at = now(),
+    is_cover = is_cover and $2 = 'approved',
+    photo = case when $2 = 'rejected' then null else photo end,
+    photo_thumbnail = case when $2 = 'rejected' then null else photo_thumbnail end,
+    photo_card = case when $2 = 'rejected' then null else photo_card end
+where media.pk = $1
+returning pk, id, vendor, event, venue, position, caption, alt_text, photo, photo_thumbnail, photo_card, is_cover, created_at, status, moderation_reason, photo_hash, reviewed_by, reviewed_at
+`
+
+type AdminReviewMediaParams struct {
+	Pk               int32
+	Status           string
+	ModerationReason pgtype.Text
+	ReviewedBy       pgtype.Text
+}
+
+func (q *Queries) AdminReviewMedia(ctx context.Context, arg AdminReviewMediaParams) (AppMedia, error) {
+	row := q.db.QueryRow(ctx, adminReviewMedia,
+		arg.Pk,
+		arg.Status,
+		arg.ModerationReason,
+		arg.ReviewedBy,
+	)
+	var i AppMedia
+	err := row.Scan(
+		&i.Pk,
+		&i.ID,
+		&i.Vendor,
+		&i.Event,
+		&i.Venue,
+		&i.Position,
+		&i.Caption,
+		&i.AltText,
+		&i.Photo,
+		&i.PhotoThumbnail,
+		&i.PhotoCard,
+		&i.IsCover,
+		&i.CreatedAt,
+		&i.Status,
+		&i.ModerationReason,
+		&i.PhotoHash,
+		&i.ReviewedBy,
+		&i.ReviewedAt,
 	)
 	return i, err
 }
@@ -53,6 +228,81 @@ func (q *Queries) CheckVenueVendorStatus(ctx context.Context, dollar_1 int32) (i
 	return vendor, err
 }
 
+const claimIdempotencyKey = `-- name: ClaimIdempotencyKey :one
+insert into app.idempotency_key (
+    wallet,
+    key,
+    fingerprint
+) values (
+    $1, $2, $3
+)
+on conflict (wallet, key) do update set
+    fingerprint = excluded.fingerprint,
+    status_code = null,
+    response_body = null,
+    created_at = now()
+where idempotency_key.created_at < now() - interval '24 hours'
+returning wallet, key, fingerprint, status_code, response_body, created_at
+`
+
+type ClaimIdempotencyKeyParams struct {
+	Wallet      string
+	Key         string
+	Fingerprint string
+}
+
+func (q *Queries) ClaimIdempotencyKey(ctx context.Context, arg ClaimIdempotencyKeyParams) (AppIdempotencyKey, error) {
+	row := q.db.QueryRow(ctx, claimIdempotencyKey, arg.Wallet, arg.Key, arg.Fingerprint)
+	var i AppIdempotencyKey
+	err := row.Scan(
+		&i.Wallet,
+		&i.Key,
+		&i.Fingerprint,
+		&i.StatusCode,
+		&i.ResponseBody,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const clearMediaCover = `-- name: ClearMediaCover :exec
+update app.media
+set is_cover = false
+where is_cover
+and (media.event = $1 or media.venue = $2)
+`
+
+type ClearMediaCoverParams struct {
+	Event pgtype.Int4
+	Venue pgtype.Int4
+}
+
+func (q *Queries) ClearMediaCover(ctx context.Context, arg ClearMediaCoverParams) error {
+	_, err := q.db.Exec(ctx, clearMediaCover, arg.Event, arg.Venue)
+	return err
+}
+
+const completeIdempotencyKey = `-- name: CompleteIdempotencyKey :exec
+update app.idempotency_key set status_code = $3, response_body = $4 where wallet = $1 and key = $2
+`
+
+type CompleteIdempotencyKeyParams struct {
+	Wallet       string
+	Key          string
+	StatusCode   pgtype.Int4
+	ResponseBody pgtype.Text
+}
+
+func (q *Queries) CompleteIdempotencyKey(ctx context.Context, arg CompleteIdempotencyKeyParams) error {
+	_, err := q.db.Exec(ctx, completeIdempotencyKey,
+		arg.Wallet,
+		arg.Key,
+		arg.StatusCode,
+		arg.ResponseBody,
+	)
+	return err
+}
+
 const createEvent = `-- name: CreateEvent :one
 insert into app.event (
     vendor,
@@ -60,23 +310,15 @@ insert into app.event (
     name,
     type,
     event_datetime,
+    end_datetime,
     description,
     disclaimer,
     basecost,
     num_unique,
     num_ga
 ) values (
-    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
-) returning (
-    name,
-    type,
-    event_datetime,
-    description,
-    disclaimer,
-    basecost,
-    num_unique,
-    num_ga
-)
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
+) returning pk, id, vendor, venue, name, type, event_datetime, end_datetime, description, disclaimer, basecost, num_unique, num_ga, photo, photo_thumbnail, photo_card, transaction_hash, version, updated_at, cancelled_at
 `
 
 type CreateEventParams struct {
@@ -85,6 +327,7 @@ type CreateEventParams struct {
 	Name          string
 	Type          string
 	EventDatetime pgtype.Timestamptz
+	EndDatetime   pgtype.Timestamptz
 	Description   string
 	Disclaimer    pgtype.Text
 	Basecost      float64
@@ -92,22 +335,127 @@ type CreateEventParams struct {
 	NumGa         int32
 }
 
-func (q *Queries) CreateEvent(ctx context.Context, arg CreateEventParams) (interface{}, error) {
+func (q *Queries) CreateEvent(ctx context.Context, arg CreateEventParams) (AppEvent, error) {
 	row := q.db.QueryRow(ctx, createEvent,
 		arg.Vendor,
 		arg.Venue,
 		arg.Name,
 		arg.Type,
 		arg.EventDatetime,
+		arg.EndDatetime,
 		arg.Description,
 		arg.Disclaimer,
 		arg.Basecost,
 		arg.NumUnique,
 		arg.NumGa,
 	)
-	var column_1 interface{}
-	err := row.Scan(&column_1)
-	return column_1, err
+	var i AppEvent
+	err := row.Scan(
+		&i.Pk,
+		&i.ID,
+		&i.Vendor,
+		&i.Venue,
+		&i.Name,
+		&i.Type,
+		&i.EventDatetime,
+		&i.EndDatetime,
+		&i.Description,
+		&i.Disclaimer,
+		&i.Basecost,
+		&i.NumUnique,
+		&i.NumGa,
+		&i.Photo,
+		&i.PhotoThumbnail,
+		&i.PhotoCard,
+		&i.TransactionHash,
+		&i.Version,
+		&i.UpdatedAt,
+		&i.CancelledAt,
+	)
+	return i, err
+}
+
+const createMedia = `-- name: CreateMedia :one
+insert into app.media (
+    vendor,
+    event,
+    venue,
+    position,
+    caption,
+    alt_text
+) values (
+    $1,
+    $2,
+    $3,
+    (
+        select coalesce(max(position) + 1, 0) from app.media
+        where media.event = $2 or media.venue = $3
+    ),
+    $4,
+    $5
+)
+returning pk, id, vendor, event, venue, position, caption, alt_text, photo, photo_thumbnail, photo_card, is_cover, created_at, status, moderation_reason, photo_hash, reviewed_by, reviewed_at
+`
+
+type CreateMediaParams struct {
+	Vendor  int32
+	Event   pgtype.Int4
+	Venue   pgtype.Int4
+	Caption pgtype.Text
+	AltText pgtype.Text
+}
+
+func (q *Queries) CreateMedia(ctx context.Context, arg CreateMediaParams) (AppMedia, error) {
+	row := q.db.QueryRow(ctx, createMedia,
+		arg.Vendor,
+		arg.Event,
+		arg.Venue,
+		arg.Caption,
+		arg.AltText,
+	)
+	var i AppMedia
+	err := row.Scan(
+		&i.Pk,
+		&i.ID,
+		&i.Vendor,
+		&i.Event,
+		&i.Venue,
+		&i.Position,
+		&i.Caption,
+		&i.AltText,
+		&i.Photo,
+		&i.PhotoThumbnail,
+		&i.PhotoCard,
+		&i.IsCover,
+		&i.CreatedAt,
+		&i.Status,
+		&i.ModerationReason,
+		&i.PhotoHash,
+		&i.ReviewedBy,
+		&i.ReviewedAt,
+	)
+	return i, err
+}
+
+const createNotification = `-- name: CreateNotification :exec
+insert into app.notification_outbox (
+    kind,
+    event,
+    payload
+) values (
+    $1, $2, $3
+)
+`
+
+type CreateNotificationParams struct {
+	Kind    string
+	Event   int32
+	Payload []byte
+}
+
+func (q *Queries) CreateNotification(ctx context.Context, arg CreateNotificationParams) error {
+	_, err := q.db.Exec(ctx, createNotification, arg.Kind, arg.Event, arg.Payload)
+	return err
 }
 
 const createVendor = `-- name: CreateVendor :one
@@ -165,20 +513,12 @@ insert into app.venue (
     country_name,
     num_unique,
     num_ga,
-    vendor
+    vendor,
+    latitude,
+    longitude
 ) values (
-    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
-) returning (
-    name,
-    street_address,
-    zip,
-    city,
-    state_code,
-    country_code,
-    country_name,
-    num_unique,
-    num_ga
-)
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13
+) returning pk, id, vendor, name, street_address, zip, city, state_code, state_name, country_code, country_name, num_unique, num_ga, photo, photo_thumbnail, photo_card, latitude, longitude, version, updated_at
 `
 
 type CreateVenueParams struct {
@@ -193,9 +533,11 @@ type CreateVenueParams struct {
 	NumUnique     int32
 	NumGa         int32
 	Vendor        int32
+	Latitude      pgtype.Float8
+	Longitude     pgtype.Float8
 }
 
-func (q *Queries) CreateVenue(ctx context.Context, arg CreateVenueParams) (interface{}, error) {
+func (q *Queries) CreateVenue(ctx context.Context, arg CreateVenueParams) (AppVenue, error) {
 	row := q.db.QueryRow(ctx, createVenue,
 		arg.Name,
 		arg.StreetAddress,
@@ -208,14 +550,69 @@ func (q *Queries) CreateVenue(ctx context.Context, arg CreateVenueParams) (inter
 		arg.NumUnique,
 		arg.NumGa,
 		arg.Vendor,
+		arg.Latitude,
+		arg.Longitude,
+	)
+	var i AppVenue
+	err := row.Scan(
+		&i.Pk,
+		&i.ID,
+		&i.Vendor,
+		&i.Name,
+		&i.StreetAddress,
+		&i.Zip,
+		&i.City,
+		&i.StateCode,
+		&i.StateName,
+		&i.CountryCode,
+		&i.CountryName,
+		&i.NumUnique,
+		&i.NumGa,
+		&i.Photo,
+		&i.PhotoThumbnail,
+		&i.PhotoCard,
+		&i.Latitude,
+		&i.Longitude,
+		&i.Version,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const deleteMedia = `-- name: DeleteMedia :one
+delete from app.media
+where media.pk = $1
+returning pk, id, vendor, event, venue, position, caption, alt_text, photo, photo_thumbnail, photo_card, is_cover, created_at, status, moderation_reason, photo_hash, reviewed_by, reviewed_at
+`
+
+func (q *Queries) DeleteMedia(ctx context.Context, pk int32) (AppMedia, error) {
+	row := q.db.QueryRow(ctx, deleteMedia, pk)
+	var i AppMedia
+	err := row.Scan(
+		&i.Pk,
+		&i.ID,
+		&i.Vendor,
+		&i.Event,
+		&i.Venue,
+		&i.Position,
+		&i.Caption,
+		&i.AltText,
+		&i.Photo,
+		&i.PhotoThumbnail,
+		&i.PhotoCard,
+		&i.IsCover,
+		&i.CreatedAt,
+		&i.Status,
+		&i.ModerationReason,
+		&i.PhotoHash,
+		&i.ReviewedBy,
+		&i.ReviewedAt,
 	)
-	var column_1 interface{}
-	err := row.Scan(&column_1)
-	return column_1, err
+	return i, err
 }
 
 const getEventByUuid = `-- name: GetEventByUuid :one
-select pk, id, vendor, venue, name, type, event_datetime, description, disclaimer, basecost, num_unique, num_ga, photo, transaction_hash from app.event event
+select pk, id, vendor, venue, name, type, event_datetime, end_datetime, description, disclaimer, basecost, num_unique, num_ga, photo, photo_thumbnail, photo_card, transaction_hash, version, updated_at, cancelled_at from app.event event
 where event.id = $1
 limit 1
 `
@@ -231,58 +628,114 @@ func (q *Queries) GetEventByUuid(ctx context.Context, id uuid.UUID) (AppEvent, e
 		&i.Name,
 		&i.Type,
 		&i.EventDatetime,
+		&i.EndDatetime,
 		&i.Description,
 		&i.Disclaimer,
 		&i.Basecost,
 		&i.NumUnique,
 		&i.NumGa,
 		&i.Photo,
+		&i.PhotoThumbnail,
+		&i.PhotoCard,
 		&i.TransactionHash,
+		&i.Version,
+		&i.UpdatedAt,
+		&i.CancelledAt,
 	)
 	return i, err
 }
 
-const getTicket = `-- name: GetTicket :one
-select pk, contract, ticket_id, checked_in, event from app.ticket where event = $1 and ticket_id = $2 limit 1
+const getEventTicketSummary = `-- name: GetEventTicketSummary :one
+select
+    count(*) as minted,
+    count(*) filter (where ticket.sold_at is not null or ticket.checked_in) as sold,
+    coalesce(max(ticket.ticket_id), 0)::int as max_ticket_id,
+    coalesce(max(ticket.contract), '')::text as contract
+from app.ticket ticket
+where ticket.event = $1
 `
 
-type GetTicketParams struct {
-	Event    int32
-	TicketID int32
+type GetEventTicketSummaryRow struct {
+	Minted      int64
+	Sold        int64
+	MaxTicketID int32
+	Contract    string
 }
 
-func (q *Queries) GetTicket(ctx context.Context, arg GetTicketParams) (AppTicket, error) {
-	row := q.db.QueryRow(ctx, getTicket, arg.Event, arg.TicketID)
-	var i AppTicket
+func (q *Queries) GetEventTicketSummary(ctx context.Context, event int32) (GetEventTicketSummaryRow, error) {
+	row := q.db.QueryRow(ctx, getEventTicketSummary, event)
+	var i GetEventTicketSummaryRow
 	err := row.Scan(
-		&i.Pk,
+		&i.Minted,
+		&i.Sold,
+		&i.MaxTicketID,
 		&i.Contract,
-		&i.TicketID,
-		&i.CheckedIn,
-		&i.Event,
 	)
 	return i, err
 }
 
-const getTicketsByEvent = `-- name: GetTicketsByEvent :many
-select pk, contract, ticket_id, checked_in, event from app.ticket where event = $1
+const getIdempotencyKey = `-- name: GetIdempotencyKey :one
+select wallet, key, fingerprint, status_code, response_body, created_at from app.idempotency_key where wallet = $1 and key = $2
 `
 
-func (q *Queries) GetTicketsByEvent(ctx context.Context, event int32) ([]AppTicket, error) {
-	rows, err := q.db.Query(ctx, getTicketsByEvent, event)
+type GetIdempotencyKeyParams struct {
+	Wallet string
+	Key    string
+}
+
+func (q *Queries) GetIdempotencyKey(ctx context.Context, arg GetIdempotencyKeyParams) (AppIdempotencyKey, error) {
+	row := q.db.QueryRow(ctx, getIdempotencyKey, arg.Wallet, arg.Key)
+	var i AppIdempotencyKey
+	err := row.Scan(
+		&i.Wallet,
+		&i.Key,
+		&i.Fingerprint,
+		&i.StatusCode,
+		&i.ResponseBody,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getMedia = `-- name: GetMedia :many
+select pk, id, vendor, event, venue, position, caption, alt_text, photo, photo_thumbnail, photo_card, is_cover, created_at, status, moderation_reason, photo_hash, reviewed_by, reviewed_at from app.media
+where media.event = $1 or media.venue = $2
+order by position, pk
+`
+
+type GetMediaParams struct {
+	Event pgtype.Int4
+	Venue pgtype.Int4
+}
+
+func (q *Queries) GetMedia(ctx context.Context, arg GetMediaParams) ([]AppMedia, error) {
+	rows, err := q.db.Query(ctx, getMedia, arg.Event, arg.Venue)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []AppTicket
+	var items []AppMedia
 	for rows.Next() {
-		var i AppTicket
+		var i AppMedia
 		if err := rows.Scan(
 			&i.Pk,
-			&i.Contract,
-			&i.TicketID,
-			&i.CheckedIn,
+			&i.ID,
+			&i.Vendor,
 			&i.Event,
+			&i.Venue,
+			&i.Position,
+			&i.Caption,
+			&i.AltText,
+			&i.Photo,
+			&i.PhotoThumbnail,
+			&i.PhotoCard,
+			&i.IsCover,
+			&i.CreatedAt,
+			&i.Status,
+			&i.ModerationReason,
+			&i.PhotoHash,
+			&i.ReviewedBy,
+			&i.ReviewedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -294,27 +747,196 @@ func (q *Queries) GetTicketsByEvent(ctx context.Context, event int32) ([]AppTick
 	return items, nil
 }
 
-const getVendorByUuid = `-- name: GetVendorByUuid :one
-select pk, id, wallet, name from app.vendor where id = $1 limit 1
+const getNotificationRecipients = `-- name: GetNotificationRecipients :many
+select
+    buyer.wallet::text as wallet,
+    preference.email,
+    coalesce(preference.email_enabled, true)::boolean as email_enabled,
+    coalesce(preference.push_enabled, true)::boolean as push_enabled,
+    array(
+        select push_token.token from app.push_token push_token
+        where push_token.wallet = buyer.wallet
+        order by push_token.token
+    )::text[] as push_tokens
+from (
+    select distinct ticket.buyer as wallet from app.ticket ticket
+    where ticket.event = $1
+    and ticket.buyer is not null
+) buyer
+left join app.notification_preference preference on preference.wallet = buyer.wallet
+order by buyer.wallet
 `
 
-func (q *Queries) GetVendorByUuid(ctx context.Context, id uuid.UUID) (AppVendor, error) {
-	row := q.db.QueryRow(ctx, getVendorByUuid, id)
-	var i AppVendor
-	err := row.Scan(
-		&i.Pk,
-		&i.ID,
-		&i.Wallet,
-		&i.Name,
-	)
-	return i, err
+type GetNotificationRecipientsRow struct {
+	Wallet       string
+	Email        pgtype.Text
+	EmailEnabled bool
+	PushEnabled  bool
+	PushTokens   []string
 }
 
-const getVendorByWallet = `-- name: GetVendorByWallet :one
-select pk, id, wallet, name from app.vendor where wallet = $1 limit 1
-`
-
-func (q *Queries) GetVendorByWallet(ctx context.Context, wallet string) (AppVendor, error) {
+func (q *Queries) GetNotificationRecipients(ctx context.Context, event int32) ([]GetNotificationRecipientsRow, error) {
+	rows, err := q.db.Query(ctx, getNotificationRecipients, event)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetNotificationRecipientsRow
+	for rows.Next() {
+		var i GetNotificationRecipientsRow
+		if err := rows.Scan(
+			&i.Wallet,
+			&i.Email,
+			&i.EmailEnabled,
+			&i.PushEnabled,
+			&i.PushTokens,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTicket = `-- name: GetTicket :one
+select pk, contract, ticket_id, checked_in, event, created_at, sold_at, buyer, purchase_tx, checked_in_at from app.ticket where event = $1 and ticket_id = $2 limit 1
+`
+
+type GetTicketParams struct {
+	Event    int32
+	TicketID int32
+}
+
+func (q *Queries) GetTicket(ctx context.Context, arg GetTicketParams) (AppTicket, error) {
+	row := q.db.QueryRow(ctx, getTicket, arg.Event, arg.TicketID)
+	var i AppTicket
+	err := row.Scan(
+		&i.Pk,
+		&i.Contract,
+		&i.TicketID,
+		&i.CheckedIn,
+		&i.Event,
+		&i.CreatedAt,
+		&i.SoldAt,
+		&i.Buyer,
+		&i.PurchaseTx,
+		&i.CheckedInAt,
+	)
+	return i, err
+}
+
+const getTicketExportRows = `-- name: GetTicketExportRows :many
+select
+    ticket.ticket_id,
+    ticket.contract,
+    (case
+        when row_number() over (order by ticket.ticket_id) <= event.num_unique then 'unique'
+        else 'ga'
+    end)::text as tier,
+    ticket.buyer,
+    ticket.checked_in,
+    ticket.checked_in_at
+from app.ticket ticket
+join app.event event on event.pk = ticket.event
+where ticket.event = $1
+order by ticket.ticket_id
+`
+
+type GetTicketExportRowsRow struct {
+	TicketID    int32
+	Contract    string
+	Tier        string
+	Buyer       pgtype.Text
+	CheckedIn   bool
+	CheckedInAt pgtype.Timestamptz
+}
+
+func (q *Queries) GetTicketExportRows(ctx context.Context, event int32) ([]GetTicketExportRowsRow, error) {
+	rows, err := q.db.Query(ctx, getTicketExportRows, event)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetTicketExportRowsRow
+	for rows.Next() {
+		var i GetTicketExportRowsRow
+		if err := rows.Scan(
+			&i.TicketID,
+			&i.Contract,
+			&i.Tier,
+			&i.Buyer,
+			&i.CheckedIn,
+			&i.CheckedInAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTicketsByEvent = `-- name: GetTicketsByEvent :many
+select pk, contract, ticket_id, checked_in, event, created_at, sold_at, buyer, purchase_tx, checked_in_at from app.ticket where event = $1
+`
+
+func (q *Queries) GetTicketsByEvent(ctx context.Context, event int32) ([]AppTicket, error) {
+	rows, err := q.db.Query(ctx, getTicketsByEvent, event)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []AppTicket
+	for rows.Next() {
+		var i AppTicket
+		if err := rows.Scan(
+			&i.Pk,
+			&i.Contract,
+			&i.TicketID,
+			&i.CheckedIn,
+			&i.Event,
+			&i.CreatedAt,
+			&i.SoldAt,
+			&i.Buyer,
+			&i.PurchaseTx,
+			&i.CheckedInAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getVendorByUuid = `-- name: GetVendorByUuid :one
+select pk, id, wallet, name from app.vendor where id = $1 limit 1
+`
+
+func (q *Queries) GetVendorByUuid(ctx context.Context, id uuid.UUID) (AppVendor, error) {
+	row := q.db.QueryRow(ctx, getVendorByUuid, id)
+	var i AppVendor
+	err := row.Scan(
+		&i.Pk,
+		&i.ID,
+		&i.Wallet,
+		&i.Name,
+	)
+	return i, err
+}
+
+const getVendorByWallet = `-- name: GetVendorByWallet :one
+select pk, id, wallet, name from app.vendor where wallet = $1 limit 1
+`
+
+func (q *Queries) GetVendorByWallet(ctx context.Context, wallet string) (AppVendor, error) {
 	row := q.db.QueryRow(ctx, getVendorByWallet, wallet)
 	var i AppVendor
 	err := row.Scan(
@@ -326,11 +948,565 @@ func (q *Queries) GetVendorByWallet(ctx context.Context, wallet string) (AppVend
 	return i, err
 }
 
+const insecureAttachMedia = `-- name: InsecureAttachMedia :one
+update app.media
+set photo = $2, photo_thumbnail = $3, photo_card = $4,
+    status = $5, moderation_reason = $6, photo_hash = $7
+where media.id = $1
+and media.status = 'pending'
+and media.photo is null
+returning pk, id, vendor, event, venue, position, caption, alt_text, photo, photo_thumbnail, photo_card, is_cover, created_at, status, moderation_reason, photo_hash, reviewed_by, reviewed_at
+`
+
+type InsecureAttachMediaParams struct {
+	ID               uuid.UUID
+	Photo            pgtype.Text
+	PhotoThumbnail   pgtype.Text
+	PhotoCard        pgtype.Text
+	Status           string
+	ModerationReason pgtype.Text
+	PhotoHash        pgtype.Int8
+}
+
+func (q *Queries) InsecureAttachMedia(ctx context.Context, arg InsecureAttachMediaParams) (AppMedia, error) {
+	row := q.db.QueryRow(ctx, insecureAttachMedia,
+		arg.ID,
+		arg.Photo,
+		arg.PhotoThumbnail,
+		arg.PhotoCard,
+		arg.Status,
+		arg.ModerationReason,
+		arg.PhotoHash,
+	)
+	var i AppMedia
+	err := row.Scan(
+		&i.Pk,
+		&i.ID,
+		&i.Vendor,
+		&i.Event,
+		&i.Venue,
+		&i.Position,
+		&i.Caption,
+		&i.AltText,
+		&i.Photo,
+		&i.PhotoThumbnail,
+		&i.PhotoCard,
+		&i.IsCover,
+		&i.CreatedAt,
+		&i.Status,
+		&i.ModerationReason,
+		&i.PhotoHash,
+		&i.ReviewedBy,
+		&i.ReviewedAt,
+	)
+	return i, err
+}
+
+const insecureClaimMediaCover = `-- name: InsecureClaimMediaCover :one
+update app.media
+set is_cover = true
+where media.pk = $1
+and not exists (
+    select 1 from app.media cover
+    where cover.is_cover
+    and (cover.event = media.event or cover.venue = media.venue)
+)
+returning pk, id, vendor, event, venue, position, caption, alt_text, photo, photo_thumbnail, photo_card, is_cover, created_at, status, moderation_reason, photo_hash, reviewed_by, reviewed_at
+`
+
+func (q *Queries) InsecureClaimMediaCover(ctx context.Context, pk int32) (AppMedia, error) {
+	row := q.db.QueryRow(ctx, insecureClaimMediaCover, pk)
+	var i AppMedia
+	err := row.Scan(
+		&i.Pk,
+		&i.ID,
+		&i.Vendor,
+		&i.Event,
+		&i.Venue,
+		&i.Position,
+		&i.Caption,
+		&i.AltText,
+		&i.Photo,
+		&i.PhotoThumbnail,
+		&i.PhotoCard,
+		&i.IsCover,
+		&i.CreatedAt,
+		&i.Status,
+		&i.ModerationReason,
+		&i.PhotoHash,
+		&i.ReviewedBy,
+		&i.ReviewedAt,
+	)
+	return i, err
+}
+
+const insecureClaimNotificationDelivery = `-- name: InsecureClaimNotificationDelivery :one
+insert into app.notification_delivery (
+    notification,
+    wallet,
+    channel
+) values (
+    $1, $2, $3
+)
+on conflict (notification, wallet, channel) do update set
+    status = 'sending',
+    error = null,
+    updated_at = now()
+where notification_delivery.status = 'failed'
+or (notification_delivery.status = 'sending' and notification_delivery.updated_at < now() - interval '15 minutes')
+returning pk
+`
+
+type InsecureClaimNotificationDeliveryParams struct {
+	Notification int32
+	Wallet       string
+	Channel      string
+}
+
+func (q *Queries) InsecureClaimNotificationDelivery(ctx context.Context, arg InsecureClaimNotificationDeliveryParams) (int32, error) {
+	row := q.db.QueryRow(ctx, insecureClaimNotificationDelivery, arg.Notification, arg.Wallet, arg.Channel)
+	var pk int32
+	err := row.Scan(&pk)
+	return pk, err
+}
+
+const insecureClaimNotifications = `-- name: InsecureClaimNotifications :many
+update app.notification_outbox
+set status = 'running', claimed_at = now(), attempts = notification_outbox.attempts + 1
+where notification_outbox.pk in (
+    select pending.pk from app.notification_outbox pending
+    where pending.status = 'pending'
+    or (pending.status = 'running' and pending.claimed_at < now() - interval '15 minutes')
+    order by pending.pk
+    limit $1
+    for update skip locked
+)
+returning pk, kind, event, payload, status, attempts, error, created_at, claimed_at, completed_at
+`
+
+func (q *Queries) InsecureClaimNotifications(ctx context.Context, limit int32) ([]AppNotificationOutbox, error) {
+	rows, err := q.db.Query(ctx, insecureClaimNotifications, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []AppNotificationOutbox
+	for rows.Next() {
+		var i AppNotificationOutbox
+		if err := rows.Scan(
+			&i.Pk,
+			&i.Kind,
+			&i.Event,
+			&i.Payload,
+			&i.Status,
+			&i.Attempts,
+			&i.Error,
+			&i.CreatedAt,
+			&i.ClaimedAt,
+			&i.CompletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insecureClaimTicketExports = `-- name: InsecureClaimTicketExports :many
+update app.ticket_export set status = 'running', claimed_at = now()
+where ticket_export.pk in (
+    select pending.pk from app.ticket_export pending
+    where pending.status = 'pending'
+    or (pending.status = 'running' and pending.claimed_at < now() - interval '15 minutes')
+    order by pending.pk
+    limit $1
+    for update skip locked
+)
+returning pk, id, event, wallet, format, status, object_key, error, created_at, claimed_at, completed_at
+`
+
+func (q *Queries) InsecureClaimTicketExports(ctx context.Context, limit int32) ([]AppTicketExport, error) {
+	rows, err := q.db.Query(ctx, insecureClaimTicketExports, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []AppTicketExport
+	for rows.Next() {
+		var i AppTicketExport
+		if err := rows.Scan(
+			&i.Pk,
+			&i.ID,
+			&i.Event,
+			&i.Wallet,
+			&i.Format,
+			&i.Status,
+			&i.ObjectKey,
+			&i.Error,
+			&i.CreatedAt,
+			&i.ClaimedAt,
+			&i.CompletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insecureCompleteNotification = `-- name: InsecureCompleteNotification :exec
+update app.notification_outbox set status = 'done', error = null, completed_at = now()
+where notification_outbox.pk = $1
+`
+
+func (q *Queries) InsecureCompleteNotification(ctx context.Context, pk int32) error {
+	_, err := q.db.Exec(ctx, insecureCompleteNotification, pk)
+	return err
+}
+
+const insecureCompleteTicketExport = `-- name: InsecureCompleteTicketExport :exec
+update app.ticket_export set status = 'done', object_key = $2, completed_at = now()
+where ticket_export.pk = $1
+`
+
+type InsecureCompleteTicketExportParams struct {
+	Pk        int32
+	ObjectKey pgtype.Text
+}
+
+func (q *Queries) InsecureCompleteTicketExport(ctx context.Context, arg InsecureCompleteTicketExportParams) error {
+	_, err := q.db.Exec(ctx, insecureCompleteTicketExport, arg.Pk, arg.ObjectKey)
+	return err
+}
+
+const insecureDeleteDeletedPhotos = `-- name: InsecureDeleteDeletedPhotos :exec
+delete from app.deleted_photo
+where deleted_photo.pk = any($1::int[])
+`
+
+func (q *Queries) InsecureDeleteDeletedPhotos(ctx context.Context, dollar_1 []int32) error {
+	_, err := q.db.Exec(ctx, insecureDeleteDeletedPhotos, dollar_1)
+	return err
+}
+
+const insecureDeleteMedia = `-- name: InsecureDeleteMedia :exec
+delete from app.media
+where media.id = $1
+`
+
+func (q *Queries) InsecureDeleteMedia(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.Exec(ctx, insecureDeleteMedia, id)
+	return err
+}
+
+const insecureDeletePushToken = `-- name: InsecureDeletePushToken :exec
+delete from app.push_token where token = $1
+`
+
+func (q *Queries) InsecureDeletePushToken(ctx context.Context, token string) error {
+	_, err := q.db.Exec(ctx, insecureDeletePushToken, token)
+	return err
+}
+
+const insecureExpireStaleUploads = `-- name: InsecureExpireStaleUploads :execrows
+delete from app.media
+where media.status = 'pending'
+and media.photo is null
+and media.created_at < $1::timestamptz
+`
+
+func (q *Queries) InsecureExpireStaleUploads(ctx context.Context, dollar_1 pgtype.Timestamptz) (int64, error) {
+	result, err := q.db.Exec(ctx, insecureExpireStaleUploads, dollar_1)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const insecureExpireTicketExport = `-- name: InsecureExpireTicketExport :exec
+update app.ticket_export set status = 'expired', object_key = null
+where ticket_export.pk = $1
+`
+
+func (q *Queries) InsecureExpireTicketExport(ctx context.Context, pk int32) error {
+	_, err := q.db.Exec(ctx, insecureExpireTicketExport, pk)
+	return err
+}
+
+const insecureFailNotification = `-- name: InsecureFailNotification :exec
+update app.notification_outbox
+set status = case when notification_outbox.attempts >= $3::int then 'failed' else 'pending' end,
+    error = $2,
+    completed_at = case when notification_outbox.attempts >= $3::int then now() end
+where notification_outbox.pk = $1
+`
+
+type InsecureFailNotificationParams struct {
+	Pk      int32
+	Error   pgtype.Text
+	Column3 int32
+}
+
+func (q *Queries) InsecureFailNotification(ctx context.Context, arg InsecureFailNotificationParams) error {
+	_, err := q.db.Exec(ctx, insecureFailNotification, arg.Pk, arg.Error, arg.Column3)
+	return err
+}
+
+const insecureFailTicketExport = `-- name: InsecureFailTicketExport :exec
+update app.ticket_export set status = 'failed', error = $2, completed_at = now()
+where ticket_export.pk = $1
+`
+
+type InsecureFailTicketExportParams struct {
+	Pk    int32
+	Error pgtype.Text
+}
+
+func (q *Queries) InsecureFailTicketExport(ctx context.Context, arg InsecureFailTicketExportParams) error {
+	_, err := q.db.Exec(ctx, insecureFailTicketExport, arg.Pk, arg.Error)
+	return err
+}
+
+const insecureFinishNotificationDelivery = `-- name: InsecureFinishNotificationDelivery :exec
+update app.notification_delivery set status = $2, error = $3, updated_at = now()
+where notification_delivery.pk = $1
+`
+
+type InsecureFinishNotificationDeliveryParams struct {
+	Pk     int32
+	Status string
+	Error  pgtype.Text
+}
+
+func (q *Queries) InsecureFinishNotificationDelivery(ctx context.Context, arg InsecureFinishNotificationDeliveryParams) error {
+	_, err := q.db.Exec(ctx, insecureFinishNotificationDelivery, arg.Pk, arg.Status, arg.Error)
+	return err
+}
+
+const insecureGetDeletedPhotos = `-- name: InsecureGetDeletedPhotos :many
+select deleted.pk, deleted.url, (
+    exists (
+        select 1 from app.media media
+        where deleted.url in (media.photo, media.photo_thumbnail, media.photo_card)
+    )
+    or exists (
+        select 1 from app.event event
+        where deleted.url in (event.photo, event.photo_thumbnail, event.photo_card)
+    )
+    or exists (
+        select 1 from app.venue venue
+        where deleted.url in (venue.photo, venue.photo_thumbnail, venue.photo_card)
+    )
+)::boolean referenced
+from app.deleted_photo deleted
+order by deleted.pk
+limit $1
+`
+
+type InsecureGetDeletedPhotosRow struct {
+	Pk         int32
+	Url        string
+	Referenced bool
+}
+
+func (q *Queries) InsecureGetDeletedPhotos(ctx context.Context, limit int32) ([]InsecureGetDeletedPhotosRow, error) {
+	rows, err := q.db.Query(ctx, insecureGetDeletedPhotos, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []InsecureGetDeletedPhotosRow
+	for rows.Next() {
+		var i InsecureGetDeletedPhotosRow
+		if err := rows.Scan(&i.Pk, &i.Url, &i.Referenced); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insecureGetExpiredTicketExports = `-- name: InsecureGetExpiredTicketExports :many
+select pk, id, event, wallet, format, status, object_key, error, created_at, claimed_at, completed_at from app.ticket_export
+where ticket_export.status = 'done'
+and ticket_export.completed_at < $1::timestamptz
+order by ticket_export.pk
+`
+
+func (q *Queries) InsecureGetExpiredTicketExports(ctx context.Context, dollar_1 pgtype.Timestamptz) ([]AppTicketExport, error) {
+	rows, err := q.db.Query(ctx, insecureGetExpiredTicketExports, dollar_1)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []AppTicketExport
+	for rows.Next() {
+		var i AppTicketExport
+		if err := rows.Scan(
+			&i.Pk,
+			&i.ID,
+			&i.Event,
+			&i.Wallet,
+			&i.Format,
+			&i.Status,
+			&i.ObjectKey,
+			&i.Error,
+			&i.CreatedAt,
+			&i.ClaimedAt,
+			&i.CompletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insecureGetMediaByUuid = `-- name: InsecureGetMediaByUuid :one
+select pk, id, vendor, event, venue, position, caption, alt_text, photo, photo_thumbnail, photo_card, is_cover, created_at, status, moderation_reason, photo_hash, reviewed_by, reviewed_at from app.media
+where media.id = $1
+`
+
+func (q *Queries) InsecureGetMediaByUuid(ctx context.Context, id uuid.UUID) (AppMedia, error) {
+	row := q.db.QueryRow(ctx, insecureGetMediaByUuid, id)
+	var i AppMedia
+	err := row.Scan(
+		&i.Pk,
+		&i.ID,
+		&i.Vendor,
+		&i.Event,
+		&i.Venue,
+		&i.Position,
+		&i.Caption,
+		&i.AltText,
+		&i.Photo,
+		&i.PhotoThumbnail,
+		&i.PhotoCard,
+		&i.IsCover,
+		&i.CreatedAt,
+		&i.Status,
+		&i.ModerationReason,
+		&i.PhotoHash,
+		&i.ReviewedBy,
+		&i.ReviewedAt,
+	)
+	return i, err
+}
+
+const insecureGetPhotoBlocklist = `-- name: InsecureGetPhotoBlocklist :many
+select hash from app.photo_blocklist
+`
+
+func (q *Queries) InsecureGetPhotoBlocklist(ctx context.Context) ([]int64, error) {
+	rows, err := q.db.Query(ctx, insecureGetPhotoBlocklist)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int64
+	for rows.Next() {
+		var hash int64
+		if err := rows.Scan(&hash); err != nil {
+			return nil, err
+		}
+		items = append(items, hash)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insecureGetPhotoUrls = `-- name: InsecureGetPhotoUrls :many
+select media.photo::text from app.media media where media.photo is not null
+union select media.photo_thumbnail::text from app.media media where media.photo_thumbnail is not null
+union select media.photo_card::text from app.media media where media.photo_card is not null
+union select event.photo::text from app.event event where event.photo is not null
+union select event.photo_thumbnail::text from app.event event where event.photo_thumbnail is not null
+union select event.photo_card::text from app.event event where event.photo_card is not null
+union select venue.photo::text from app.venue venue where venue.photo is not null
+union select venue.photo_thumbnail::text from app.venue venue where venue.photo_thumbnail is not null
+union select venue.photo_card::text from app.venue venue where venue.photo_card is not null
+`
+
+func (q *Queries) InsecureGetPhotoUrls(ctx context.Context) ([]string, error) {
+	rows, err := q.db.Query(ctx, insecureGetPhotoUrls)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var photo string
+		if err := rows.Scan(&photo); err != nil {
+			return nil, err
+		}
+		items = append(items, photo)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insecureRejectMedia = `-- name: InsecureRejectMedia :one
+update app.media
+set status = 'rejected', moderation_reason = $2, photo_hash = $3
+where media.id = $1
+and media.status = 'pending'
+and media.photo is null
+returning pk, id, vendor, event, venue, position, caption, alt_text, photo, photo_thumbnail, photo_card, is_cover, created_at, status, moderation_reason, photo_hash, reviewed_by, reviewed_at
+`
+
+type InsecureRejectMediaParams struct {
+	ID               uuid.UUID
+	ModerationReason pgtype.Text
+	PhotoHash        pgtype.Int8
+}
+
+func (q *Queries) InsecureRejectMedia(ctx context.Context, arg InsecureRejectMediaParams) (AppMedia, error) {
+	row := q.db.QueryRow(ctx, insecureRejectMedia, arg.ID, arg.ModerationReason, arg.PhotoHash)
+	var i AppMedia
+	err := row.Scan(
+		&i.Pk,
+		&i.ID,
+		&i.Vendor,
+		&i.Event,
+		&i.Venue,
+		&i.Position,
+		&i.Caption,
+		&i.AltText,
+		&i.Photo,
+		&i.PhotoThumbnail,
+		&i.PhotoCard,
+		&i.IsCover,
+		&i.CreatedAt,
+		&i.Status,
+		&i.ModerationReason,
+		&i.PhotoHash,
+		&i.ReviewedBy,
+		&i.ReviewedAt,
+	)
+	return i, err
+}
+
 const insecureRemoveEventPhoto = `-- name: InsecureRemoveEventPhoto :one
 update app.event
-set photo = null
+set photo = null, photo_thumbnail = null, photo_card = null
 where event.id = $1
-returning pk, id, vendor, venue, name, type, event_datetime, description, disclaimer, basecost, num_unique, num_ga, photo, transaction_hash
+returning pk, id, vendor, venue, name, type, event_datetime, end_datetime, description, disclaimer, basecost, num_unique, num_ga, photo, photo_thumbnail, photo_card, transaction_hash, version, updated_at, cancelled_at
 `
 
 func (q *Queries) InsecureRemoveEventPhoto(ctx context.Context, id uuid.UUID) (AppEvent, error) {
@@ -344,22 +1520,28 @@ func (q *Queries) InsecureRemoveEventPhoto(ctx context.Context, id uuid.UUID) (A
 		&i.Name,
 		&i.Type,
 		&i.EventDatetime,
+		&i.EndDatetime,
 		&i.Description,
 		&i.Disclaimer,
 		&i.Basecost,
 		&i.NumUnique,
 		&i.NumGa,
 		&i.Photo,
+		&i.PhotoThumbnail,
+		&i.PhotoCard,
 		&i.TransactionHash,
+		&i.Version,
+		&i.UpdatedAt,
+		&i.CancelledAt,
 	)
 	return i, err
 }
 
 const insecureRemoveVenuePhoto = `-- name: InsecureRemoveVenuePhoto :one
 update app.venue
-set photo = null
+set photo = null, photo_thumbnail = null, photo_card = null
 where venue.id = $1
-returning pk, id, vendor, name, street_address, zip, city, state_code, state_name, country_code, country_name, num_unique, num_ga, photo
+returning pk, id, vendor, name, street_address, zip, city, state_code, state_name, country_code, country_name, num_unique, num_ga, photo, photo_thumbnail, photo_card, latitude, longitude, version, updated_at
 `
 
 func (q *Queries) InsecureRemoveVenuePhoto(ctx context.Context, id uuid.UUID) (AppVenue, error) {
@@ -380,24 +1562,67 @@ func (q *Queries) InsecureRemoveVenuePhoto(ctx context.Context, id uuid.UUID) (A
 		&i.NumUnique,
 		&i.NumGa,
 		&i.Photo,
+		&i.PhotoThumbnail,
+		&i.PhotoCard,
+		&i.Latitude,
+		&i.Longitude,
+		&i.Version,
+		&i.UpdatedAt,
 	)
 	return i, err
 }
 
+const insecureSyncEventCover = `-- name: InsecureSyncEventCover :exec
+update app.event
+set (photo, photo_thumbnail, photo_card) = (
+    select media.photo, media.photo_thumbnail, media.photo_card from app.media media
+    where media.event = event.pk
+    and media.is_cover
+)
+where event.pk = $1
+`
+
+func (q *Queries) InsecureSyncEventCover(ctx context.Context, pk int32) error {
+	_, err := q.db.Exec(ctx, insecureSyncEventCover, pk)
+	return err
+}
+
+const insecureSyncVenueCover = `-- name: InsecureSyncVenueCover :exec
+update app.venue
+set (photo, photo_thumbnail, photo_card) = (
+    select media.photo, media.photo_thumbnail, media.photo_card from app.media media
+    where media.venue = venue.pk
+    and media.is_cover
+)
+where venue.pk = $1
+`
+
+func (q *Queries) InsecureSyncVenueCover(ctx context.Context, pk int32) error {
+	_, err := q.db.Exec(ctx, insecureSyncVenueCover, pk)
+	return err
+}
+
 const insecureUpdateEventPhoto = `-- name: InsecureUpdateEventPhoto :one
 update app.event
-set photo = $2
+set photo = $2, photo_thumbnail = $3, photo_card = $4
 where event.id = $1
-returning pk, id, vendor, venue, name, type, event_datetime, description, disclaimer, basecost, num_unique, num_ga, photo, transaction_hash
+returning pk, id, vendor, venue, name, type, event_datetime, end_datetime, description, disclaimer, basecost, num_unique, num_ga, photo, photo_thumbnail, photo_card, transaction_hash, version, updated_at, cancelled_at
 `
 
 type InsecureUpdateEventPhotoParams struct {
-	ID    uuid.UUID
-	Photo pgtype.Text
+	ID             uuid.UUID
+	Photo          pgtype.Text
+	PhotoThumbnail pgtype.Text
+	PhotoCard      pgtype.Text
 }
 
 func (q *Queries) InsecureUpdateEventPhoto(ctx context.Context, arg InsecureUpdateEventPhotoParams) (AppEvent, error) {
-	row := q.db.QueryRow(ctx, insecureUpdateEventPhoto, arg.ID, arg.Photo)
+	row := q.db.QueryRow(ctx, insecureUpdateEventPhoto,
+		arg.ID,
+		arg.Photo,
+		arg.PhotoThumbnail,
+		arg.PhotoCard,
+	)
 	var i AppEvent
 	err := row.Scan(
 		&i.Pk,
@@ -407,31 +1632,44 @@ func (q *Queries) InsecureUpdateEventPhoto(ctx context.Context, arg InsecureUpda
 		&i.Name,
 		&i.Type,
 		&i.EventDatetime,
+		&i.EndDatetime,
 		&i.Description,
 		&i.Disclaimer,
 		&i.Basecost,
 		&i.NumUnique,
 		&i.NumGa,
 		&i.Photo,
+		&i.PhotoThumbnail,
+		&i.PhotoCard,
 		&i.TransactionHash,
+		&i.Version,
+		&i.UpdatedAt,
+		&i.CancelledAt,
 	)
 	return i, err
 }
 
 const insecureUpdateVenuePhoto = `-- name: InsecureUpdateVenuePhoto :one
 update app.venue
-set photo = $2
+set photo = $2, photo_thumbnail = $3, photo_card = $4
 where venue.id = $1
-returning pk, id, vendor, name, street_address, zip, city, state_code, state_name, country_code, country_name, num_unique, num_ga, photo
+returning pk, id, vendor, name, street_address, zip, city, state_code, state_name, country_code, country_name, num_unique, num_ga, photo, photo_thumbnail, photo_card, latitude, longitude, version, updated_at
 `
 
 type InsecureUpdateVenuePhotoParams struct {
-	ID    uuid.UUID
-	Photo pgtype.Text
+	ID             uuid.UUID
+	Photo          pgtype.Text
+	PhotoThumbnail pgtype.Text
+	PhotoCard      pgtype.Text
 }
 
 func (q *Queries) InsecureUpdateVenuePhoto(ctx context.Context, arg InsecureUpdateVenuePhotoParams) (AppVenue, error) {
-	row := q.db.QueryRow(ctx, insecureUpdateVenuePhoto, arg.ID, arg.Photo)
+	row := q.db.QueryRow(ctx, insecureUpdateVenuePhoto,
+		arg.ID,
+		arg.Photo,
+		arg.PhotoThumbnail,
+		arg.PhotoCard,
+	)
 	var i AppVenue
 	err := row.Scan(
 		&i.Pk,
@@ -448,12 +1686,103 @@ func (q *Queries) InsecureUpdateVenuePhoto(ctx context.Context, arg InsecureUpda
 		&i.NumUnique,
 		&i.NumGa,
 		&i.Photo,
+		&i.PhotoThumbnail,
+		&i.PhotoCard,
+		&i.Latitude,
+		&i.Longitude,
+		&i.Version,
+		&i.UpdatedAt,
 	)
 	return i, err
 }
 
+const isAdmin = `-- name: IsAdmin :one
+select exists (
+    select 1 from app.admin
+    where wallet = $1
+)
+`
+
+func (q *Queries) IsAdmin(ctx context.Context, wallet string) (bool, error) {
+	row := q.db.QueryRow(ctx, isAdmin, wallet)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const releaseIdempotencyKey = `-- name: ReleaseIdempotencyKey :exec
+delete from app.idempotency_key where wallet = $1 and key = $2
+`
+
+type ReleaseIdempotencyKeyParams struct {
+	Wallet string
+	Key    string
+}
+
+func (q *Queries) ReleaseIdempotencyKey(ctx context.Context, arg ReleaseIdempotencyKeyParams) error {
+	_, err := q.db.Exec(ctx, releaseIdempotencyKey, arg.Wallet, arg.Key)
+	return err
+}
+
+const setAuditActor = `-- name: SetAuditActor :exec
+select set_config('app.actor', $1::text, false)
+`
+
+func (q *Queries) SetAuditActor(ctx context.Context, dollar_1 string) error {
+	_, err := q.db.Exec(ctx, setAuditActor, dollar_1)
+	return err
+}
+
+const setMediaCover = `-- name: SetMediaCover :one
+update app.media
+set is_cover = true
+where media.pk = $1
+returning pk, id, vendor, event, venue, position, caption, alt_text, photo, photo_thumbnail, photo_card, is_cover, created_at, status, moderation_reason, photo_hash, reviewed_by, reviewed_at
+`
+
+func (q *Queries) SetMediaCover(ctx context.Context, pk int32) (AppMedia, error) {
+	row := q.db.QueryRow(ctx, setMediaCover, pk)
+	var i AppMedia
+	err := row.Scan(
+		&i.Pk,
+		&i.ID,
+		&i.Vendor,
+		&i.Event,
+		&i.Venue,
+		&i.Position,
+		&i.Caption,
+		&i.AltText,
+		&i.Photo,
+		&i.PhotoThumbnail,
+		&i.PhotoCard,
+		&i.IsCover,
+		&i.CreatedAt,
+		&i.Status,
+		&i.ModerationReason,
+		&i.PhotoHash,
+		&i.ReviewedBy,
+		&i.ReviewedAt,
+	)
+	return i, err
+}
+
+const shareLockEvent = `-- name: ShareLockEvent :exec
+select pk from app.event event
+where event.pk = $1
+for share
+`
+
+func (q *Queries) ShareLockEvent(ctx context.Context, pk int32) error {
+	_, err := q.db.Exec(ctx, shareLockEvent, pk)
+	return err
+}
+
 const updateCheckin = `-- name: UpdateCheckin :one
-update app.ticket set checked_in = $2 where pk = $1 returning pk, contract, ticket_id, checked_in, event
+update app.ticket set
+    checked_in = $2,
+    checked_in_at = case when $2 then now() end,
+    sold_at = coalesce(sold_at, case when $2 then now() end)
+where pk = $1 returning pk, contract, ticket_id, checked_in, event, created_at, sold_at, buyer, purchase_tx, checked_in_at
 `
 
 type UpdateCheckinParams struct {
@@ -470,10 +1799,80 @@ func (q *Queries) UpdateCheckin(ctx context.Context, arg UpdateCheckinParams) (A
 		&i.TicketID,
 		&i.CheckedIn,
 		&i.Event,
+		&i.CreatedAt,
+		&i.SoldAt,
+		&i.Buyer,
+		&i.PurchaseTx,
+		&i.CheckedInAt,
+	)
+	return i, err
+}
+
+const updateMediaDetails = `-- name: UpdateMediaDetails :one
+update app.media
+set
+  caption = case when $4::bool then null else coalesce(nullif($2::text, ''), caption) end,
+  alt_text = case when $5::bool then null else coalesce(nullif($3::text, ''), alt_text) end
+where media.pk = $1
+returning pk, id, vendor, event, venue, position, caption, alt_text, photo, photo_thumbnail, photo_card, is_cover, created_at, status, moderation_reason, photo_hash, reviewed_by, reviewed_at
+`
+
+type UpdateMediaDetailsParams struct {
+	Pk      int32
+	Column2 string
+	Column3 string
+	Column4 bool
+	Column5 bool
+}
+
+func (q *Queries) UpdateMediaDetails(ctx context.Context, arg UpdateMediaDetailsParams) (AppMedia, error) {
+	row := q.db.QueryRow(ctx, updateMediaDetails,
+		arg.Pk,
+		arg.Column2,
+		arg.Column3,
+		arg.Column4,
+		arg.Column5,
+	)
+	var i AppMedia
+	err := row.Scan(
+		&i.Pk,
+		&i.ID,
+		&i.Vendor,
+		&i.Event,
+		&i.Venue,
+		&i.Position,
+		&i.Caption,
+		&i.AltText,
+		&i.Photo,
+		&i.PhotoThumbnail,
+		&i.PhotoCard,
+		&i.IsCover,
+		&i.CreatedAt,
+		&i.Status,
+		&i.ModerationReason,
+		&i.PhotoHash,
+		&i.ReviewedBy,
+		&i.ReviewedAt,
 	)
 	return i, err
 }
 
+const updateMediaPosition = `-- name: UpdateMediaPosition :exec
+update app.media
+set position = $2
+where media.pk = $1
+`
+
+type UpdateMediaPositionParams struct {
+	Pk       int32
+	Position int32
+}
+
+func (q *Queries) UpdateMediaPosition(ctx context.Context, arg UpdateMediaPositionParams) error {
+	_, err := q.db.Exec(ctx, updateMediaPosition, arg.Pk, arg.Position)
+	return err
+}
+
 const updateVendorName = `-- name: UpdateVendorName :one
 update app.vendor set name = $2 where wallet = $1 returning pk, id, wallet, name
 `
@@ -495,10 +1894,74 @@ func (q *Queries) UpdateVendorName(ctx context.Context, arg UpdateVendorNamePara
 	return i, err
 }
 
+const userCountEvents = `-- name: UserCountEvents :one
+select count(*)
+from app.event event
+join app.venue venue on event.venue = venue.pk
+join app.event_search event_search on event_search.event = event.pk
+where (cardinality($1::text[]) = 0 or venue.zip = ANY($1::text[]))
+and ($2::text = ''
+    or event_search.document @@ to_tsquery('english', $3::text)
+    or word_similarity($2::text, event.name) >= 0.4
+    or word_similarity($2::text, venue.name) >= 0.4)
+and ($4::text = '' or $4::text = event.type)
+and ($5::double precision >= event.basecost)
+and ($6::timestamptz <= event.event_datetime)
+and event.cancelled_at is null
+and ($7::boolean = false
+    or app.distance_miles($8::double precision, $9::double precision, venue.latitude, venue.longitude)
+        <= $10::double precision)
+`
+
+type UserCountEventsParams struct {
+	Column1  []string
+	Column2  string
+	Column3  string
+	Column4  string
+	Column5  float64
+	Column6  pgtype.Timestamptz
+	Column7  bool
+	Column8  float64
+	Column9  float64
+	Column10 float64
+}
+
+func (q *Queries) UserCountEvents(ctx context.Context, arg UserCountEventsParams) (int64, error) {
+	row := q.db.QueryRow(ctx, userCountEvents,
+		arg.Column1,
+		arg.Column2,
+		arg.Column3,
+		arg.Column4,
+		arg.Column5,
+		arg.Column6,
+		arg.Column7,
+		arg.Column8,
+		arg.Column9,
+		arg.Column10,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const userDeletePushToken = `-- name: UserDeletePushToken :exec
+delete from app.push_token where token = $1 and wallet = $2
+`
+
+type UserDeletePushTokenParams struct {
+	Token  string
+	Wallet string
+}
+
+func (q *Queries) UserDeletePushToken(ctx context.Context, arg UserDeletePushTokenParams) error {
+	_, err := q.db.Exec(ctx, userDeletePushToken, arg.Token, arg.Wallet)
+	return err
+}
+
 const userGetEventByUuid = `-- name: UserGetEventByUuid :one
 select event.name Eventname, event.type, event.event_datetime,
-event.id, event.description, event.disclaimer,
-event.basecost, event.num_unique, event.num_ga,
+event.end_datetime, event.id, event.description, event.disclaimer,
+event.basecost, event.num_unique, event.num_ga, event.cancelled_at,
 event.photo Eventphoto, venue.name Venuename, venue.street_address, venue.zip, venue.city,
 venue.state_code, venue.country_code, venue.country_name,
 venue.photo Venuephoto, vendor.name Vendorname
@@ -513,12 +1976,14 @@ type UserGetEventByUuidRow struct {
 	Eventname     string
 	Type          string
 	EventDatetime pgtype.Timestamptz
+	EndDatetime   pgtype.Timestamptz
 	ID            uuid.UUID
 	Description   string
 	Disclaimer    pgtype.Text
 	Basecost      float64
 	NumUnique     int32
 	NumGa         int32
+	CancelledAt   pgtype.Timestamptz
 	Eventphoto    pgtype.Text
 	Venuename     string
 	StreetAddress string
@@ -538,12 +2003,14 @@ func (q *Queries) UserGetEventByUuid(ctx context.Context, id uuid.UUID) (UserGet
 		&i.Eventname,
 		&i.Type,
 		&i.EventDatetime,
+		&i.EndDatetime,
 		&i.ID,
 		&i.Description,
 		&i.Disclaimer,
 		&i.Basecost,
 		&i.NumUnique,
 		&i.NumGa,
+		&i.CancelledAt,
 		&i.Eventphoto,
 		&i.Venuename,
 		&i.StreetAddress,
@@ -558,32 +2025,118 @@ func (q *Queries) UserGetEventByUuid(ctx context.Context, id uuid.UUID) (UserGet
 	return i, err
 }
 
+const userGetEventMedia = `-- name: UserGetEventMedia :many
+select media.pk, media.id, media.vendor, media.event, media.venue, media.position, media.caption, media.alt_text, media.photo, media.photo_thumbnail, media.photo_card, media.is_cover, media.created_at, media.status, media.moderation_reason, media.photo_hash, media.reviewed_by, media.reviewed_at from app.media media
+join app.event event on media.event = event.pk
+where event.id = $1
+and media.status = 'approved'
+and media.photo is not null
+order by media.position, media.pk
+`
+
+func (q *Queries) UserGetEventMedia(ctx context.Context, id uuid.UUID) ([]AppMedia, error) {
+	rows, err := q.db.Query(ctx, userGetEventMedia, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []AppMedia
+	for rows.Next() {
+		var i AppMedia
+		if err := rows.Scan(
+			&i.Pk,
+			&i.ID,
+			&i.Vendor,
+			&i.Event,
+			&i.Venue,
+			&i.Position,
+			&i.Caption,
+			&i.AltText,
+			&i.Photo,
+			&i.PhotoThumbnail,
+			&i.PhotoCard,
+			&i.IsCover,
+			&i.CreatedAt,
+			&i.Status,
+			&i.ModerationReason,
+			&i.PhotoHash,
+			&i.ReviewedBy,
+			&i.ReviewedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const userGetEventsPaginated = `-- name: UserGetEventsPaginated :many
-select event.name, event.type, event.event_datetime,
-venue.name Venuename, venue.state_code, venue.country_code, event.photo,
-event.id
-from app.event event, app.venue venue
-where event.venue = venue.pk
-and (cardinality($2::text[]) = 0 or venue.zip = ANY($2::text[]))
-and ($3::text = '' or $3::text = event.name)
-and ($4::text = '' or $4::text = event.type)
-and ($5::double precision >= event.basecost)
-and ($6::timestamptz <= event.event_datetime)
-order by event.event_datetime, event.name
-limit 5
-offset (($1::int - 1) * 5)
+select results.pk, results.name, results.type, results.event_datetime,
+results.Venuename, results.state_code, results.country_code, results.photo,
+results.id, results.Rank, results.Snippet, results.Distance
+from (
+    select event.pk, event.name, event.type, event.event_datetime,
+    venue.name Venuename, venue.state_code, venue.country_code,
+    coalesce(event.photo_card, event.photo) photo,
+    event.id,
+    greatest(
+        ts_rank(event_search.document, to_tsquery('english', $4::text)),
+        word_similarity($3::text, event.name)
+    )::double precision Rank,
+    (case when $3::text = '' then ''
+    else ts_headline('english', event.description, to_tsquery('english', $4::text),
+        'StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MinWords=5, MaxWords=20')
+    end)::text Snippet,
+    (case when $13::boolean
+    then app.distance_miles($14::double precision, $15::double precision, venue.latitude, venue.longitude)
+    else 0 end)::double precision Distance
+    from app.event event
+    join app.venue venue on event.venue = venue.pk
+    join app.event_search event_search on event_search.event = event.pk
+    where (cardinality($2::text[]) = 0 or venue.zip = ANY($2::text[]))
+    and ($3::text = ''
+        or event_search.document @@ to_tsquery('english', $4::text)
+        or word_similarity($3::text, event.name) >= 0.4
+        or word_similarity($3::text, venue.name) >= 0.4)
+    and ($5::text = '' or $5::text = event.type)
+    and ($6::double precision >= event.basecost)
+    and ($7::timestamptz <= event.event_datetime)
+    and event.cancelled_at is null
+) results
+where ($13::boolean = false or results.Distance <= $16::double precision)
+and ($8::boolean = false
+    or (case when $13::boolean then results.Distance else -results.Rank end,
+        results.event_datetime, results.name, results.pk)
+        > ($9::double precision, $10::timestamptz, $11::text, $12::int))
+order by case when $13::boolean then results.Distance else -results.Rank end,
+    results.event_datetime, results.name, results.pk
+limit $1::int
 `
 
 type UserGetEventsPaginatedParams struct {
-	Column1 int32
-	Column2 []string
-	Column3 string
-	Column4 string
-	Column5 float64
-	Column6 pgtype.Timestamptz
+	Column1  int32
+	Column2  []string
+	Column3  string
+	Column4  string
+	Column5  string
+	Column6  float64
+	Column7  pgtype.Timestamptz
+	Column8  bool
+	Column9  float64
+	Column10 pgtype.Timestamptz
+	Column11 string
+	Column12 int32
+	Column13 bool
+	Column14 float64
+	Column15 float64
+	Column16 float64
 }
 
 type UserGetEventsPaginatedRow struct {
+	Pk            int32
 	Name          string
 	Type          string
 	EventDatetime pgtype.Timestamptz
@@ -592,6 +2145,9 @@ type UserGetEventsPaginatedRow struct {
 	CountryCode   string
 	Photo         pgtype.Text
 	ID            uuid.UUID
+	Rank          float64
+	Snippet       string
+	Distance      float64
 }
 
 func (q *Queries) UserGetEventsPaginated(ctx context.Context, arg UserGetEventsPaginatedParams) ([]UserGetEventsPaginatedRow, error) {
@@ -602,68 +2158,393 @@ func (q *Queries) UserGetEventsPaginated(ctx context.Context, arg UserGetEventsP
 		arg.Column4,
 		arg.Column5,
 		arg.Column6,
+		arg.Column7,
+		arg.Column8,
+		arg.Column9,
+		arg.Column10,
+		arg.Column11,
+		arg.Column12,
+		arg.Column13,
+		arg.Column14,
+		arg.Column15,
+		arg.Column16,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []UserGetEventsPaginatedRow
+	for rows.Next() {
+		var i UserGetEventsPaginatedRow
+		if err := rows.Scan(
+			&i.Pk,
+			&i.Name,
+			&i.Type,
+			&i.EventDatetime,
+			&i.Venuename,
+			&i.StateCode,
+			&i.CountryCode,
+			&i.Photo,
+			&i.ID,
+			&i.Rank,
+			&i.Snippet,
+			&i.Distance,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const userGetNotificationPreference = `-- name: UserGetNotificationPreference :one
+select wallet, email, email_enabled, push_enabled, updated_at from app.notification_preference where wallet = $1
+`
+
+func (q *Queries) UserGetNotificationPreference(ctx context.Context, wallet string) (AppNotificationPreference, error) {
+	row := q.db.QueryRow(ctx, userGetNotificationPreference, wallet)
+	var i AppNotificationPreference
+	err := row.Scan(
+		&i.Wallet,
+		&i.Email,
+		&i.EmailEnabled,
+		&i.PushEnabled,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const userRegisterPushToken = `-- name: UserRegisterPushToken :exec
+insert into app.push_token (
+    token,
+    wallet
+) values (
+    $1, $2
+)
+on conflict (token) do update set
+    wallet = excluded.wallet,
+    created_at = now()
+`
+
+type UserRegisterPushTokenParams struct {
+	Token  string
+	Wallet string
+}
+
+func (q *Queries) UserRegisterPushToken(ctx context.Context, arg UserRegisterPushTokenParams) error {
+	_, err := q.db.Exec(ctx, userRegisterPushToken, arg.Token, arg.Wallet)
+	return err
+}
+
+const userUpsertNotificationPreference = `-- name: UserUpsertNotificationPreference :one
+insert into app.notification_preference (
+    wallet,
+    email,
+    email_enabled,
+    push_enabled
+) values (
+    $1, $2, $3, $4
+)
+on conflict (wallet) do update set
+    email = excluded.email,
+    email_enabled = excluded.email_enabled,
+    push_enabled = excluded.push_enabled,
+    updated_at = now()
+returning wallet, email, email_enabled, push_enabled, updated_at
+`
+
+type UserUpsertNotificationPreferenceParams struct {
+	Wallet       string
+	Email        pgtype.Text
+	EmailEnabled bool
+	PushEnabled  bool
+}
+
+func (q *Queries) UserUpsertNotificationPreference(ctx context.Context, arg UserUpsertNotificationPreferenceParams) (AppNotificationPreference, error) {
+	row := q.db.QueryRow(ctx, userUpsertNotificationPreference,
+		arg.Wallet,
+		arg.Email,
+		arg.EmailEnabled,
+		arg.PushEnabled,
+	)
+	var i AppNotificationPreference
+	err := row.Scan(
+		&i.Wallet,
+		&i.Email,
+		&i.EmailEnabled,
+		&i.PushEnabled,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const vendorAddTransactionHash = `-- name: VendorAddTransactionHash :one
+update app.event set transaction_hash = $3 
+where event.pk = $1 
+and event.vendor = (
+    select pk from app.vendor 
+    where wallet = $2
+) 
+returning pk, id, vendor, venue, name, type, event_datetime, end_datetime, description, disclaimer, basecost, num_unique, num_ga, photo, photo_thumbnail, photo_card, transaction_hash, version, updated_at, cancelled_at
+`
+
+type VendorAddTransactionHashParams struct {
+	Pk              int32
+	Wallet          string
+	TransactionHash pgtype.Text
+}
+
+func (q *Queries) VendorAddTransactionHash(ctx context.Context, arg VendorAddTransactionHashParams) (AppEvent, error) {
+	row := q.db.QueryRow(ctx, vendorAddTransactionHash, arg.Pk, arg.Wallet, arg.TransactionHash)
+	var i AppEvent
+	err := row.Scan(
+		&i.Pk,
+		&i.ID,
+		&i.Vendor,
+		&i.Venue,
+		&i.Name,
+		&i.Type,
+		&i.EventDatetime,
+		&i.EndDatetime,
+		&i.Description,
+		&i.Disclaimer,
+		&i.Basecost,
+		&i.NumUnique,
+		&i.NumGa,
+		&i.Photo,
+		&i.PhotoThumbnail,
+		&i.PhotoCard,
+		&i.TransactionHash,
+		&i.Version,
+		&i.UpdatedAt,
+		&i.CancelledAt,
+	)
+	return i, err
+}
+
+const vendorCancelEvent = `-- name: VendorCancelEvent :one
+update app.event set cancelled_at = now()
+where event.pk = $1
+and event.vendor = (
+    select pk from app.vendor
+    where wallet = $2
+)
+and event.cancelled_at is null
+returning pk, id, vendor, venue, name, type, event_datetime, end_datetime, description, disclaimer, basecost, num_unique, num_ga, photo, photo_thumbnail, photo_card, transaction_hash, version, updated_at, cancelled_at
+`
+
+type VendorCancelEventParams struct {
+	Pk     int32
+	Wallet string
+}
+
+func (q *Queries) VendorCancelEvent(ctx context.Context, arg VendorCancelEventParams) (AppEvent, error) {
+	row := q.db.QueryRow(ctx, vendorCancelEvent, arg.Pk, arg.Wallet)
+	var i AppEvent
+	err := row.Scan(
+		&i.Pk,
+		&i.ID,
+		&i.Vendor,
+		&i.Venue,
+		&i.Name,
+		&i.Type,
+		&i.EventDatetime,
+		&i.EndDatetime,
+		&i.Description,
+		&i.Disclaimer,
+		&i.Basecost,
+		&i.NumUnique,
+		&i.NumGa,
+		&i.Photo,
+		&i.PhotoThumbnail,
+		&i.PhotoCard,
+		&i.TransactionHash,
+		&i.Version,
+		&i.UpdatedAt,
+		&i.CancelledAt,
+	)
+	return i, err
+}
+
+const vendorCountEvents = `-- name: VendorCountEvents :one
+select count(*) from app.event event
+where event.vendor = (
+    select pk from app.vendor vendor
+    where vendor.wallet = $1
+)
+and ($2::int = -1 or $2::int = event.venue)
+and ($3::timestamptz <= event.event_datetime)
+and ($4::text = '' or exists (
+    select 1 from app.event_search event_search
+    where event_search.event = event.pk
+    and event_search.document @@ to_tsquery('english', $5::text)
+) or word_similarity($4::text, event.name) >= 0.4)
+`
+
+type VendorCountEventsParams struct {
+	Wallet  string
+	Column2 int32
+	Column3 pgtype.Timestamptz
+	Column4 string
+	Column5 string
+}
+
+func (q *Queries) VendorCountEvents(ctx context.Context, arg VendorCountEventsParams) (int64, error) {
+	row := q.db.QueryRow(ctx, vendorCountEvents,
+		arg.Wallet,
+		arg.Column2,
+		arg.Column3,
+		arg.Column4,
+		arg.Column5,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const vendorCountRecordHistory = `-- name: VendorCountRecordHistory :one
+select count(*) from app.audit_log audit
+where audit.vendor = (
+    select pk from app.vendor vendor
+    where vendor.wallet = $1
+)
+and (
+    (audit.table_name = $2::text and audit.record_pk = $3::int)
+    or ($2::text = 'event' and audit.table_name = 'ticket' and audit.record_pk in (
+        select ticket.pk from app.ticket ticket
+        where ticket.event = $3::int
+    ))
+)
+`
+
+type VendorCountRecordHistoryParams struct {
+	Wallet  string
+	Column2 string
+	Column3 int32
+}
+
+func (q *Queries) VendorCountRecordHistory(ctx context.Context, arg VendorCountRecordHistoryParams) (int64, error) {
+	row := q.db.QueryRow(ctx, vendorCountRecordHistory, arg.Wallet, arg.Column2, arg.Column3)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const vendorCountVenues = `-- name: VendorCountVenues :one
+select count(*) from app.venue venue
+where venue.vendor = (
+    select pk from app.vendor vendor
+    where vendor.wallet = $1
+)
+and ($2::text = '' or exists (
+    select 1 from app.venue_search venue_search
+    where venue_search.venue = venue.pk
+    and venue_search.document @@ to_tsquery('english', $3::text)
+) or word_similarity($2::text, venue.name) >= 0.4)
+`
+
+type VendorCountVenuesParams struct {
+	Wallet  string
+	Column2 string
+	Column3 string
+}
+
+func (q *Queries) VendorCountVenues(ctx context.Context, arg VendorCountVenuesParams) (int64, error) {
+	row := q.db.QueryRow(ctx, vendorCountVenues, arg.Wallet, arg.Column2, arg.Column3)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const vendorCreateTicketExport = `-- name: VendorCreateTicketExport :one
+insert into app.ticket_export (
+    event,
+    wallet,
+    format
+) values (
+    $1, $2, $3
+) returning pk, id, event, wallet, format, status, object_key, error, created_at, claimed_at, completed_at
+`
+
+type VendorCreateTicketExportParams struct {
+	Event  int32
+	Wallet string
+	Format string
+}
+
+func (q *Queries) VendorCreateTicketExport(ctx context.Context, arg VendorCreateTicketExportParams) (AppTicketExport, error) {
+	row := q.db.QueryRow(ctx, vendorCreateTicketExport, arg.Event, arg.Wallet, arg.Format)
+	var i AppTicketExport
+	err := row.Scan(
+		&i.Pk,
+		&i.ID,
+		&i.Event,
+		&i.Wallet,
+		&i.Format,
+		&i.Status,
+		&i.ObjectKey,
+		&i.Error,
+		&i.CreatedAt,
+		&i.ClaimedAt,
+		&i.CompletedAt,
 	)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []UserGetEventsPaginatedRow
-	for rows.Next() {
-		var i UserGetEventsPaginatedRow
-		if err := rows.Scan(
-			&i.Name,
-			&i.Type,
-			&i.EventDatetime,
-			&i.Venuename,
-			&i.StateCode,
-			&i.CountryCode,
-			&i.Photo,
-			&i.ID,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
+	return i, err
 }
 
-const vendorAddTransactionHash = `-- name: VendorAddTransactionHash :one
-update app.event set transaction_hash = $3 
-where event.pk = $1 
-and event.vendor = (
-    select pk from app.vendor 
-    where wallet = $2
-) 
-returning pk, id, vendor, venue, name, type, event_datetime, description, disclaimer, basecost, num_unique, num_ga, photo, transaction_hash
+const vendorFindDuplicateVenue = `-- name: VendorFindDuplicateVenue :one
+select pk, id, vendor, name, street_address, zip, city, state_code, state_name, country_code, country_name, num_unique, num_ga, photo, photo_thumbnail, photo_card, latitude, longitude, version, updated_at from app.venue
+where venue.vendor = (
+    select pk from app.vendor
+    where wallet = $1
+)
+and lower(venue.street_address) = lower($2::text)
+and venue.zip = $3
+and venue.country_code = $4
+and venue.pk <> $5::int
+limit 1
 `
 
-type VendorAddTransactionHashParams struct {
-	Pk              int32
-	Wallet          string
-	TransactionHash pgtype.Text
+type VendorFindDuplicateVenueParams struct {
+	Wallet      string
+	Column2     string
+	Zip         string
+	CountryCode string
+	Column5     int32
 }
 
-func (q *Queries) VendorAddTransactionHash(ctx context.Context, arg VendorAddTransactionHashParams) (AppEvent, error) {
-	row := q.db.QueryRow(ctx, vendorAddTransactionHash, arg.Pk, arg.Wallet, arg.TransactionHash)
-	var i AppEvent
+func (q *Queries) VendorFindDuplicateVenue(ctx context.Context, arg VendorFindDuplicateVenueParams) (AppVenue, error) {
+	row := q.db.QueryRow(ctx, vendorFindDuplicateVenue,
+		arg.Wallet,
+		arg.Column2,
+		arg.Zip,
+		arg.CountryCode,
+		arg.Column5,
+	)
+	var i AppVenue
 	err := row.Scan(
 		&i.Pk,
 		&i.ID,
 		&i.Vendor,
-		&i.Venue,
 		&i.Name,
-		&i.Type,
-		&i.EventDatetime,
-		&i.Description,
-		&i.Disclaimer,
-		&i.Basecost,
+		&i.StreetAddress,
+		&i.Zip,
+		&i.City,
+		&i.StateCode,
+		&i.StateName,
+		&i.CountryCode,
+		&i.CountryName,
 		&i.NumUnique,
 		&i.NumGa,
 		&i.Photo,
-		&i.TransactionHash,
+		&i.PhotoThumbnail,
+		&i.PhotoCard,
+		&i.Latitude,
+		&i.Longitude,
+		&i.Version,
+		&i.UpdatedAt,
 	)
 	return i, err
 }
@@ -703,8 +2584,83 @@ func (q *Queries) VendorGetAllVenues(ctx context.Context, wallet string) ([]Vend
 	return items, nil
 }
 
+const vendorGetEventAnalytics = `-- name: VendorGetEventAnalytics :many
+select
+    event.id,
+    venue.id as venue_id,
+    venue.name as venue_name,
+    event.name,
+    event.event_datetime,
+    event.end_datetime,
+    event.basecost,
+    count(ticket.pk) as minted,
+    count(ticket.pk) filter (where ticket.sold_at is not null or ticket.checked_in) as sold,
+    count(ticket.pk) filter (where ticket.checked_in) as checked_in
+from app.event event
+join app.venue venue on venue.pk = event.venue
+left join app.ticket ticket on ticket.event = event.pk
+where event.vendor = (
+    select pk from app.vendor vendor
+    where vendor.wallet = $1
+)
+and ($2::timestamptz is null or event.event_datetime >= $2::timestamptz)
+and ($3::timestamptz is null or event.event_datetime < $3::timestamptz)
+group by event.pk, venue.pk
+order by event.event_datetime, event.pk
+`
+
+type VendorGetEventAnalyticsParams struct {
+	Wallet  string
+	Column2 pgtype.Timestamptz
+	Column3 pgtype.Timestamptz
+}
+
+type VendorGetEventAnalyticsRow struct {
+	ID            uuid.UUID
+	VenueID       uuid.UUID
+	VenueName     string
+	Name          string
+	EventDatetime pgtype.Timestamptz
+	EndDatetime   pgtype.Timestamptz
+	Basecost      float64
+	Minted        int64
+	Sold          int64
+	CheckedIn     int64
+}
+
+func (q *Queries) VendorGetEventAnalytics(ctx context.Context, arg VendorGetEventAnalyticsParams) ([]VendorGetEventAnalyticsRow, error) {
+	rows, err := q.db.Query(ctx, vendorGetEventAnalytics, arg.Wallet, arg.Column2, arg.Column3)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []VendorGetEventAnalyticsRow
+	for rows.Next() {
+		var i VendorGetEventAnalyticsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.VenueID,
+			&i.VenueName,
+			&i.Name,
+			&i.EventDatetime,
+			&i.EndDatetime,
+			&i.Basecost,
+			&i.Minted,
+			&i.Sold,
+			&i.CheckedIn,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const vendorGetEventByPk = `-- name: VendorGetEventByPk :one
-select pk, id, vendor, venue, name, type, event_datetime, description, disclaimer, basecost, num_unique, num_ga, photo, transaction_hash from app.event event
+select pk, id, vendor, venue, name, type, event_datetime, end_datetime, description, disclaimer, basecost, num_unique, num_ga, photo, photo_thumbnail, photo_card, transaction_hash, version, updated_at, cancelled_at from app.event event
 where event.pk = $1
 and event.vendor = (
     select pk from app.vendor
@@ -729,19 +2685,68 @@ func (q *Queries) VendorGetEventByPk(ctx context.Context, arg VendorGetEventByPk
 		&i.Name,
 		&i.Type,
 		&i.EventDatetime,
+		&i.EndDatetime,
+		&i.Description,
+		&i.Disclaimer,
+		&i.Basecost,
+		&i.NumUnique,
+		&i.NumGa,
+		&i.Photo,
+		&i.PhotoThumbnail,
+		&i.PhotoCard,
+		&i.TransactionHash,
+		&i.Version,
+		&i.UpdatedAt,
+		&i.CancelledAt,
+	)
+	return i, err
+}
+
+const vendorGetEventByPkForUpdate = `-- name: VendorGetEventByPkForUpdate :one
+select pk, id, vendor, venue, name, type, event_datetime, end_datetime, description, disclaimer, basecost, num_unique, num_ga, photo, photo_thumbnail, photo_card, transaction_hash, version, updated_at, cancelled_at from app.event event
+where event.pk = $1
+and event.vendor = (
+    select pk from app.vendor
+    where wallet = $2
+)
+for update
+`
+
+type VendorGetEventByPkForUpdateParams struct {
+	Pk     int32
+	Wallet string
+}
+
+func (q *Queries) VendorGetEventByPkForUpdate(ctx context.Context, arg VendorGetEventByPkForUpdateParams) (AppEvent, error) {
+	row := q.db.QueryRow(ctx, vendorGetEventByPkForUpdate, arg.Pk, arg.Wallet)
+	var i AppEvent
+	err := row.Scan(
+		&i.Pk,
+		&i.ID,
+		&i.Vendor,
+		&i.Venue,
+		&i.Name,
+		&i.Type,
+		&i.EventDatetime,
+		&i.EndDatetime,
 		&i.Description,
 		&i.Disclaimer,
 		&i.Basecost,
 		&i.NumUnique,
 		&i.NumGa,
 		&i.Photo,
+		&i.PhotoThumbnail,
+		&i.PhotoCard,
 		&i.TransactionHash,
+		&i.Version,
+		&i.UpdatedAt,
+		&i.CancelledAt,
 	)
 	return i, err
 }
 
 const vendorGetEventByUuid = `-- name: VendorGetEventByUuid :one
-select pk, id, vendor, venue, name, type, event_datetime, description, disclaimer, basecost, num_unique, num_ga, photo, transaction_hash from app.event event
+select pk, id, vendor, venue, name, type, event_datetime, end_datetime, description, disclaimer, basecost, num_unique, num_ga, photo, photo_thumbnail, photo_card, transaction_hash, version, updated_at, cancelled_at from app.event event
 where event.id = $1
 and event.vendor = (
     select pk from app.vendor
@@ -766,37 +2771,53 @@ func (q *Queries) VendorGetEventByUuid(ctx context.Context, arg VendorGetEventBy
 		&i.Name,
 		&i.Type,
 		&i.EventDatetime,
+		&i.EndDatetime,
 		&i.Description,
 		&i.Disclaimer,
 		&i.Basecost,
 		&i.NumUnique,
 		&i.NumGa,
 		&i.Photo,
+		&i.PhotoThumbnail,
+		&i.PhotoCard,
 		&i.TransactionHash,
+		&i.Version,
+		&i.UpdatedAt,
+		&i.CancelledAt,
 	)
 	return i, err
 }
 
 const vendorGetEventsPaginated = `-- name: VendorGetEventsPaginated :many
-select pk, id, vendor, venue, name, type, event_datetime, description, disclaimer, basecost, num_unique, num_ga, photo, transaction_hash from app.event event
+select pk, id, vendor, venue, name, type, event_datetime, end_datetime, description, disclaimer, basecost, num_unique, num_ga, photo, photo_thumbnail, photo_card, transaction_hash, version, updated_at, cancelled_at from app.event event
 where event.vendor = (
     select pk from app.vendor vendor
     where vendor.wallet = $2
 )
 and ($3::int = -1 or $3::int = event.venue)
 and ($4::timestamptz <= event.event_datetime)
-and ($5::text = '' or $5::text like LOWER(event.name) or $5::text like LOWER(event.type))
-order by event.event_datetime, event.name
-limit 25
-offset (($1::int - 1) * 25)
+and ($5::text = '' or exists (
+    select 1 from app.event_search event_search
+    where event_search.event = event.pk
+    and event_search.document @@ to_tsquery('english', $6::text)
+) or word_similarity($5::text, event.name) >= 0.4)
+and ($7::boolean = false
+    or (event.event_datetime, event.name, event.pk) > ($8::timestamptz, $9::text, $10::int))
+order by event.event_datetime, event.name, event.pk
+limit $1::int
 `
 
 type VendorGetEventsPaginatedParams struct {
-	Column1 int32
-	Wallet  string
-	Column3 int32
-	Column4 pgtype.Timestamptz
-	Column5 string
+	Column1  int32
+	Wallet   string
+	Column3  int32
+	Column4  pgtype.Timestamptz
+	Column5  string
+	Column6  string
+	Column7  bool
+	Column8  pgtype.Timestamptz
+	Column9  string
+	Column10 int32
 }
 
 func (q *Queries) VendorGetEventsPaginated(ctx context.Context, arg VendorGetEventsPaginatedParams) ([]AppEvent, error) {
@@ -806,29 +2827,264 @@ func (q *Queries) VendorGetEventsPaginated(ctx context.Context, arg VendorGetEve
 		arg.Column3,
 		arg.Column4,
 		arg.Column5,
+		arg.Column6,
+		arg.Column7,
+		arg.Column8,
+		arg.Column9,
+		arg.Column10,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []AppEvent
+	for rows.Next() {
+		var i AppEvent
+		if err := rows.Scan(
+			&i.Pk,
+			&i.ID,
+			&i.Vendor,
+			&i.Venue,
+			&i.Name,
+			&i.Type,
+			&i.EventDatetime,
+			&i.EndDatetime,
+			&i.Description,
+			&i.Disclaimer,
+			&i.Basecost,
+			&i.NumUnique,
+			&i.NumGa,
+			&i.Photo,
+			&i.PhotoThumbnail,
+			&i.PhotoCard,
+			&i.TransactionHash,
+			&i.Version,
+			&i.UpdatedAt,
+			&i.CancelledAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const vendorGetMediaByUuid = `-- name: VendorGetMediaByUuid :one
+select pk, id, vendor, event, venue, position, caption, alt_text, photo, photo_thumbnail, photo_card, is_cover, created_at, status, moderation_reason, photo_hash, reviewed_by, reviewed_at from app.media
+where media.id = $1
+and media.vendor = (
+    select pk from app.vendor
+    where wallet = $2
+)
+`
+
+type VendorGetMediaByUuidParams struct {
+	ID     uuid.UUID
+	Wallet string
+}
+
+func (q *Queries) VendorGetMediaByUuid(ctx context.Context, arg VendorGetMediaByUuidParams) (AppMedia, error) {
+	row := q.db.QueryRow(ctx, vendorGetMediaByUuid, arg.ID, arg.Wallet)
+	var i AppMedia
+	err := row.Scan(
+		&i.Pk,
+		&i.ID,
+		&i.Vendor,
+		&i.Event,
+		&i.Venue,
+		&i.Position,
+		&i.Caption,
+		&i.AltText,
+		&i.Photo,
+		&i.PhotoThumbnail,
+		&i.PhotoCard,
+		&i.IsCover,
+		&i.CreatedAt,
+		&i.Status,
+		&i.ModerationReason,
+		&i.PhotoHash,
+		&i.ReviewedBy,
+		&i.ReviewedAt,
+	)
+	return i, err
+}
+
+const vendorGetOverlappingEvents = `-- name: VendorGetOverlappingEvents :many
+select event.pk, event.id, event.name, event.event_datetime, event.end_datetime
+from app.event event
+where event.venue = $1
+  and event.event_datetime < $3::timestamptz
+  and event.end_datetime > $2::timestamptz
+  and event.pk <> $4::int
+  and event.cancelled_at is null
+order by event.event_datetime, event.pk
+`
+
+type VendorGetOverlappingEventsParams struct {
+	Venue   int32
+	Column2 pgtype.Timestamptz
+	Column3 pgtype.Timestamptz
+	Column4 int32
+}
+
+type VendorGetOverlappingEventsRow struct {
+	Pk            int32
+	ID            uuid.UUID
+	Name          string
+	EventDatetime pgtype.Timestamptz
+	EndDatetime   pgtype.Timestamptz
+}
+
+func (q *Queries) VendorGetOverlappingEvents(ctx context.Context, arg VendorGetOverlappingEventsParams) ([]VendorGetOverlappingEventsRow, error) {
+	rows, err := q.db.Query(ctx, vendorGetOverlappingEvents,
+		arg.Venue,
+		arg.Column2,
+		arg.Column3,
+		arg.Column4,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []VendorGetOverlappingEventsRow
+	for rows.Next() {
+		var i VendorGetOverlappingEventsRow
+		if err := rows.Scan(
+			&i.Pk,
+			&i.ID,
+			&i.Name,
+			&i.EventDatetime,
+			&i.EndDatetime,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const vendorGetRecordHistory = `-- name: VendorGetRecordHistory :many
+select pk, table_name, record_pk, vendor, action, actor, before, after, changed_at from app.audit_log audit
+where audit.vendor = (
+    select pk from app.vendor vendor
+    where vendor.wallet = $2
+)
+and (
+    (audit.table_name = $3::text and audit.record_pk = $4::int)
+    or ($3::text = 'event' and audit.table_name = 'ticket' and audit.record_pk in (
+        select ticket.pk from app.ticket ticket
+        where ticket.event = $4::int
+    ))
+)
+and ($5::boolean = false or audit.pk < $6::int)
+order by audit.pk desc
+limit $1::int
+`
+
+type VendorGetRecordHistoryParams struct {
+	Column1 int32
+	Wallet  string
+	Column3 string
+	Column4 int32
+	Column5 bool
+	Column6 int32
+}
+
+func (q *Queries) VendorGetRecordHistory(ctx context.Context, arg VendorGetRecordHistoryParams) ([]AppAuditLog, error) {
+	rows, err := q.db.Query(ctx, vendorGetRecordHistory,
+		arg.Column1,
+		arg.Wallet,
+		arg.Column3,
+		arg.Column4,
+		arg.Column5,
+		arg.Column6,
 	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []AppEvent
+	var items []AppAuditLog
 	for rows.Next() {
-		var i AppEvent
+		var i AppAuditLog
 		if err := rows.Scan(
 			&i.Pk,
-			&i.ID,
+			&i.TableName,
+			&i.RecordPk,
 			&i.Vendor,
-			&i.Venue,
-			&i.Name,
-			&i.Type,
-			&i.EventDatetime,
-			&i.Description,
-			&i.Disclaimer,
-			&i.Basecost,
-			&i.NumUnique,
-			&i.NumGa,
-			&i.Photo,
-			&i.TransactionHash,
+			&i.Action,
+			&i.Actor,
+			&i.Before,
+			&i.After,
+			&i.ChangedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const vendorGetTicketActivityByHour = `-- name: VendorGetTicketActivityByHour :many
+with ticket as (
+    select ticket.sold_at, ticket.checked_in_at
+    from app.ticket ticket
+    join app.event event on event.pk = ticket.event
+    where event.vendor = (
+        select pk from app.vendor vendor
+        where vendor.wallet = $1
+    )
+    and ($2::timestamptz is null or event.event_datetime >= $2::timestamptz)
+    and ($3::timestamptz is null or event.event_datetime < $3::timestamptz)
+), activity as (
+    select date_trunc('hour', ticket.sold_at) as hour, 'sold' as kind
+    from ticket where ticket.sold_at is not null
+    union all
+    select date_trunc('hour', ticket.checked_in_at) as hour, 'checked_in' as kind
+    from ticket where ticket.checked_in_at is not null
+)
+select
+    activity.hour::timestamptz as hour,
+    count(*) filter (where activity.kind = 'sold') as sold,
+    count(*) filter (where activity.kind = 'checked_in') as checked_in
+from activity
+group by activity.hour
+order by activity.hour
+`
+
+type VendorGetTicketActivityByHourParams struct {
+	Wallet  string
+	Column2 pgtype.Timestamptz
+	Column3 pgtype.Timestamptz
+}
+
+type VendorGetTicketActivityByHourRow struct {
+	Hour      pgtype.Timestamptz
+	Sold      int64
+	CheckedIn int64
+}
+
+func (q *Queries) VendorGetTicketActivityByHour(ctx context.Context, arg VendorGetTicketActivityByHourParams) ([]VendorGetTicketActivityByHourRow, error) {
+	rows, err := q.db.Query(ctx, vendorGetTicketActivityByHour, arg.Wallet, arg.Column2, arg.Column3)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []VendorGetTicketActivityByHourRow
+	for rows.Next() {
+		var i VendorGetTicketActivityByHourRow
+		if err := rows.Scan(
+			&i.Hour,
+			&i.Sold,
+			&i.CheckedIn,
 		); err != nil {
 			return nil, err
 		}
@@ -840,8 +3096,39 @@ func (q *Queries) VendorGetEventsPaginated(ctx context.Context, arg VendorGetEve
 	return items, nil
 }
 
+const vendorGetTicketExport = `-- name: VendorGetTicketExport :one
+select pk, id, event, wallet, format, status, object_key, error, created_at, claimed_at, completed_at from app.ticket_export
+where ticket_export.id = $1
+and ticket_export.wallet = $2
+limit 1
+`
+
+type VendorGetTicketExportParams struct {
+	ID     uuid.UUID
+	Wallet string
+}
+
+func (q *Queries) VendorGetTicketExport(ctx context.Context, arg VendorGetTicketExportParams) (AppTicketExport, error) {
+	row := q.db.QueryRow(ctx, vendorGetTicketExport, arg.ID, arg.Wallet)
+	var i AppTicketExport
+	err := row.Scan(
+		&i.Pk,
+		&i.ID,
+		&i.Event,
+		&i.Wallet,
+		&i.Format,
+		&i.Status,
+		&i.ObjectKey,
+		&i.Error,
+		&i.CreatedAt,
+		&i.ClaimedAt,
+		&i.CompletedAt,
+	)
+	return i, err
+}
+
 const vendorGetVenueByPk = `-- name: VendorGetVenueByPk :one
-select pk, id, vendor, name, street_address, zip, city, state_code, state_name, country_code, country_name, num_unique, num_ga, photo from app.venue 
+select pk, id, vendor, name, street_address, zip, city, state_code, state_name, country_code, country_name, num_unique, num_ga, photo, photo_thumbnail, photo_card, latitude, longitude, version, updated_at from app.venue 
 where venue.pk = $1 
 and venue.vendor = (
     select pk from app.vendor
@@ -873,12 +3160,61 @@ func (q *Queries) VendorGetVenueByPk(ctx context.Context, arg VendorGetVenueByPk
 		&i.NumUnique,
 		&i.NumGa,
 		&i.Photo,
+		&i.PhotoThumbnail,
+		&i.PhotoCard,
+		&i.Latitude,
+		&i.Longitude,
+		&i.Version,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const vendorGetVenueByPkForUpdate = `-- name: VendorGetVenueByPkForUpdate :one
+select pk, id, vendor, name, street_address, zip, city, state_code, state_name, country_code, country_name, num_unique, num_ga, photo, photo_thumbnail, photo_card, latitude, longitude, version, updated_at from app.venue
+where venue.pk = $1
+and venue.vendor = (
+    select pk from app.vendor
+    where wallet = $2
+)
+for update
+`
+
+type VendorGetVenueByPkForUpdateParams struct {
+	Pk     int32
+	Wallet string
+}
+
+func (q *Queries) VendorGetVenueByPkForUpdate(ctx context.Context, arg VendorGetVenueByPkForUpdateParams) (AppVenue, error) {
+	row := q.db.QueryRow(ctx, vendorGetVenueByPkForUpdate, arg.Pk, arg.Wallet)
+	var i AppVenue
+	err := row.Scan(
+		&i.Pk,
+		&i.ID,
+		&i.Vendor,
+		&i.Name,
+		&i.StreetAddress,
+		&i.Zip,
+		&i.City,
+		&i.StateCode,
+		&i.StateName,
+		&i.CountryCode,
+		&i.CountryName,
+		&i.NumUnique,
+		&i.NumGa,
+		&i.Photo,
+		&i.PhotoThumbnail,
+		&i.PhotoCard,
+		&i.Latitude,
+		&i.Longitude,
+		&i.Version,
+		&i.UpdatedAt,
 	)
 	return i, err
 }
 
 const vendorGetVenueByUuid = `-- name: VendorGetVenueByUuid :one
-select pk, id, vendor, name, street_address, zip, city, state_code, state_name, country_code, country_name, num_unique, num_ga, photo from app.venue 
+select pk, id, vendor, name, street_address, zip, city, state_code, state_name, country_code, country_name, num_unique, num_ga, photo, photo_thumbnail, photo_card, latitude, longitude, version, updated_at from app.venue 
 where venue.id = $1 
 and venue.vendor = (
     select pk from app.vendor
@@ -910,30 +3246,154 @@ func (q *Queries) VendorGetVenueByUuid(ctx context.Context, arg VendorGetVenueBy
 		&i.NumUnique,
 		&i.NumGa,
 		&i.Photo,
+		&i.PhotoThumbnail,
+		&i.PhotoCard,
+		&i.Latitude,
+		&i.Longitude,
+		&i.Version,
+		&i.UpdatedAt,
 	)
 	return i, err
 }
 
+const vendorGetVenueCalendar = `-- name: VendorGetVenueCalendar :many
+select event.pk, event.id, event.name, event.event_datetime, event.end_datetime
+from app.event event
+where event.venue = $1
+  and event.event_datetime < $3::timestamptz
+  and event.end_datetime > $2::timestamptz
+  and event.cancelled_at is null
+order by event.event_datetime, event.pk
+`
+
+type VendorGetVenueCalendarParams struct {
+	Venue   int32
+	Column2 pgtype.Timestamptz
+	Column3 pgtype.Timestamptz
+}
+
+type VendorGetVenueCalendarRow struct {
+	Pk            int32
+	ID            uuid.UUID
+	Name          string
+	EventDatetime pgtype.Timestamptz
+	EndDatetime   pgtype.Timestamptz
+}
+
+func (q *Queries) VendorGetVenueCalendar(ctx context.Context, arg VendorGetVenueCalendarParams) ([]VendorGetVenueCalendarRow, error) {
+	rows, err := q.db.Query(ctx, vendorGetVenueCalendar, arg.Venue, arg.Column2, arg.Column3)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []VendorGetVenueCalendarRow
+	for rows.Next() {
+		var i VendorGetVenueCalendarRow
+		if err := rows.Scan(
+			&i.Pk,
+			&i.ID,
+			&i.Name,
+			&i.EventDatetime,
+			&i.EndDatetime,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const vendorGetVenueCapacityConflicts = `-- name: VendorGetVenueCapacityConflicts :many
+select event.pk, event.id, event.name, event.event_datetime, event.num_unique, event.num_ga
+from app.event event
+where event.venue = $1
+  and event.end_datetime > now()
+  and event.cancelled_at is null
+  and (event.num_unique > $2::int or event.num_ga > $3::int)
+order by event.event_datetime, event.pk
+`
+
+type VendorGetVenueCapacityConflictsParams struct {
+	Venue   int32
+	Column2 int32
+	Column3 int32
+}
+
+type VendorGetVenueCapacityConflictsRow struct {
+	Pk            int32
+	ID            uuid.UUID
+	Name          string
+	EventDatetime pgtype.Timestamptz
+	NumUnique     int32
+	NumGa         int32
+}
+
+func (q *Queries) VendorGetVenueCapacityConflicts(ctx context.Context, arg VendorGetVenueCapacityConflictsParams) ([]VendorGetVenueCapacityConflictsRow, error) {
+	rows, err := q.db.Query(ctx, vendorGetVenueCapacityConflicts, arg.Venue, arg.Column2, arg.Column3)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []VendorGetVenueCapacityConflictsRow
+	for rows.Next() {
+		var i VendorGetVenueCapacityConflictsRow
+		if err := rows.Scan(
+			&i.Pk,
+			&i.ID,
+			&i.Name,
+			&i.EventDatetime,
+			&i.NumUnique,
+			&i.NumGa,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const vendorGetVenuesPaginated = `-- name: VendorGetVenuesPaginated :many
-select pk, id, vendor, name, street_address, zip, city, state_code, state_name, country_code, country_name, num_unique, num_ga, photo from app.venue venue
+select pk, id, vendor, name, street_address, zip, city, state_code, state_name, country_code, country_name, num_unique, num_ga, photo, photo_thumbnail, photo_card, latitude, longitude, version, updated_at from app.venue venue
 where venue.vendor = (
     select pk from app.vendor vendor
     where vendor.wallet = $2
 )
-and ($3::text = '' or $3::text like LOWER(venue.name) or $3::text like LOWER(venue.zip) or $3::text like LOWER(venue.city))
-order by venue.name
-limit 25
-offset (($1::int - 1) * 25)
+and ($3::text = '' or exists (
+    select 1 from app.venue_search venue_search
+    where venue_search.venue = venue.pk
+    and venue_search.document @@ to_tsquery('english', $4::text)
+) or word_similarity($3::text, venue.name) >= 0.4)
+and ($5::boolean = false or (venue.name, venue.pk) > ($6::text, $7::int))
+order by venue.name, venue.pk
+limit $1::int
 `
 
 type VendorGetVenuesPaginatedParams struct {
 	Column1 int32
 	Wallet  string
 	Column3 string
+	Column4 string
+	Column5 bool
+	Column6 string
+	Column7 int32
 }
 
 func (q *Queries) VendorGetVenuesPaginated(ctx context.Context, arg VendorGetVenuesPaginatedParams) ([]AppVenue, error) {
-	rows, err := q.db.Query(ctx, vendorGetVenuesPaginated, arg.Column1, arg.Wallet, arg.Column3)
+	rows, err := q.db.Query(ctx, vendorGetVenuesPaginated,
+		arg.Column1,
+		arg.Wallet,
+		arg.Column3,
+		arg.Column4,
+		arg.Column5,
+		arg.Column6,
+		arg.Column7,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -956,6 +3416,12 @@ func (q *Queries) VendorGetVenuesPaginated(ctx context.Context, arg VendorGetVen
 			&i.NumUnique,
 			&i.NumGa,
 			&i.Photo,
+			&i.PhotoThumbnail,
+			&i.PhotoCard,
+			&i.Latitude,
+			&i.Longitude,
+			&i.Version,
+			&i.UpdatedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -973,28 +3439,36 @@ set
   name = coalesce(nullif($3::text, ''), name),
   type = coalesce(nullif($4::text, ''), type),
   event_datetime = coalesce($5::timestamptz, event_datetime),
+  end_datetime = coalesce($13::timestamptz, end_datetime),
   description = coalesce(nullif($6::text, ''), description),
-  disclaimer = coalesce(nullif($7::text, ''), disclaimer),
-  photo = coalesce(nullif($8::text, ''), photo),
-  transaction_hash = coalesce(nullif($9::text, ''), transaction_hash)
+  disclaimer = case when $9::bool then null else coalesce(nullif($7::text, ''), disclaimer) end,
+  photo = case when $10::bool then null else photo end,
+  photo_thumbnail = case when $10::bool then null else photo_thumbnail end,
+  photo_card = case when $10::bool then null else photo_card end,
+  transaction_hash = case when $11::bool then null else coalesce(nullif($8::text, ''), transaction_hash) end
 where event.pk = $1
   and event.vendor = (
     select pk from app.vendor
     where wallet = $2
   )
-returning pk, id, vendor, venue, name, type, event_datetime, description, disclaimer, basecost, num_unique, num_ga, photo, transaction_hash
+  and ($12::int = 0 or event.version = $12::int)
+returning pk, id, vendor, venue, name, type, event_datetime, end_datetime, description, disclaimer, basecost, num_unique, num_ga, photo, photo_thumbnail, photo_card, transaction_hash, version, updated_at, cancelled_at
 `
 
 type VendorPatchEventParams struct {
-	Pk      int32
-	Wallet  string
-	Column3 string
-	Column4 string
-	Column5 pgtype.Timestamptz
-	Column6 string
-	Column7 string
-	Column8 string
-	Column9 string
+	Pk       int32
+	Wallet   string
+	Column3  string
+	Column4  string
+	Column5  pgtype.Timestamptz
+	Column6  string
+	Column7  string
+	Column8  string
+	Column9  bool
+	Column10 bool
+	Column11 bool
+	Column12 int32
+	Column13 pgtype.Timestamptz
 }
 
 func (q *Queries) VendorPatchEvent(ctx context.Context, arg VendorPatchEventParams) (AppEvent, error) {
@@ -1008,6 +3482,10 @@ func (q *Queries) VendorPatchEvent(ctx context.Context, arg VendorPatchEventPara
 		arg.Column7,
 		arg.Column8,
 		arg.Column9,
+		arg.Column10,
+		arg.Column11,
+		arg.Column12,
+		arg.Column13,
 	)
 	var i AppEvent
 	err := row.Scan(
@@ -1018,13 +3496,19 @@ func (q *Queries) VendorPatchEvent(ctx context.Context, arg VendorPatchEventPara
 		&i.Name,
 		&i.Type,
 		&i.EventDatetime,
+		&i.EndDatetime,
 		&i.Description,
 		&i.Disclaimer,
 		&i.Basecost,
 		&i.NumUnique,
 		&i.NumGa,
 		&i.Photo,
+		&i.PhotoThumbnail,
+		&i.PhotoCard,
 		&i.TransactionHash,
+		&i.Version,
+		&i.UpdatedAt,
+		&i.CancelledAt,
 	)
 	return i, err
 }
@@ -1040,13 +3524,18 @@ set
   state_name = coalesce(nullif($8::text, ''), state_name),
   country_code = coalesce(nullif($9::text, ''), country_code),
   country_name = coalesce(nullif($10::text, ''), country_name),
-  photo = coalesce(nullif($11::text, ''), photo)
+  photo = case when $11::bool then null else photo end,
+  photo_thumbnail = case when $11::bool then null else photo_thumbnail end,
+  photo_card = case when $11::bool then null else photo_card end,
+  latitude = case when $13::bool then case when $14::bool then $15::double precision end else latitude end,
+  longitude = case when $13::bool then case when $14::bool then $16::double precision end else longitude end
 where venue.pk = $1
   and venue.vendor = (
     select pk from app.vendor
     where wallet = $2
   )
-returning pk, id, vendor, name, street_address, zip, city, state_code, state_name, country_code, country_name, num_unique, num_ga, photo
+  and ($12::int = 0 or venue.version = $12::int)
+returning pk, id, vendor, name, street_address, zip, city, state_code, state_name, country_code, country_name, num_unique, num_ga, photo, photo_thumbnail, photo_card, latitude, longitude, version, updated_at
 `
 
 type VendorPatchVenueParams struct {
@@ -1060,7 +3549,12 @@ type VendorPatchVenueParams struct {
 	Column8  string
 	Column9  string
 	Column10 string
-	Column11 string
+	Column11 bool
+	Column12 int32
+	Column13 bool
+	Column14 bool
+	Column15 float64
+	Column16 float64
 }
 
 func (q *Queries) VendorPatchVenue(ctx context.Context, arg VendorPatchVenueParams) (AppVenue, error) {
@@ -1076,6 +3570,11 @@ func (q *Queries) VendorPatchVenue(ctx context.Context, arg VendorPatchVenuePara
 		arg.Column9,
 		arg.Column10,
 		arg.Column11,
+		arg.Column12,
+		arg.Column13,
+		arg.Column14,
+		arg.Column15,
+		arg.Column16,
 	)
 	var i AppVenue
 	err := row.Scan(
@@ -1093,19 +3592,25 @@ func (q *Queries) VendorPatchVenue(ctx context.Context, arg VendorPatchVenuePara
 		&i.NumUnique,
 		&i.NumGa,
 		&i.Photo,
+		&i.PhotoThumbnail,
+		&i.PhotoCard,
+		&i.Latitude,
+		&i.Longitude,
+		&i.Version,
+		&i.UpdatedAt,
 	)
 	return i, err
 }
 
 const vendorRemoveEventPhoto = `-- name: VendorRemoveEventPhoto :one
 update app.event
-set photo = null
+set photo = null, photo_thumbnail = null, photo_card = null
 where event.id = $1
 and event.vendor = (
     select pk from app.vendor
     where wallet = $2
 )
-returning pk, id, vendor, venue, name, type, event_datetime, description, disclaimer, basecost, num_unique, num_ga, photo, transaction_hash
+returning pk, id, vendor, venue, name, type, event_datetime, end_datetime, description, disclaimer, basecost, num_unique, num_ga, photo, photo_thumbnail, photo_card, transaction_hash, version, updated_at, cancelled_at
 `
 
 type VendorRemoveEventPhotoParams struct {
@@ -1124,26 +3629,32 @@ func (q *Queries) VendorRemoveEventPhoto(ctx context.Context, arg VendorRemoveEv
 		&i.Name,
 		&i.Type,
 		&i.EventDatetime,
+		&i.EndDatetime,
 		&i.Description,
 		&i.Disclaimer,
 		&i.Basecost,
 		&i.NumUnique,
 		&i.NumGa,
 		&i.Photo,
+		&i.PhotoThumbnail,
+		&i.PhotoCard,
 		&i.TransactionHash,
+		&i.Version,
+		&i.UpdatedAt,
+		&i.CancelledAt,
 	)
 	return i, err
 }
 
 const vendorRemoveVenuePhoto = `-- name: VendorRemoveVenuePhoto :one
 update app.venue
-set photo = null
+set photo = null, photo_thumbnail = null, photo_card = null
 where venue.id = $1
 and venue.vendor = (
     select pk from app.vendor
     where wallet = $2
 )
-returning pk, id, vendor, name, street_address, zip, city, state_code, state_name, country_code, country_name, num_unique, num_ga, photo
+returning pk, id, vendor, name, street_address, zip, city, state_code, state_name, country_code, country_name, num_unique, num_ga, photo, photo_thumbnail, photo_card, latitude, longitude, version, updated_at
 `
 
 type VendorRemoveVenuePhotoParams struct {
@@ -1169,6 +3680,124 @@ func (q *Queries) VendorRemoveVenuePhoto(ctx context.Context, arg VendorRemoveVe
 		&i.NumUnique,
 		&i.NumGa,
 		&i.Photo,
+		&i.PhotoThumbnail,
+		&i.PhotoCard,
+		&i.Latitude,
+		&i.Longitude,
+		&i.Version,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const vendorUpdateEventCapacity = `-- name: VendorUpdateEventCapacity :one
+update app.event
+set
+  num_unique = $3,
+  num_ga = $4
+where event.pk = $1
+  and event.vendor = (
+    select pk from app.vendor
+    where wallet = $2
+  )
+  and ($5::int = 0 or event.version = $5::int)
+returning pk, id, vendor, venue, name, type, event_datetime, end_datetime, description, disclaimer, basecost, num_unique, num_ga, photo, photo_thumbnail, photo_card, transaction_hash, version, updated_at, cancelled_at
+`
+
+type VendorUpdateEventCapacityParams struct {
+	Pk        int32
+	Wallet    string
+	NumUnique int32
+	NumGa     int32
+	Column5   int32
+}
+
+func (q *Queries) VendorUpdateEventCapacity(ctx context.Context, arg VendorUpdateEventCapacityParams) (AppEvent, error) {
+	row := q.db.QueryRow(ctx, vendorUpdateEventCapacity,
+		arg.Pk,
+		arg.Wallet,
+		arg.NumUnique,
+		arg.NumGa,
+		arg.Column5,
+	)
+	var i AppEvent
+	err := row.Scan(
+		&i.Pk,
+		&i.ID,
+		&i.Vendor,
+		&i.Venue,
+		&i.Name,
+		&i.Type,
+		&i.EventDatetime,
+		&i.EndDatetime,
+		&i.Description,
+		&i.Disclaimer,
+		&i.Basecost,
+		&i.NumUnique,
+		&i.NumGa,
+		&i.Photo,
+		&i.PhotoThumbnail,
+		&i.PhotoCard,
+		&i.TransactionHash,
+		&i.Version,
+		&i.UpdatedAt,
+		&i.CancelledAt,
+	)
+	return i, err
+}
+
+const vendorUpdateVenueCapacity = `-- name: VendorUpdateVenueCapacity :one
+update app.venue
+set
+  num_unique = $3,
+  num_ga = $4
+where venue.pk = $1
+  and venue.vendor = (
+    select pk from app.vendor
+    where wallet = $2
+  )
+  and ($5::int = 0 or venue.version = $5::int)
+returning pk, id, vendor, name, street_address, zip, city, state_code, state_name, country_code, country_name, num_unique, num_ga, photo, photo_thumbnail, photo_card, latitude, longitude, version, updated_at
+`
+
+type VendorUpdateVenueCapacityParams struct {
+	Pk        int32
+	Wallet    string
+	NumUnique int32
+	NumGa     int32
+	Column5   int32
+}
+
+func (q *Queries) VendorUpdateVenueCapacity(ctx context.Context, arg VendorUpdateVenueCapacityParams) (AppVenue, error) {
+	row := q.db.QueryRow(ctx, vendorUpdateVenueCapacity,
+		arg.Pk,
+		arg.Wallet,
+		arg.NumUnique,
+		arg.NumGa,
+		arg.Column5,
+	)
+	var i AppVenue
+	err := row.Scan(
+		&i.Pk,
+		&i.ID,
+		&i.Vendor,
+		&i.Name,
+		&i.StreetAddress,
+		&i.Zip,
+		&i.City,
+		&i.StateCode,
+		&i.StateName,
+		&i.CountryCode,
+		&i.CountryName,
+		&i.NumUnique,
+		&i.NumGa,
+		&i.Photo,
+		&i.PhotoThumbnail,
+		&i.PhotoCard,
+		&i.Latitude,
+		&i.Longitude,
+		&i.Version,
+		&i.UpdatedAt,
 	)
 	return i, err
 }
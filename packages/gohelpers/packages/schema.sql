@@ -1,4 +1,5 @@
 CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
 
 create schema app;
 
@@ -40,9 +41,9 @@ create table app.venue (
     street_address text not null,
     zip text not null,
     city text not null,
-    state_code varchar(5) not null
+    state_code varchar(6) not null
         constraint venue_state_code_check
-            check ((state_code)::text ~ '^[A-Z]{2}-[A-Z]{2}$'::text),
+            check ((state_code)::text ~ '^[A-Z]{2}-[A-Z0-9]{1,3}$'::text),
     state_name text not null,
     country_code varchar(2) not null
         constraint venue_country_code_check
@@ -50,7 +51,16 @@ create table app.venue (
     country_name text not null,
     num_unique integer not null,
     num_ga integer not null,
-    photo text
+    photo text,
+    -- Resized copies of photo, set along with it by the upload pipeline
+    photo_thumbnail text,
+    photo_card text,
+    -- Centroid of the venue's postal code, null when it could not be geocoded
+    latitude double precision,
+    longitude double precision,
+    -- Bumped on every update, PATCH compares it against If-Match
+    version integer not null default 1,
+    updated_at timestamptz not null default now()
 );
 
 
@@ -71,15 +81,100 @@ create table app.event(
     name text not null,
     type text not null,
     event_datetime timestamptz not null,
+    end_datetime timestamptz not null,
     description text not null,
     disclaimer text,
     basecost double precision not null,
     num_unique integer not null,
     num_ga integer not null,
     photo text,
-    transaction_hash text
+    -- Resized copies of photo, set along with it by the upload pipeline
+    photo_thumbnail text,
+    photo_card text,
+    transaction_hash text,
+    -- Bumped on every update, PATCH compares it against If-Match
+    version integer not null default 1,
+    updated_at timestamptz not null default now(),
+    -- Set when the vendor cancels the event. Cancelled events stay readable
+    -- for ticket holders but are left out of searches and overlap checks.
+    cancelled_at timestamptz,
+    constraint event_ends_after_start
+        check (end_datetime > event_datetime)
+);
+
+-- Overlap checks and venue calendars look up events by venue and time
+create index event_venue_datetime_idx on app.event (venue, event_datetime, end_datetime);
+
+-- Photos in an event's or venue's gallery, shown in position order. The cover's
+-- urls are copied onto the event or venue's photo columns, so listings don't
+-- need this table. photo and its variants are null until the upload pipeline
+-- has processed the upload.
+create table app.media (
+    pk integer generated always as identity
+        constraint media_pk primary key,
+    id uuid not null
+        default uuid_generate_v4()
+        constraint media_id unique,
+    vendor integer not null
+        constraint media_vendor_pk_fk
+            references app.vendor
+            on delete cascade,
+    event integer
+        constraint media_event_pk_fk
+            references app.event
+            on delete cascade,
+    venue integer
+        constraint media_venue_pk_fk
+            references app.venue
+            on delete cascade,
+    position integer not null,
+    caption text,
+    alt_text text,
+    photo text,
+    photo_thumbnail text,
+    photo_card text,
+    is_cover boolean not null default false,
+    created_at timestamptz not null default now(),
+    -- Set by the upload pipeline's classifiers and by admins' reviews. Only
+    -- approved photos are shown to users or can be the cover.
+    status text not null default 'pending'
+        constraint media_status_check
+            check (status in ('pending', 'approved', 'rejected')),
+    moderation_reason text,
+    -- Perceptual hash of the upload, blocklisted when an admin blocks it
+    photo_hash bigint,
+    reviewed_by varchar(40),
+    reviewed_at timestamptz,
+    constraint media_one_owner
+        check (num_nonnulls(event, venue) = 1)
+);
+
+create index media_event_idx on app.media (event, position);
+create index media_venue_idx on app.media (venue, position);
+-- At most one cover per gallery
+create unique index media_event_cover_idx on app.media (event) where is_cover;
+create unique index media_venue_cover_idx on app.media (venue) where is_cover;
+-- Backs the admin review queue
+create index media_status_idx on app.media (status, pk);
+
+-- Wallets allowed to use the admin endpoints
+create table app.admin (
+    wallet varchar(40) not null
+        constraint admin_pk primary key
+        constraint admin_wallet_fmt
+            check((wallet)::text ~ '^[0-9A-Fa-f]{40}$'::text)
 );
 
+-- Perceptual hashes of photos admins rejected and blocked. Uploads close to one
+-- are rejected without a review.
+create table app.photo_blocklist (
+    hash bigint not null
+        constraint photo_blocklist_pk primary key,
+    reason text,
+    created_at timestamptz not null default now()
+);
+
+
 create table app.ticket
 (
     pk         integer generated always as identity
@@ -91,5 +186,372 @@ create table app.ticket
     event      integer               not null
         constraint ticket_event_pk_fk
             references event
-            on delete cascade
+            on delete cascade,
+    -- When the ticket was recorded as minted
+    created_at timestamptz not null default now(),
+    -- Set when the buyer records their purchase transaction, or at check-in
+    -- for tickets bought without recording it
+    sold_at timestamptz,
+    buyer varchar(40)
+        constraint ticket_buyer_fmt
+            check((buyer)::text ~ '^[0-9A-Fa-f]{40}$'::text),
+    purchase_tx text,
+    checked_in_at timestamptz
+);
+
+create index ticket_event_idx on app.ticket (event, ticket_id);
+
+-- Ticket exports too large to send in the response, written to storage by the
+-- ticket export job. Only the wallet that asked for one can download it.
+create table app.ticket_export (
+    pk integer generated always as identity
+        constraint ticket_export_pk primary key,
+    id uuid not null
+        default uuid_generate_v4()
+        constraint ticket_export_id unique,
+    event integer not null
+        constraint ticket_export_event_pk_fk
+            references app.event
+            on delete cascade,
+    wallet varchar(40) not null,
+    format text not null
+        constraint ticket_export_format_check
+            check (format in ('csv', 'ndjson')),
+    status text not null default 'pending'
+        constraint ticket_export_status_check
+            check (status in ('pending', 'running', 'done', 'failed', 'expired')),
+    object_key text,
+    error text,
+    created_at timestamptz not null default now(),
+    -- When the job took it, so exports whose job died can be taken again
+    claimed_at timestamptz,
+    completed_at timestamptz
+);
+
+create index ticket_export_status_idx on app.ticket_export (status, pk);
+
+-- How an attendee wants to hear about changes to the events they hold tickets
+-- for. Wallets without a row get every notification on every channel they can
+-- be reached on, which is push only until they give an email address.
+create table app.notification_preference (
+    wallet varchar(40) not null
+        constraint notification_preference_pk primary key
+        constraint notification_preference_wallet_fmt
+            check((wallet)::text ~ '^[0-9A-Fa-f]{40}$'::text),
+    email text,
+    email_enabled boolean not null default true,
+    push_enabled boolean not null default true,
+    updated_at timestamptz not null default now()
+);
+
+-- Expo push tokens of the mobile apps a wallet is signed in to. A token moves
+-- to whichever wallet registered it last.
+create table app.push_token (
+    token text not null
+        constraint push_token_pk primary key,
+    wallet varchar(40) not null
+        constraint push_token_wallet_fmt
+            check((wallet)::text ~ '^[0-9A-Fa-f]{40}$'::text),
+    created_at timestamptz not null default now()
+);
+
+create index push_token_wallet_idx on app.push_token (wallet);
+
+-- Notifications for the notification job to fan out to the attendees of an
+-- event. Rows are written in the same transaction as the change they announce,
+-- so neither is ever saved without the other. payload is what the templates
+-- are rendered with, taken when the change is made.
+create table app.notification_outbox (
+    pk integer generated always as identity
+        constraint notification_outbox_pk primary key,
+    kind text not null
+        constraint notification_outbox_kind_check
+            check (kind in ('event_updated', 'event_cancelled')),
+    event integer not null
+        constraint notification_outbox_event_pk_fk
+            references app.event
+            on delete cascade,
+    payload jsonb not null,
+    status text not null default 'pending'
+        constraint notification_outbox_status_check
+            check (status in ('pending', 'running', 'done', 'failed')),
+    attempts integer not null default 0,
+    error text,
+    created_at timestamptz not null default now(),
+    -- When the job took it, so notifications whose job died can be taken again
+    claimed_at timestamptz,
+    completed_at timestamptz
+);
+
+create index notification_outbox_status_idx on app.notification_outbox (status, pk);
+
+-- One row per recipient and channel of a notification, claimed before sending,
+-- so a notification taken again after a failure isn't sent to anyone twice.
+create table app.notification_delivery (
+    pk integer generated always as identity
+        constraint notification_delivery_pk primary key,
+    notification integer not null
+        constraint notification_delivery_notification_pk_fk
+            references app.notification_outbox
+            on delete cascade,
+    wallet varchar(40) not null,
+    channel text not null
+        constraint notification_delivery_channel_check
+            check (channel in ('email', 'push')),
+    status text not null default 'sending'
+        constraint notification_delivery_status_check
+            check (status in ('sending', 'sent', 'failed')),
+    error text,
+    created_at timestamptz not null default now(),
+    -- When the status last changed, so sends whose job died can be retried
+    updated_at timestamptz not null default now(),
+    constraint notification_delivery_unique unique (notification, wallet, channel)
+);
+
+-- Photo urls that a row stopped referencing, for the photo cleanup job to
+-- delete from storage. The same url can be referenced again by then, as when a
+-- gallery photo's cover copy is removed, so the job checks before deleting.
+create table app.deleted_photo (
+    pk integer generated always as identity
+        constraint deleted_photo_pk primary key,
+    url text not null,
+    deleted_at timestamptz not null default now()
+);
+
+-- Full-text search documents. These live beside event/venue instead of as
+-- columns so that select * on the base tables is unaffected, and so the event
+-- document can include the venue name and city.
+create table app.event_search (
+    event integer not null
+        constraint event_search_pk primary key
+        constraint event_search_event_pk_fk
+            references app.event
+            on delete cascade,
+    document tsvector not null
+);
+
+create index event_search_document_idx on app.event_search using gin (document);
+
+create table app.venue_search (
+    venue integer not null
+        constraint venue_search_pk primary key
+        constraint venue_search_venue_pk_fk
+            references app.venue
+            on delete cascade,
+    document tsvector not null
+);
+
+create index venue_search_document_idx on app.venue_search using gin (document);
+
+-- Responses to requests sent with an Idempotency-Key header, so retries get the
+-- original result instead of repeating the request. status_code and
+-- response_body are null while the first request is still being handled.
+create table app.idempotency_key (
+    wallet varchar(40) not null,
+    key text not null,
+    fingerprint text not null,
+    status_code integer,
+    response_body text,
+    created_at timestamptz not null default now(),
+    constraint idempotency_key_pk primary key (wallet, key)
 );
+
+-- Every change to a vendor-managed row. actor is the wallet the API set with
+-- SetAuditActor, null for changes made by the event handlers. Updates only keep
+-- the columns that changed in before and after.
+create table app.audit_log (
+    pk integer generated always as identity
+        constraint audit_log_pk primary key,
+    table_name text not null,
+    record_pk integer not null,
+    vendor integer,
+    action text not null
+        constraint audit_log_action_check
+            check (action in ('insert', 'update', 'delete')),
+    actor text,
+    before jsonb,
+    after jsonb,
+    changed_at timestamptz not null default now()
+);
+
+create index audit_log_record_idx on app.audit_log (vendor, table_name, record_pk, pk);
+
+-- Backs the per-vendor duplicate address check on venue create/patch.
+create index venue_vendor_address_idx on app.venue (vendor, lower(street_address), zip);
+
+-- Trigram indexes back the typo tolerant name matching.
+create index event_name_trgm_idx on app.event using gin (name gin_trgm_ops);
+create index venue_name_trgm_idx on app.venue using gin (name gin_trgm_ops);
+
+create function app.refresh_event_search(event_pk integer) returns void as $$
+    insert into app.event_search (event, document)
+    select event.pk,
+        setweight(to_tsvector('english', event.name), 'A') ||
+        setweight(to_tsvector('english', event.type), 'B') ||
+        setweight(to_tsvector('english', venue.name), 'B') ||
+        setweight(to_tsvector('english', venue.city), 'C') ||
+        setweight(to_tsvector('english', event.description), 'D')
+    from app.event event
+    join app.venue venue on venue.pk = event.venue
+    where event.pk = event_pk
+    on conflict (event) do update set document = excluded.document;
+$$ language sql;
+
+create function app.refresh_venue_search(venue_pk integer) returns void as $$
+    insert into app.venue_search (venue, document)
+    select venue.pk,
+        setweight(to_tsvector('english', venue.name), 'A') ||
+        setweight(to_tsvector('simple', venue.zip), 'B') ||
+        setweight(to_tsvector('english', venue.city), 'B') ||
+        setweight(to_tsvector('english', venue.street_address), 'C')
+    from app.venue venue
+    where venue.pk = venue_pk
+    on conflict (venue) do update set document = excluded.document;
+$$ language sql;
+
+create function app.event_search_trigger() returns trigger as $$
+begin
+    perform app.refresh_event_search(new.pk);
+    return null;
+end;
+$$ language plpgsql;
+
+create function app.venue_search_trigger() returns trigger as $$
+begin
+    perform app.refresh_venue_search(new.pk);
+    perform app.refresh_event_search(event.pk)
+    from app.event event
+    where event.venue = new.pk;
+    return null;
+end;
+$$ language plpgsql;
+
+create trigger event_search_refresh
+    after insert or update of name, type, description, venue on app.event
+    for each row execute function app.event_search_trigger();
+
+create trigger venue_search_refresh
+    after insert or update of name, street_address, zip, city on app.venue
+    for each row execute function app.venue_search_trigger();
+
+-- Every update of an event or venue changes its version, which is what PATCH
+-- requests compare If-Match against, so no write can be missed by a query
+-- that forgets to bump it
+create function app.bump_version_trigger() returns trigger as $$
+begin
+    new.version := old.version + 1;
+    new.updated_at := now();
+    return new;
+end;
+$$ language plpgsql;
+
+create trigger event_bump_version
+    before update on app.event
+    for each row execute function app.bump_version_trigger();
+
+create trigger venue_bump_version
+    before update on app.venue
+    for each row execute function app.bump_version_trigger();
+
+create function app.audit_trigger() returns trigger as $$
+declare
+    old_row jsonb := case when tg_op <> 'INSERT' then to_jsonb(old) end;
+    new_row jsonb := case when tg_op <> 'DELETE' then to_jsonb(new) end;
+    full_row jsonb := coalesce(new_row, old_row);
+    owner integer;
+begin
+    if tg_op = 'UPDATE' then
+        select jsonb_object_agg(n.key, old_row -> n.key), jsonb_object_agg(n.key, n.value)
+        into old_row, new_row
+        from jsonb_each(new_row) n
+        where n.value is distinct from old_row -> n.key
+        and n.key not in ('version', 'updated_at');
+        -- Nothing but the version changed
+        if new_row is null then
+            return null;
+        end if;
+    end if;
+
+    owner := case tg_table_name
+        when 'vendor' then (full_row ->> 'pk')::integer
+        when 'ticket' then (
+            select event.vendor from app.event event
+            where event.pk = (full_row ->> 'event')::integer
+        )
+        else (full_row ->> 'vendor')::integer
+    end;
+
+    insert into app.audit_log (table_name, record_pk, vendor, action, actor, before, after)
+    values (
+        tg_table_name,
+        (full_row ->> 'pk')::integer,
+        owner,
+        lower(tg_op),
+        nullif(current_setting('app.actor', true), ''),
+        old_row,
+        new_row
+    );
+    return null;
+end;
+$$ language plpgsql;
+
+create trigger vendor_audit
+    after insert or update or delete on app.vendor
+    for each row execute function app.audit_trigger();
+
+create trigger venue_audit
+    after insert or update or delete on app.venue
+    for each row execute function app.audit_trigger();
+
+create trigger event_audit
+    after insert or update or delete on app.event
+    for each row execute function app.audit_trigger();
+
+create trigger ticket_audit
+    after insert or update or delete on app.ticket
+    for each row execute function app.audit_trigger();
+
+-- Records the photo urls a delete or update dropped, including deletes
+-- cascading from a vendor, event or venue
+create function app.deleted_photo_trigger() returns trigger as $$
+declare
+    kept text[] := '{}';
+begin
+    if tg_op = 'UPDATE' then
+        kept := array[new.photo, new.photo_thumbnail, new.photo_card];
+    end if;
+
+    insert into app.deleted_photo (url)
+    select url
+    from unnest(array[old.photo, old.photo_thumbnail, old.photo_card]) url
+    where url is not null
+    and (url = any(kept)) is not true;
+    return null;
+end;
+$$ language plpgsql;
+
+create trigger venue_deleted_photo
+    after update of photo, photo_thumbnail, photo_card or delete on app.venue
+    for each row execute function app.deleted_photo_trigger();
+
+create trigger event_deleted_photo
+    after update of photo, photo_thumbnail, photo_card or delete on app.event
+    for each row execute function app.deleted_photo_trigger();
+
+create trigger media_deleted_photo
+    after update of photo, photo_thumbnail, photo_card or delete on app.media
+    for each row execute function app.deleted_photo_trigger();
+
+-- Great-circle distance in miles between two points using the haversine formula
+create function app.distance_miles(
+    lat1 double precision,
+    lng1 double precision,
+    lat2 double precision,
+    lng2 double precision
+) returns double precision as $$
+    select 3958.8 * 2 * asin(least(1, sqrt(
+        power(sin(radians(lat2 - lat1) / 2), 2) +
+        cos(radians(lat1)) * cos(radians(lat2)) *
+        power(sin(radians(lng2 - lng1) / 2), 2)
+    )));
+$$ language sql immutable;
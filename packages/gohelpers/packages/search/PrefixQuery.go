@@ -0,0 +1,21 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+)
+
+// PrefixQuery converts free-form user input into a to_tsquery expression where
+// every term must match and is prefix matched, e.g. "jazz fest" becomes
+// "jazz:* & fest:*". Punctuation is dropped so the result is always valid
+// tsquery syntax. An empty string is returned when the input has no terms.
+func PrefixQuery(input string) string {
+	terms := strings.FieldsFunc(strings.ToLower(input), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	for i, term := range terms {
+		terms[i] = term + ":*"
+	}
+	return strings.Join(terms, " & ")
+}
@@ -0,0 +1,29 @@
+package search
+
+import "testing"
+
+func TestPrefixQuery(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"empty", "", ""},
+		{"only punctuation", " ,.!& | ", ""},
+		{"single term", "jazz", "jazz:*"},
+		{"terms are all required", "jazz fest", "jazz:* & fest:*"},
+		{"lowercased", "Jazz FEST", "jazz:* & fest:*"},
+		{"extra whitespace", "  jazz \t fest\n", "jazz:* & fest:*"},
+		{"tsquery operators are dropped", "jazz & !fest | (blues):*", "jazz:* & fest:* & blues:*"},
+		{"punctuation splits terms", "rock'n'roll", "rock:* & n:* & roll:*"},
+		{"digits are kept", "summer 2026", "summer:* & 2026:*"},
+		{"non-ascii letters are kept", "café über", "café:* & über:*"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PrefixQuery(tt.input); got != tt.want {
+				t.Errorf("PrefixQuery(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
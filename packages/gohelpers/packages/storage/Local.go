@@ -0,0 +1,314 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Where LocalStore serves objects when LOCAL_STORAGE_URL isn't set
+const DefaultLocalURL = "http://localhost:9000"
+
+// A Store in a directory on the local disk, for running photo uploads without
+// AWS. Its Handler stands in for the bucket: it accepts the uploads presigned
+// by the store, serves what was written and reports new objects the way S3
+// events would.
+type LocalStore struct {
+	dir     string
+	baseURL *url.URL
+	secret  []byte
+}
+
+// Notifications sent by LocalStore's Handler
+type localNotification struct {
+	Keys []string `json:"Keys"`
+}
+
+func NewLocalStore(dir string, baseURL string, secret string) (*LocalStore, error) {
+	u, err := url.Parse(strings.TrimSuffix(baseURL, "/"))
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &LocalStore{dir: dir, baseURL: u, secret: []byte(secret)}, nil
+}
+
+func (s *LocalStore) path(key string) (string, error) {
+	if !filepath.IsLocal(filepath.FromSlash(key)) {
+		return "", errors.New("invalid object key")
+	}
+	return filepath.Join(s.dir, filepath.FromSlash(key)), nil
+}
+
+// Signs a request to PUT an upload or GET a download, so neither signature can
+// be used for the other
+func (s *LocalStore) sign(method string, key string, contentType string, size int64, expires int64) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(strings.Join([]string{method, key, contentType, strconv.FormatInt(size, 10), strconv.FormatInt(expires, 10)}, "\n")))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (s *LocalStore) PresignUpload(ctx context.Context, key string, contentType string, size int64, expires time.Duration) (PresignedUpload, error) {
+	if _, err := s.path(key); err != nil {
+		return PresignedUpload{}, err
+	}
+	expiry := time.Now().Add(expires).Unix()
+	u, err := url.Parse(s.URL(key))
+	if err != nil {
+		return PresignedUpload{}, err
+	}
+	q := u.Query()
+	q.Set("expires", strconv.FormatInt(expiry, 10))
+	q.Set("size", strconv.FormatInt(size, 10))
+	q.Set("signature", s.sign(http.MethodPut, key, contentType, size, expiry))
+	u.RawQuery = q.Encode()
+	return PresignedUpload{
+		URL:    u.String(),
+		Method: http.MethodPut,
+		SignedHeader: http.Header{
+			"Content-Type":   []string{contentType},
+			"Content-Length": []string{strconv.FormatInt(size, 10)},
+		},
+	}, nil
+}
+
+// Objects are served to anyone like in a public bucket, the signature only
+// makes the handler send the filename
+func (s *LocalStore) PresignDownload(ctx context.Context, key string, filename string, expires time.Duration) (string, error) {
+	if _, err := s.path(key); err != nil {
+		return "", err
+	}
+	expiry := time.Now().Add(expires).Unix()
+	u, err := url.Parse(s.URL(key))
+	if err != nil {
+		return "", err
+	}
+	q := u.Query()
+	q.Set("expires", strconv.FormatInt(expiry, 10))
+	q.Set("filename", filename)
+	q.Set("signature", s.sign(http.MethodGet, key, filename, 0, expiry))
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+func (s *LocalStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	return file, err
+}
+
+// The content type isn't kept, objects are served with the type of their
+// extension
+func (s *LocalStore) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	// Written beside the object and renamed, so readers never see part of it
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func (s *LocalStore) Delete(ctx context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+func (s *LocalStore) List(ctx context.Context, prefix string) ([]Object, error) {
+	var objects []Object
+	err := filepath.WalkDir(s.dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		// Skips the temporary files of writes in progress
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".upload-") {
+			return nil
+		}
+		rel, err := filepath.Rel(s.dir, path)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		objects = append(objects, Object{Key: key, LastModified: info.ModTime()})
+		return nil
+	})
+	return objects, err
+}
+
+func (s *LocalStore) URL(key string) string {
+	return s.baseURL.JoinPath(key).String()
+}
+
+func (s *LocalStore) KeyOf(rawURL string) (string, bool) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme != s.baseURL.Scheme || u.Host != s.baseURL.Host {
+		return "", false
+	}
+	key, ok := strings.CutPrefix(u.Path, s.baseURL.Path+"/")
+	if !ok || key == "" {
+		return "", false
+	}
+	return key, true
+}
+
+func (s *LocalStore) CreatedKeys(notification string) ([]string, error) {
+	var n localNotification
+	if err := json.Unmarshal([]byte(notification), &n); err != nil {
+		return nil, err
+	}
+	return n.Keys, nil
+}
+
+// Handler serves the store's objects and accepts the uploads it presigned.
+// notify is called with a notification for CreatedKeys after each upload, in
+// the background like an S3 event.
+func (s *LocalStore) Handler(notify func(ctx context.Context, notification string)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Uploads come straight from the vendor site
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, PUT")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+
+		key := strings.TrimPrefix(r.URL.Path, s.baseURL.Path+"/")
+		path, err := s.path(key)
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+
+		switch r.Method {
+		case http.MethodOptions:
+			w.WriteHeader(http.StatusNoContent)
+		case http.MethodGet, http.MethodHead:
+			if r.URL.Query().Has("signature") {
+				if err := s.checkDownload(r, key); err != nil {
+					http.Error(w, err.Error(), http.StatusForbidden)
+					return
+				}
+				w.Header().Set("Content-Disposition", attachment(r.URL.Query().Get("filename")))
+			}
+			http.ServeFile(w, r, path)
+		case http.MethodPut:
+			if err := s.checkUpload(r, key); err != nil {
+				http.Error(w, err.Error(), http.StatusForbidden)
+				return
+			}
+			data, err := io.ReadAll(io.LimitReader(r.Body, r.ContentLength+1))
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			if int64(len(data)) != r.ContentLength {
+				http.Error(w, "body does not match the signed length", http.StatusBadRequest)
+				return
+			}
+			if err := s.Put(r.Context(), key, data, r.Header.Get("Content-Type")); err != nil {
+				log.Printf("Failed to write %s: %v", key, err)
+				http.Error(w, "failed to write object", http.StatusInternalServerError)
+				return
+			}
+			notification, err := json.Marshal(localNotification{Keys: []string{key}})
+			if err != nil {
+				log.Printf("Failed to marshal notification for %s: %v", key, err)
+			} else {
+				go notify(context.Background(), string(notification))
+			}
+			w.WriteHeader(http.StatusOK)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+}
+
+// Checks an upload has the signature, content type and length its URL was
+// presigned with
+func (s *LocalStore) checkUpload(r *http.Request, key string) error {
+	q := r.URL.Query()
+	expiry, err := strconv.ParseInt(q.Get("expires"), 10, 64)
+	if err != nil {
+		return errors.New("missing expiry")
+	}
+	if time.Now().Unix() > expiry {
+		return errors.New("request has expired")
+	}
+	size, err := strconv.ParseInt(q.Get("size"), 10, 64)
+	if err != nil || size != r.ContentLength {
+		return errors.New("content length does not match the signed length")
+	}
+	expected := s.sign(http.MethodPut, key, r.Header.Get("Content-Type"), size, expiry)
+	if !hmac.Equal([]byte(expected), []byte(q.Get("signature"))) {
+		return errors.New("signature does not match")
+	}
+	return nil
+}
+
+// Checks a download has the signature and expiry its URL was presigned with
+func (s *LocalStore) checkDownload(r *http.Request, key string) error {
+	q := r.URL.Query()
+	expiry, err := strconv.ParseInt(q.Get("expires"), 10, 64)
+	if err != nil {
+		return errors.New("missing expiry")
+	}
+	if time.Now().Unix() > expiry {
+		return errors.New("request has expired")
+	}
+	expected := s.sign(http.MethodGet, key, q.Get("filename"), 0, expiry)
+	if !hmac.Equal([]byte(expected), []byte(q.Get("signature"))) {
+		return errors.New("signature does not match")
+	}
+	return nil
+}
+
+// Serves Handler on the host of the store's URL
+func (s *LocalStore) ListenAndServe(notify func(ctx context.Context, notification string)) error {
+	return http.ListenAndServe(s.baseURL.Host, s.Handler(notify))
+}
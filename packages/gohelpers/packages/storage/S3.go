@@ -0,0 +1,187 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// A Store backed by an S3 bucket, whose object created events reach the upload
+// handler through SNS and SQS
+type S3Store struct {
+	bucket    string
+	client    *s3.Client
+	presigner *s3.PresignClient
+}
+
+func NewS3Store(ctx context.Context, bucket string) (*S3Store, error) {
+	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion("us-east-1"))
+	if err != nil {
+		return nil, err
+	}
+	client := s3.NewFromConfig(cfg)
+	return &S3Store{
+		bucket:    bucket,
+		client:    client,
+		presigner: s3.NewPresignClient(client),
+	}, nil
+}
+
+func (s *S3Store) PresignUpload(ctx context.Context, key string, contentType string, size int64, expires time.Duration) (PresignedUpload, error) {
+	request, err := s.presigner.PresignPutObject(ctx, &s3.PutObjectInput{
+		Bucket:        aws.String(s.bucket),
+		Key:           aws.String(key),
+		ContentType:   aws.String(contentType),
+		ContentLength: aws.Int64(size),
+	}, s3.WithPresignExpires(expires))
+	if err != nil {
+		return PresignedUpload{}, err
+	}
+	return PresignedUpload{
+		URL:          request.URL,
+		Method:       request.Method,
+		SignedHeader: request.SignedHeader,
+	}, nil
+}
+
+func (s *S3Store) PresignDownload(ctx context.Context, key string, filename string, expires time.Duration) (string, error) {
+	request, err := s.presigner.PresignGetObject(ctx, &s3.GetObjectInput{
+		Bucket:                     aws.String(s.bucket),
+		Key:                        aws.String(key),
+		ResponseContentDisposition: aws.String(attachment(filename)),
+	}, s3.WithPresignExpires(expires))
+	if err != nil {
+		return "", err
+	}
+	return request.URL, nil
+}
+
+func (s *S3Store) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	object, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	var noSuchKey *types.NoSuchKey
+	if errors.As(err, &noSuchKey) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return object.Body, nil
+}
+
+func (s *S3Store) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	_, err := s.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(s.bucket),
+		Key:         aws.String(key),
+		Body:        bytes.NewReader(data),
+		ContentType: aws.String(contentType),
+	})
+	return err
+}
+
+func (s *S3Store) Delete(ctx context.Context, key string) error {
+	_, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	return err
+}
+
+func (s *S3Store) List(ctx context.Context, prefix string) ([]Object, error) {
+	var objects []Object
+	paginator := s3.NewListObjectsV2Paginator(s.client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(s.bucket),
+		Prefix: aws.String(prefix),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, object := range page.Contents {
+			objects = append(objects, Object{
+				Key:          aws.ToString(object.Key),
+				LastModified: aws.ToTime(object.LastModified),
+			})
+		}
+	}
+	return objects, nil
+}
+
+func (s *S3Store) URL(key string) string {
+	return s.urlPrefix() + key
+}
+
+func (s *S3Store) KeyOf(url string) (string, bool) {
+	key, ok := strings.CutPrefix(url, s.urlPrefix())
+	return key, ok && key != ""
+}
+
+func (s *S3Store) urlPrefix() string {
+	return "https://" + s.bucket + ".s3.amazonaws.com/"
+}
+
+type snsMessage struct {
+	Type      string `json:"Type"`
+	MessageId string `json:"MessageId"`
+	TopicArn  string `json:"TopicArn"`
+	Subject   string `json:"Subject"`
+	Message   string `json:"Message"`
+	Timestamp string `json:"Timestamp"`
+}
+
+type s3Event struct {
+	Records []struct {
+		EventName string `json:"eventName"`
+		S3        struct {
+			Bucket struct {
+				Name string `json:"name"`
+			} `json:"bucket"`
+			Object struct {
+				Key string `json:"key"`
+			} `json:"object"`
+		} `json:"s3"`
+	} `json:"Records"`
+}
+
+// The notification is the body of an SQS message holding an SNS notification of
+// an S3 event. Only puts to the store's bucket are returned.
+func (s *S3Store) CreatedKeys(notification string) ([]string, error) {
+	var snsMsg snsMessage
+	if err := json.Unmarshal([]byte(notification), &snsMsg); err != nil {
+		return nil, err
+	}
+	var event s3Event
+	if err := json.Unmarshal([]byte(snsMsg.Message), &event); err != nil {
+		return nil, err
+	}
+
+	var keys []string
+	for _, r := range event.Records {
+		if r.EventName != "ObjectCreated:Put" || r.S3.Bucket.Name != s.bucket {
+			log.Printf("Skipping event: %s", r.EventName)
+			continue
+		}
+		// Keys in S3 events are URL encoded, with spaces as +
+		key, err := url.QueryUnescape(r.S3.Object.Key)
+		if err != nil {
+			log.Printf("Invalid object key %q: %v", r.S3.Object.Key, err)
+			continue
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
@@ -0,0 +1,98 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"time"
+)
+
+var ErrNotFound = errors.New("object not found")
+
+// Where uploaded photos and their variants are kept. Clients upload straight to
+// the store with a presigned request, and the store reports the objects written
+// to it in notifications that CreatedKeys understands.
+type Store interface {
+	// PresignUpload returns a request the client can send to write key without
+	// credentials. The store rejects an upload that doesn't have contentType and
+	// exactly size bytes.
+	PresignUpload(ctx context.Context, key string, contentType string, size int64, expires time.Duration) (PresignedUpload, error)
+	// PresignDownload returns a URL that reads key without credentials until it
+	// expires, saved as filename by browsers. Unlike URL it works for objects
+	// that aren't public.
+	PresignDownload(ctx context.Context, key string, filename string, expires time.Duration) (string, error)
+	// Get returns ErrNotFound if there is no object at key
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+	// Deleting a key that doesn't exist isn't an error
+	Delete(ctx context.Context, key string) error
+	// List returns the objects whose keys start with prefix
+	List(ctx context.Context, prefix string) ([]Object, error)
+	// URL returns where key can be read publicly
+	URL(key string) string
+	// KeyOf is the inverse of URL, it reports false for urls outside the store
+	KeyOf(url string) (string, bool)
+	// CreatedKeys returns the keys of the objects a notification says were
+	// written to the store
+	CreatedKeys(notification string) ([]string, error)
+}
+
+type Object struct {
+	Key          string
+	LastModified time.Time
+}
+
+// Matches the JSON of the v4.PresignedHTTPRequest the API used to return, so
+// existing clients keep working
+type PresignedUpload struct {
+	URL          string
+	Method       string
+	SignedHeader http.Header
+}
+
+// Returns the store for the environment. Setting LOCAL_STORAGE_DIR runs photo
+// uploads against the local disk, with LOCAL_STORAGE_URL and
+// LOCAL_STORAGE_SECRET shared by the API and the upload handler. Otherwise the
+// S3 bucket PHOTO_BUCKET is used.
+func FromEnv(ctx context.Context) (Store, error) {
+	if dir := os.Getenv("LOCAL_STORAGE_DIR"); dir != "" {
+		baseURL := os.Getenv("LOCAL_STORAGE_URL")
+		if baseURL == "" {
+			baseURL = DefaultLocalURL
+		}
+		secret := os.Getenv("LOCAL_STORAGE_SECRET")
+		if secret == "" {
+			return nil, errors.New("LOCAL_STORAGE_SECRET must be set")
+		}
+		return NewLocalStore(dir, baseURL, secret)
+	}
+
+	bucket := os.Getenv("PHOTO_BUCKET")
+	if bucket == "" {
+		return nil, errors.New("PHOTO_BUCKET must be set")
+	}
+	return NewS3Store(ctx, bucket)
+}
+
+// Returns the store ticket exports are written to. They list attendees'
+// wallets, so on S3 they go to the private bucket EXPORT_BUCKET rather than the
+// public photo bucket. A local store keeps them alongside the photos.
+func ExportsFromEnv(ctx context.Context) (Store, error) {
+	if os.Getenv("LOCAL_STORAGE_DIR") != "" {
+		return FromEnv(ctx)
+	}
+
+	bucket := os.Getenv("EXPORT_BUCKET")
+	if bucket == "" {
+		return nil, errors.New("EXPORT_BUCKET must be set")
+	}
+	return NewS3Store(ctx, bucket)
+}
+
+// The Content-Disposition of a download saved as filename
+func attachment(filename string) string {
+	return mime.FormatMediaType("attachment", map[string]string{"filename": filename})
+}
@@ -0,0 +1,549 @@
+// Code generated by apps/api/spec/gen from the OpenAPI document. DO NOT EDIT.
+// Run go generate ./apps/api/spec after changing request or response types.
+
+export type AppEvent = {
+	Pk: number;
+	ID: string;
+	Vendor: number;
+	Venue: number;
+	Name: string;
+	Type: string;
+	EventDatetime: string;
+	EndDatetime: string;
+	Description: string;
+	Disclaimer: string | null;
+	Basecost: number;
+	NumUnique: number;
+	NumGa: number;
+	Photo: string | null;
+	PhotoThumbnail: string | null;
+	PhotoCard: string | null;
+	TransactionHash: string | null;
+	Version: number;
+	UpdatedAt: string;
+	CancelledAt: string | null;
+};
+
+export type AppMedia = {
+	Pk: number;
+	ID: string;
+	Vendor: number;
+	Event: number | null;
+	Venue: number | null;
+	Position: number;
+	Caption: string | null;
+	AltText: string | null;
+	Photo: string | null;
+	PhotoThumbnail: string | null;
+	PhotoCard: string | null;
+	IsCover: boolean;
+	CreatedAt: string;
+	Status: string;
+	ModerationReason: string | null;
+	PhotoHash: number | null;
+	ReviewedBy: string | null;
+	ReviewedAt: string | null;
+};
+
+export type AppVendor = {
+	Pk: number;
+	ID: string;
+	Wallet: string;
+	Name: string;
+};
+
+export type AppVenue = {
+	Pk: number;
+	ID: string;
+	Vendor: number;
+	Name: string;
+	StreetAddress: string;
+	Zip: string;
+	City: string;
+	StateCode: string;
+	StateName: string;
+	CountryCode: string;
+	CountryName: string;
+	NumUnique: number;
+	NumGa: number;
+	Photo: string | null;
+	PhotoThumbnail: string | null;
+	PhotoCard: string | null;
+	Latitude: number | null;
+	Longitude: number | null;
+	Version: number;
+	UpdatedAt: string;
+};
+
+export type DeleteVendorPhotoRequest = {
+	ID: string;
+};
+
+export type ErrorResponse = {
+	message: string;
+};
+
+export type EventCapacityPatchBodyParams = {
+	NumUnique: number;
+	NumGa: number;
+};
+
+export type EventOverlapResponse = {
+	message: string;
+	code: string;
+	events: VendorGetOverlappingEventsRow[];
+};
+
+export type EventPatchBodyParams = {
+	Pk: number;
+	Venue?: number;
+	Name?: string;
+	Type?: 'Concert' | 'Sporting Event' | 'Festival' | 'Conference/Seminar' | 'Other';
+	EventDatetime?: string;
+	EndDatetime?: string;
+	Description?: string;
+	Disclaimer?: string | null;
+	TransactionHash?: string | null;
+	AllowOverlap?: boolean;
+};
+
+export type EventPostBodyParams = {
+	Venue: number;
+	Name: string;
+	Type: 'Concert' | 'Sporting Event' | 'Festival' | 'Conference/Seminar' | 'Other';
+	EventDatetime: string;
+	EndDatetime: string;
+	Description: string;
+	Disclaimer: string;
+	Basecost: number;
+	NumUnique: number;
+	NumGa: number;
+	AllowOverlap?: boolean;
+};
+
+export type FieldError = {
+	field: string;
+	code: string;
+	message: string;
+};
+
+export type FieldErrorResponse = {
+	message: string;
+	code: string;
+	errors: FieldError[];
+};
+
+export type GalleryCoverPutBodyParams = {
+	ID: string;
+};
+
+export type GalleryOrderPutBodyParams = {
+	IDs: string[];
+};
+
+export type GalleryPhotoPatchBodyParams = {
+	Caption?: string | null;
+	AltText?: string | null;
+};
+
+export type ImportResponse = {
+	dryRun: boolean;
+	valid: number;
+	invalid: number;
+	rows: ImportRow[];
+};
+
+export type ImportRow = {
+	row: number;
+	status: string;
+	id: string | null;
+	message?: string;
+	code?: string;
+	errors?: FieldError[];
+};
+
+export type NotificationPreferencesPutBodyParams = {
+	Email?: string;
+	EmailEnabled: boolean;
+	PushEnabled: boolean;
+};
+
+export type PaginatedAppEvent = {
+	items: AppEvent[];
+	next_cursor: string;
+	total_count: number;
+};
+
+export type PaginatedAppVenue = {
+	items: AppVenue[];
+	next_cursor: string;
+	total_count: number;
+};
+
+export type PaginatedUserGetEventsPaginatedRow = {
+	items: UserGetEventsPaginatedRow[];
+	next_cursor: string;
+	total_count: number;
+};
+
+export type PhotoReviewPutBodyParams = {
+	Status: 'approved' | 'rejected';
+	Reason?: string;
+	Block?: boolean;
+};
+
+export type PostPatchVendorIdRequestBody = {
+	Name: string;
+};
+
+export type PostVendorPhotoRequest = {
+	ID: string;
+	Filename?: string;
+	ContentType: 'image/jpeg' | 'image/png' | 'image/gif' | 'image/webp';
+	Size: number;
+	Caption?: string;
+	AltText?: string;
+};
+
+export type PostVendorPhotoResponse = {
+	Request: PresignedUpload;
+	ObjectKey: string;
+	MediaID: string;
+};
+
+export type PresignedUpload = {
+	URL: string;
+	Method: string;
+	SignedHeader: Record<string, string[]>;
+};
+
+export type PushTokenBodyParams = {
+	Token: string;
+};
+
+export type TicketCheckBodyParams = {
+	Event: string;
+	TicketID: number;
+};
+
+export type TicketCreatePostBodyParams = {
+	Event: string;
+	Contract: string;
+	TicketMin: number;
+	TicketMax: number;
+};
+
+export type UserEventPage = {
+	Eventname: string;
+	Type: string;
+	EventDatetime: string;
+	EndDatetime: string;
+	ID: string;
+	Description: string;
+	Disclaimer: string | null;
+	Basecost: number;
+	NumUnique: number;
+	NumGa: number;
+	CancelledAt: string | null;
+	Eventphoto: string | null;
+	Venuename: string;
+	StreetAddress: string;
+	Zip: string;
+	City: string;
+	StateCode: string;
+	CountryCode: string;
+	CountryName: string;
+	Venuephoto: string | null;
+	Vendorname: string;
+	Gallery: AppMedia[];
+};
+
+export type UserGetEventsPaginatedRow = {
+	Pk: number;
+	Name: string;
+	Type: string;
+	EventDatetime: string;
+	Venuename: string;
+	StateCode: string;
+	CountryCode: string;
+	Photo: string | null;
+	ID: string;
+	Rank: number;
+	Snippet: string;
+	Distance: number;
+};
+
+export type V1AuditEntry = {
+	table: string;
+	action: string;
+	actor: string | null;
+	before: unknown;
+	after: unknown;
+	changedAt: string;
+};
+
+export type V1CalendarEntry = {
+	eventId: string;
+	name: string;
+	start: string;
+	end: string;
+};
+
+export type V1Event = {
+	id: string;
+	venueId: string;
+	name: string;
+	type: string;
+	eventDatetime: string;
+	endDatetime: string;
+	description: string;
+	disclaimer: string | null;
+	basecost: number;
+	numUnique: number;
+	numGa: number;
+	photo: string | null;
+	photoThumbnail: string | null;
+	photoCard: string | null;
+	transactionHash: string | null;
+	version: number;
+	updatedAt: string;
+	cancelledAt: string | null;
+};
+
+export type V1EventAnalytics = {
+	eventId: string;
+	venueId: string;
+	name: string;
+	eventDatetime: string;
+	endDatetime: string;
+	basecost: number;
+	tickets: V1TicketStats;
+};
+
+export type V1EventDetails = {
+	id: string;
+	name: string;
+	type: string;
+	eventDatetime: string;
+	endDatetime: string;
+	description: string;
+	disclaimer: string | null;
+	basecost: number;
+	numUnique: number;
+	numGa: number;
+	photo: string | null;
+	vendorName: string;
+	venue: V1VenueAddress;
+	gallery: V1Media[];
+	cancelledAt: string | null;
+};
+
+export type V1EventSummary = {
+	id: string;
+	name: string;
+	type: string;
+	eventDatetime: string;
+	venueName: string;
+	stateCode: string;
+	countryCode: string;
+	photo: string | null;
+	snippet: string;
+	distance: number | null;
+};
+
+export type V1Gallery = {
+	photos: V1Media[];
+};
+
+export type V1HourlyAnalytics = {
+	hour: string;
+	sold: number;
+	checkedIn: number;
+};
+
+export type V1Media = {
+	id: string;
+	position: number;
+	caption: string | null;
+	altText: string | null;
+	photo: string | null;
+	photoThumbnail: string | null;
+	photoCard: string | null;
+	cover: boolean;
+	status: string;
+	moderationReason: string | null;
+	createdAt: string;
+};
+
+export type V1NotificationPreferences = {
+	email: string | null;
+	emailEnabled: boolean;
+	pushEnabled: boolean;
+};
+
+export type V1PageAuditEntry = {
+	items: V1AuditEntry[];
+	nextCursor: string | null;
+	totalCount: number;
+};
+
+export type V1PageEvent = {
+	items: V1Event[];
+	nextCursor: string | null;
+	totalCount: number;
+};
+
+export type V1PageEventSummary = {
+	items: V1EventSummary[];
+	nextCursor: string | null;
+	totalCount: number;
+};
+
+export type V1PageMedia = {
+	items: V1Media[];
+	nextCursor: string | null;
+	totalCount: number;
+};
+
+export type V1PageVenue = {
+	items: V1Venue[];
+	nextCursor: string | null;
+	totalCount: number;
+};
+
+export type V1TicketExport = {
+	id: string;
+	format: string;
+	status: string;
+	error: string | null;
+	downloadUrl: string | null;
+	createdAt: string;
+	completedAt: string | null;
+};
+
+export type V1TicketStats = {
+	minted: number;
+	sold: number;
+	checkedIn: number;
+	noShowRate: number | null;
+	revenue: number;
+};
+
+export type V1Vendor = {
+	id: string;
+	name: string;
+	wallet: string;
+};
+
+export type V1VendorAnalytics = {
+	from: string | null;
+	to: string | null;
+	totals: V1TicketStats;
+	venues: V1VenueAnalytics[];
+	events: V1EventAnalytics[];
+	hourly: V1HourlyAnalytics[];
+};
+
+export type V1Venue = {
+	id: string;
+	name: string;
+	streetAddress: string;
+	zip: string;
+	city: string;
+	stateCode: string;
+	stateName: string;
+	countryCode: string;
+	countryName: string;
+	numUnique: number;
+	numGa: number;
+	photo: string | null;
+	photoThumbnail: string | null;
+	photoCard: string | null;
+	latitude: number | null;
+	longitude: number | null;
+	version: number;
+	updatedAt: string;
+};
+
+export type V1VenueAddress = {
+	name: string;
+	streetAddress: string;
+	zip: string;
+	city: string;
+	stateCode: string;
+	countryCode: string;
+	countryName: string;
+	photo: string | null;
+};
+
+export type V1VenueAnalytics = {
+	venueId: string;
+	name: string;
+	tickets: V1TicketStats;
+};
+
+export type V1VenueCalendar = {
+	from: string;
+	to: string;
+	events: V1CalendarEntry[];
+};
+
+export type V1VenueSummary = {
+	id: string;
+	name: string;
+};
+
+export type VendorGetAllVenuesRow = {
+	Pk: number;
+	ID: string;
+	Name: string;
+};
+
+export type VendorGetOverlappingEventsRow = {
+	Pk: number;
+	ID: string;
+	Name: string;
+	EventDatetime: string;
+	EndDatetime: string;
+};
+
+export type VendorGetVenueCapacityConflictsRow = {
+	Pk: number;
+	ID: string;
+	Name: string;
+	EventDatetime: string;
+	NumUnique: number;
+	NumGa: number;
+};
+
+export type VenueCapacityConflictResponse = {
+	message: string;
+	code: string;
+	events: VendorGetVenueCapacityConflictsRow[];
+};
+
+export type VenueCapacityPatchBodyParams = {
+	NumUnique: number;
+	NumGa: number;
+};
+
+export type VenuePatchBodyParams = {
+	Pk: number;
+	Name?: string;
+	StreetAddress?: string;
+	Zip?: string;
+	City?: string;
+	StateCode?: string;
+	CountryCode?: string;
+};
+
+export type VenuePostBodyParams = {
+	Name: string;
+	StreetAddress: string;
+	Zip?: string;
+	City: string;
+	StateCode: string;
+	CountryCode: string;
+	NumUnique: number;
+	NumGa: number;
+};
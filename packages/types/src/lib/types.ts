@@ -1,110 +1,48 @@
-export type Event = {
-	Pk: number;
-	ID: string;
-	Vendor: number;
-	Venue: number;
-	Name: string;
-	Type: string;
-	EventDatetime: string;
-	Description: string;
-	Disclaimer: string;
-	Basecost: number;
-	NumUnique: number;
-	NumGa: number;
-	Photo: string;
-	TransactionHash: string;
-};
+import type {
+	AppEvent,
+	AppVenue,
+	EventPostBodyParams,
+	FieldErrorResponse,
+	UserEventPage,
+	UserGetEventsPaginatedRow,
+	VendorGetAllVenuesRow,
+	VenuePatchBodyParams,
+	VenuePostBodyParams
+} from './api.gen';
 
-export type Venue = {
-	Pk: number;
-	ID: string;
-	Vendor: number;
-	Name: string;
-	StreetAddress: string;
-	Zip: string;
-	City: string;
-	StateCode: string;
-	StateName: string;
-	CountryCode: string;
-	CountryName: string;
-	NumUnique: number;
-	NumGa: number;
-	Photo: string;
-};
+// The API's own types are generated from the Go handlers into api.gen.ts, these
+// are the names the apps use for them.
+export type Event = AppEvent;
 
-export type UserEventResponse = Pick<
-	Event,
-	'Name' | 'Type' | 'EventDatetime' | 'Photo' | 'ID'
-> &
-	Pick<Venue, 'StateCode' | 'CountryCode'> & {
-		Venuename: string;
-	};
-
-export type UserEventDetailsResponse = Omit<
-	Event,
-	'Pk' | 'Vendor' | 'Venue' | 'TransactionHash' | 'Name' | 'Photo'
-> &
-	Pick<
-		Venue,
-		| 'StreetAddress'
-		| 'Zip'
-		| 'City'
-		| 'CountryCode'
-		| 'StateCode'
-		| 'CountryName'
-	> & {
-		Vendorname: string;
-		Venuename: string;
-		Venuephoto: string;
-		Eventname: string;
-		Eventphoto: string;
-	};
-
-export type EventCreationFormData = Pick<
-	Event,
-	| 'Venue'
-	| 'Name'
-	| 'Type'
-	| 'EventDatetime'
-	| 'Description'
-	| 'Disclaimer'
-	| 'Basecost'
-	| 'NumUnique'
-	| 'NumGa'
->;
+export type Venue = AppVenue;
 
-export type VenueCreationFormData = Pick<
-	Venue,
-	| 'Name'
-	| 'StreetAddress'
-	| 'Zip'
-	| 'City'
-	| 'StateCode'
-	| 'StateName'
-	| 'CountryCode'
-	| 'CountryName'
-	| 'NumUnique'
-	| 'NumGa'
->;
+export type UserEventResponse = UserGetEventsPaginatedRow;
+
+export type UserEventDetailsResponse = UserEventPage;
+
+// Forms hold the event type as a plain string until it is validated
+export type EventCreationFormData = Omit<EventPostBodyParams, 'Type'> & {
+	Type: string;
+};
 
+export type VenueCreationFormData = VenuePostBodyParams;
+
+// Edits never clear the disclaimer, so unlike the patch body it isn't nullable
 export type EventEditableFields = Pick<
-	Event,
-	'Type' | 'Description' | 'Disclaimer'
->;
+	EventPostBodyParams,
+	'Description' | 'Disclaimer'
+> & {
+	Type: string;
+};
 
-export type VenueEditableFields = Pick<
-	Venue,
-	| 'Name'
-	| 'StreetAddress'
-	| 'Zip'
-	| 'City'
-	| 'StateCode'
-	| 'StateName'
-	| 'CountryCode'
-	| 'CountryName'
+export type VenueEditableFields = Required<
+	Pick<
+		VenuePatchBodyParams,
+		'Name' | 'StreetAddress' | 'Zip' | 'City' | 'StateCode' | 'CountryCode'
+	>
 >;
 
-export type AllVenuesListSimplifiedResponse = Pick<Venue, 'Pk' | 'ID' | 'Name'>;
+export type AllVenuesListSimplifiedResponse = VendorGetAllVenuesRow;
 
 export type EventTypes =
 	| 'Concert'
@@ -129,13 +67,18 @@ const EVENT_DEFAULT_DO_NOT_USE: Event = {
 	Name: '',
 	Type: '',
 	EventDatetime: '',
+	EndDatetime: '',
 	Description: '',
 	Disclaimer: '',
 	Basecost: 0,
 	NumUnique: 0,
 	NumGa: 0,
 	Photo: '',
-	TransactionHash: ''
+	PhotoThumbnail: '',
+	PhotoCard: '',
+	TransactionHash: '',
+	Version: 0,
+	UpdatedAt: ''
 };
 
 const VENUE_DEFAULT_DO_NOT_USE: Venue = {
@@ -152,7 +95,13 @@ const VENUE_DEFAULT_DO_NOT_USE: Venue = {
 	CountryName: '',
 	NumUnique: 0,
 	NumGa: 0,
-	Photo: ''
+	Photo: '',
+	PhotoThumbnail: '',
+	PhotoCard: '',
+	Latitude: null,
+	Longitude: null,
+	Version: 0,
+	UpdatedAt: ''
 };
 
 export const EVENT_KEYS = Object.keys(
@@ -161,3 +110,21 @@ export const EVENT_KEYS = Object.keys(
 export const VENUE_KEYS = Object.keys(
 	VENUE_DEFAULT_DO_NOT_USE
 ) as (keyof Venue)[];
+
+// Maps a FieldErrorResponse to field name -> message, keeping the first error
+// reported for each field.
+export function getFieldErrors(
+	data: Partial<FieldErrorResponse>
+): Record<string, string> {
+	const errors: Record<string, string> = {};
+	data?.errors?.forEach((e) => {
+		if (!(e.field in errors)) errors[e.field] = e.message;
+	});
+	return errors;
+}
+
+export type PaginatedResponse<T> = {
+	items: T[];
+	next_cursor: string;
+	total_count: number;
+};
@@ -4,5 +4,6 @@ export * from './lib/Dropdown';
 export * from './lib/error';
 export * from './lib/BaseModalForm';
 export * from './lib/SuccessAlert';
+export * from './lib/FieldErrorText';
 export * from './lib/ViewingOnMobile';
 export * from './lib/FullscreenLoadingMessage';
@@ -0,0 +1,15 @@
+import '@testing-library/jest-dom';
+import { render, screen } from '@testing-library/react';
+import { FieldErrorText } from './FieldErrorText';
+
+describe('FieldErrorText', () => {
+	it('renders the message', () => {
+		render(<FieldErrorText message="Name is required" />);
+		expect(screen.getByText('Name is required')).toBeInTheDocument();
+	});
+
+	it('renders nothing without a message', () => {
+		const { container } = render(<FieldErrorText />);
+		expect(container).toBeEmptyDOMElement();
+	});
+});
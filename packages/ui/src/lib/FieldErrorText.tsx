@@ -0,0 +1,14 @@
+import { Text } from '@radix-ui/themes';
+
+export interface FieldErrorTextProps {
+	message?: string;
+}
+
+export function FieldErrorText({ message }: FieldErrorTextProps) {
+	if (!message) return null;
+	return (
+		<Text as="div" size="1" color="red" mt="1">
+			{message}
+		</Text>
+	);
+}